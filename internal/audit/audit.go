@@ -0,0 +1,209 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+)
+
+// Action identifies what happened to a key
+type Action string
+
+const (
+	ActionReveal     Action = "reveal"     // Full key printed (aim keys show)
+	ActionAdd        Action = "add"        // Key added (aim keys add, aim keys receive)
+	ActionRemove     Action = "remove"     // Key removed
+	ActionRename     Action = "rename"     // Key renamed
+	ActionShare      Action = "share"      // Key encrypted for a teammate
+	ActionCredential Action = "credential" // Key handed to a tool via credential helper
+	ActionInstall    Action = "install"    // Key written into a tool config (aim setup install)
+	ActionRun        Action = "run"        // Key passed to a child process (aim run)
+)
+
+// Actions lists every known action, for help text and validation
+var Actions = []Action{ActionReveal, ActionAdd, ActionRemove, ActionRename, ActionShare, ActionCredential, ActionInstall, ActionRun}
+
+// Entry is a single audit record. It carries key names only, never key values.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Action   Action    `json:"action"`
+	Key      string    `json:"key"`
+	Tool     string    `json:"tool,omitempty"`
+	Provider string    `json:"provider,omitempty"`
+	Cwd      string    `json:"cwd,omitempty"`
+	PID      int       `json:"pid"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+// Filter selects entries when querying the log. Zero values match everything.
+type Filter struct {
+	Key    string
+	Action Action
+	Tool   string
+	Since  time.Time
+	Until  time.Time
+}
+
+// Match reports whether an entry passes the filter
+func (f *Filter) Match(e *Entry) bool {
+	if f.Key != "" && e.Key != f.Key {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	if f.Tool != "" && e.Tool != f.Tool {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Log is an append-only JSONL audit log, rotated once it exceeds maxSize
+type Log struct {
+	path    string
+	maxSize int64
+}
+
+// NewLog creates an audit log at the default location (~/.aim/audit.jsonl)
+func NewLog() *Log {
+	return NewLogWithPath(filepath.Join(config.GetAIMHome(), "audit.jsonl"), constants.AuditLogMaxSize)
+}
+
+// NewLogWithPath creates an audit log at a custom path with a custom size cap
+func NewLogWithPath(path string, maxSize int64) *Log {
+	return &Log{path: path, maxSize: maxSize}
+}
+
+// Path returns the current log file path
+func (l *Log) Path() string {
+	return l.path
+}
+
+// rotatedPath returns the path of the previous (rotated) log file
+func (l *Log) rotatedPath() string {
+	return l.path + ".1"
+}
+
+// Append writes an entry, filling in time, cwd and pid when unset
+func (l *Log) Append(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Cwd == "" {
+		e.Cwd, _ = os.Getwd()
+	}
+	if e.PID == 0 {
+		e.PID = os.Getpid()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	if err := l.rotateIfNeeded(int64(len(data) + 1)); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.AuditFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// rotateIfNeeded moves the current log aside when the next write would exceed the cap
+// Only one rotated file is kept, so the log never uses more than twice maxSize
+func (l *Log) rotateIfNeeded(incoming int64) error {
+	if l.maxSize <= 0 {
+		return nil
+	}
+
+	info, err := os.Stat(l.path)
+	if err != nil || info.Size()+incoming <= l.maxSize {
+		return nil
+	}
+
+	if err := os.Rename(l.path, l.rotatedPath()); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return nil
+}
+
+// Query returns matching entries from the rotated and current log, oldest first
+func (l *Log) Query(filter Filter) ([]Entry, error) {
+	var entries []Entry
+
+	for _, path := range []string{l.rotatedPath(), l.path} {
+		fileEntries, err := readEntries(path, &filter)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// readEntries reads matching entries from one JSONL file, skipping malformed lines
+func readEntries(path string, filter *Filter) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if filter.Match(&e) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+// Record appends an entry to the default log
+// Audit failures never block the audited operation; they are reported on stderr
+func Record(action Action, key, tool, provider, detail string) {
+	err := NewLog().Append(Entry{
+		Action:   action,
+		Key:      key,
+		Tool:     tool,
+		Provider: provider,
+		Detail:   detail,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
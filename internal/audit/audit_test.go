@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAppendsJSONL(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("AIM_HOME", home)
+
+	Record(ActionReveal, "work", "", "deepseek", "")
+	Record(ActionRun, "work", "codex", "deepseek", "profile deepseek")
+
+	path := filepath.Join(home, "audit.jsonl")
+	if got := NewLog().Path(); got != path {
+		t.Fatalf("log path = %s, want %s", got, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("mode = %o, want 600", mode)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"action":"run"`) || !strings.Contains(lines[1], `"tool":"codex"`) {
+		t.Errorf("log = %s", data)
+	}
+
+	entries, err := NewLog().Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	run := entries[1]
+	if run.Action != ActionRun || run.Detail != "profile deepseek" || run.PID != os.Getpid() || run.Cwd == "" || run.Time.IsZero() {
+		t.Errorf("entry = %+v, want time, cwd and pid filled in", run)
+	}
+}
+
+func TestLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewLogWithPath(path, 300)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		if err := log.Append(Entry{Time: base.Add(time.Duration(i) * time.Minute), Action: ActionRun, Key: "work", Cwd: "/w", PID: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, p := range []string{path, path + ".1"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 300 {
+			t.Errorf("%s is %d bytes, over the 300 byte cap", p, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Error("more than one rotated file was kept")
+	}
+
+	// Entries come back oldest first across the rotated and current files, ending with the latest
+	entries, err := log.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) >= 10 {
+		t.Fatalf("got %d entries, want some dropped by rotation", len(entries))
+	}
+	for i := 1; i < len(entries); i++ {
+		if !entries[i-1].Time.Before(entries[i].Time) {
+			t.Errorf("entries out of order: %v before %v", entries[i-1].Time, entries[i].Time)
+		}
+	}
+	if last := entries[len(entries)-1].Time; !last.Equal(base.Add(9 * time.Minute)) {
+		t.Errorf("last entry at %v, want the latest", last)
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewLogWithPath(path, 0)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: base, Action: ActionAdd, Key: "work"},
+		{Time: base.Add(time.Hour), Action: ActionRun, Key: "work", Tool: "codex"},
+		{Time: base.Add(2 * time.Hour), Action: ActionRun, Key: "home", Tool: "claude-code"},
+		{Time: base.Add(3 * time.Hour), Action: ActionReveal, Key: "home"},
+	}
+	for _, e := range entries {
+		if err := log.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Malformed lines are skipped rather than failing the query
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	f.Close()
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int // Number of matching entries
+	}{
+		{"all", Filter{}, 4},
+		{"key", Filter{Key: "work"}, 2},
+		{"action", Filter{Action: ActionRun}, 2},
+		{"tool", Filter{Tool: "codex"}, 1},
+		{"since inclusive", Filter{Since: base.Add(time.Hour)}, 3},
+		{"until exclusive", Filter{Until: base.Add(time.Hour)}, 1},
+		{"window", Filter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}, 2},
+		{"combined", Filter{Key: "home", Action: ActionRun}, 1},
+		{"no match", Filter{Key: "missing"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := log.Query(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Query(%+v) = %d entries, want %d", tt.filter, len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMissingLog(t *testing.T) {
+	entries, err := NewLogWithPath(filepath.Join(t.TempDir(), "audit.jsonl"), 0).Query(Filter{})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Query() = %v, %v, want no entries", entries, err)
+	}
+}
@@ -0,0 +1,130 @@
+package bench
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fakecore/aim/internal/probe"
+)
+
+// Options controls one benchmark run against a single target
+type Options struct {
+	Requests    int           // Number of streaming requests to send
+	Concurrency int           // Requests in flight at once
+	Prompt      string        // Prompt sent with every request
+	MaxTokens   int           // Output token budget per request
+	Timeout     time.Duration // Timeout per request
+}
+
+// Summary aggregates the results of a benchmark run
+type Summary struct {
+	Requests     int
+	Errors       int
+	ErrorRate    float64
+	TTFTP50      time.Duration
+	TTFTP90      time.Duration
+	LatencyP50   time.Duration
+	LatencyP90   time.Duration
+	LatencyP99   time.Duration
+	TokensPerSec float64
+	ErrorClasses map[string]int
+	LastError    string
+	Results      []probe.StreamResult
+}
+
+// Run sends opts.Requests streaming requests to the target and summarizes them
+func Run(ctx context.Context, prober *probe.Prober, target probe.Target, opts Options) Summary {
+	if opts.Requests < 1 {
+		opts.Requests = 1
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	results := make([]probe.StreamResult, opts.Requests)
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Requests; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			reqCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+
+			results[i] = prober.Stream(reqCtx, target, opts.Prompt, opts.MaxTokens)
+		}(i)
+	}
+	wg.Wait()
+
+	return Summarize(results)
+}
+
+// Summarize computes percentiles, throughput and error rate from individual results
+// Latency and TTFT percentiles only include successful requests
+func Summarize(results []probe.StreamResult) Summary {
+	summary := Summary{Requests: len(results), Results: results}
+
+	var ttfts, latencies []time.Duration
+	var throughput float64
+	var throughputSamples int
+
+	for _, r := range results {
+		if !r.OK() {
+			summary.Errors++
+			if summary.ErrorClasses == nil {
+				summary.ErrorClasses = make(map[string]int)
+			}
+			summary.ErrorClasses[string(r.Class)]++
+			summary.LastError = r.Message
+			continue
+		}
+		ttfts = append(ttfts, r.TTFT)
+		latencies = append(latencies, r.Latency)
+		if tps := r.TokensPerSecond(); tps > 0 {
+			throughput += tps
+			throughputSamples++
+		}
+	}
+
+	if summary.Requests > 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.Requests)
+	}
+	if throughputSamples > 0 {
+		summary.TokensPerSec = throughput / float64(throughputSamples)
+	}
+
+	summary.TTFTP50 = Percentile(ttfts, 50)
+	summary.TTFTP90 = Percentile(ttfts, 90)
+	summary.LatencyP50 = Percentile(latencies, 50)
+	summary.LatencyP90 = Percentile(latencies, 90)
+	summary.LatencyP99 = Percentile(latencies, 99)
+
+	return summary
+}
+
+// Percentile returns the nearest-rank percentile of the samples (0 when empty)
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
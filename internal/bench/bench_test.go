@@ -0,0 +1,132 @@
+package bench
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fakecore/aim/internal/probe"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		samples := make([]time.Duration, len(values))
+		for i, v := range values {
+			samples[i] = time.Duration(v) * time.Millisecond
+		}
+		return samples
+	}
+
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		p       float64
+		want    time.Duration
+	}{
+		{"empty", nil, 50, 0},
+		{"single p50", ms(7), 50, 7 * time.Millisecond},
+		{"single p99", ms(7), 99, 7 * time.Millisecond},
+		{"unsorted p50", ms(30, 10, 20), 50, 20 * time.Millisecond},
+		{"even count p50", ms(40, 10, 30, 20), 50, 20 * time.Millisecond},
+		{"p90 of ten", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 90, 9 * time.Millisecond},
+		{"p99 of ten", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 99, 10 * time.Millisecond},
+		{"p0 is the minimum", ms(5, 3, 9), 0, 3 * time.Millisecond},
+		{"p100 is the maximum", ms(5, 3, 9), 100, 9 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := append([]time.Duration(nil), tt.samples...)
+			if got := Percentile(tt.samples, tt.p); got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %v, want %v", tt.samples, tt.p, got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.samples, before) {
+				t.Errorf("Percentile reordered its input: %v", tt.samples)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	ok := func(ttft, latency time.Duration, tokens int) probe.StreamResult {
+		return probe.StreamResult{StatusCode: 200, TTFT: ttft, Latency: latency, OutputTokens: tokens}
+	}
+
+	tests := []struct {
+		name    string
+		results []probe.StreamResult
+		want    Summary
+	}{
+		{
+			name: "empty",
+			want: Summary{},
+		},
+		{
+			name:    "single success",
+			results: []probe.StreamResult{ok(100*time.Millisecond, 1100*time.Millisecond, 50)},
+			want: Summary{
+				Requests:     1,
+				TTFTP50:      100 * time.Millisecond,
+				TTFTP90:      100 * time.Millisecond,
+				LatencyP50:   1100 * time.Millisecond,
+				LatencyP90:   1100 * time.Millisecond,
+				LatencyP99:   1100 * time.Millisecond,
+				TokensPerSec: 50,
+			},
+		},
+		{
+			name: "single failure",
+			results: []probe.StreamResult{
+				{StatusCode: 429, Class: probe.ClassRateLimit, Message: "slow down"},
+			},
+			want: Summary{
+				Requests:     1,
+				Errors:       1,
+				ErrorRate:    1,
+				ErrorClasses: map[string]int{"rate_limit": 1},
+				LastError:    "slow down",
+			},
+		},
+		{
+			name: "mixed",
+			results: []probe.StreamResult{
+				ok(100*time.Millisecond, 1100*time.Millisecond, 100),
+				{StatusCode: 500, Class: probe.ClassServer, Message: "first"},
+				ok(300*time.Millisecond, 2300*time.Millisecond, 100),
+				// No token count: excluded from throughput, still counted in latency
+				ok(200*time.Millisecond, 500*time.Millisecond, 0),
+				{Class: probe.ClassNetwork, Message: "timeout"},
+			},
+			want: Summary{
+				Requests:     5,
+				Errors:       2,
+				ErrorRate:    0.4,
+				TTFTP50:      200 * time.Millisecond,
+				TTFTP90:      300 * time.Millisecond,
+				LatencyP50:   1100 * time.Millisecond,
+				LatencyP90:   2300 * time.Millisecond,
+				LatencyP99:   2300 * time.Millisecond,
+				TokensPerSec: 75, // mean of 100 and 50 tokens/s
+				ErrorClasses: map[string]int{"server": 1, "network": 1},
+				LastError:    "timeout",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Summarize(tt.results)
+			if len(got.Results) != len(tt.results) {
+				t.Errorf("Results has %d entries, want %d", len(got.Results), len(tt.results))
+			}
+			got.Results = nil
+			if math.Abs(got.TokensPerSec-tt.want.TokensPerSec) < 1e-9 {
+				got.TokensPerSec = tt.want.TokensPerSec
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the key access audit log",
+	Long: `Query the append-only audit log of key reveals, additions, removals,
+setup installs and runs. Entries record key names only, never key values.
+
+The log lives at ~/.aim/audit.jsonl and is rotated once it exceeds 5 MiB.
+
+Examples:
+  # Show recent activity
+  aim audit
+
+  # Who revealed the GLM key in the last week?
+  aim audit --key glm-shared --action reveal --since 7d
+
+  # All runs on a given day, as JSON
+  aim audit --action run --since 2026-02-01 --until 2026-02-02 --json`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().String("key", "", "Only show entries for this key")
+	auditCmd.Flags().String("action", "", "Only show this action: "+joinActions())
+	auditCmd.Flags().String("tool", "", "Only show entries for this tool")
+	auditCmd.Flags().String("since", "", "Only show entries at or after this time (YYYY-MM-DD, RFC3339, or a duration like 24h or 7d)")
+	auditCmd.Flags().String("until", "", "Only show entries before this time (same formats as --since)")
+	auditCmd.Flags().Int("limit", 50, "Show at most this many of the most recent entries (0 for all)")
+	auditCmd.Flags().Bool("json", false, "Output entries as JSON lines")
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	keyName, _ := cmd.Flags().GetString("key")
+	action, _ := cmd.Flags().GetString("action")
+	toolName, _ := cmd.Flags().GetString("tool")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	limit, _ := cmd.Flags().GetInt("limit")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	filter := audit.Filter{
+		Key:    keyName,
+		Action: audit.Action(action),
+		Tool:   toolName,
+	}
+
+	if action != "" && !isKnownAction(filter.Action) {
+		return fmt.Errorf("unknown action '%s'. Valid actions: %s", action, joinActions())
+	}
+
+	var err error
+	if since != "" {
+		if filter.Since, err = parseAuditTime(since); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if filter.Until, err = parseAuditTime(until); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	log := audit.NewLog()
+	entries, err := log.Query(filter)
+	if err != nil {
+		return err
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	if jsonOutput {
+		for _, entry := range entries {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		}
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No matching audit entries.")
+		fmt.Printf("  Log: %s\n", log.Path())
+		return nil
+	}
+
+	fmt.Printf("%-20s %-10s %-20s %-12s %-8s %s\n", "TIME", "ACTION", "KEY", "TOOL", "PID", "CWD")
+	for _, entry := range entries {
+		fmt.Printf("%-20s %-10s %-20s %-12s %-8d %s\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Action,
+			entry.Key,
+			valueOrDash(entry.Tool),
+			entry.PID,
+			entry.Cwd)
+		if entry.Detail != "" && verbose {
+			fmt.Printf("%-20s %s\n", "", entry.Detail)
+		}
+	}
+
+	return nil
+}
+
+// parseAuditTime parses an absolute date/time or a relative duration ago
+func parseAuditTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	// Relative durations: Go durations plus a "d" suffix for days
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil {
+			return time.Now().AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time '%s'", value)
+}
+
+// isKnownAction reports whether an action is recorded by aim
+func isKnownAction(action audit.Action) bool {
+	for _, a := range audit.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// joinActions returns all actions as a comma-separated list
+func joinActions() string {
+	names := make([]string, len(audit.Actions))
+	for i, a := range audit.Actions {
+		names[i] = string(a)
+	}
+	return strings.Join(names, ", ")
+}
+
+// valueOrDash returns "-" for empty table cells
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
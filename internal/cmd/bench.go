@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fakecore/aim/internal/bench"
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/probe"
+	"github.com/fakecore/aim/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [key-name...]",
+	Short: "Benchmark latency and throughput across keys and providers",
+	Long: `Send N streaming requests per resolved profile and compare providers.
+
+Each key is resolved exactly like 'aim run' resolves it, so the benchmark
+measures the endpoint, model and key a tool would actually use. Reported
+metrics are time to first token (TTFT), output tokens per second, total
+latency percentiles and error rate.
+
+Examples:
+  # Benchmark the default key against claude-code
+  aim bench
+
+  # Compare several keys, 10 requests each, 2 at a time
+  aim bench deepseek-work glm-work kimi-work -n 10 --concurrency 2
+
+  # Benchmark every key against codex and export CSV
+  aim bench --all --tool codex --format csv > bench.csv`,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().Bool("all", false, "Benchmark all configured keys")
+	benchCmd.Flags().String("tool", "claude-code", "Tool whose profiles are benchmarked")
+	benchCmd.Flags().IntP("requests", "n", 5, "Number of requests per key")
+	benchCmd.Flags().Int("concurrency", 1, "Requests in flight at once per key")
+	benchCmd.Flags().Int("max-tokens", 128, "Output token budget per request")
+	benchCmd.Flags().String("prompt", "Count from 1 to 50, separated by spaces.", "Prompt sent with every request")
+	benchCmd.Flags().Duration("timeout", 60*time.Second, "Timeout for each request")
+	benchCmd.Flags().String("format", "table", "Output format: table, csv, json")
+}
+
+// benchRow is one line of benchmark output
+type benchRow struct {
+	Key          string         `json:"key"`
+	Tool         string         `json:"tool"`
+	Provider     string         `json:"provider,omitempty"`
+	Model        string         `json:"model,omitempty"`
+	Requests     int            `json:"requests"`
+	Errors       int            `json:"errors"`
+	ErrorRate    float64        `json:"error_rate"`
+	TTFTP50MS    int64          `json:"ttft_p50_ms"`
+	TTFTP90MS    int64          `json:"ttft_p90_ms"`
+	LatencyP50MS int64          `json:"latency_p50_ms"`
+	LatencyP90MS int64          `json:"latency_p90_ms"`
+	LatencyP99MS int64          `json:"latency_p99_ms"`
+	TokensPerSec float64        `json:"tokens_per_sec"`
+	ErrorClasses map[string]int `json:"error_classes,omitempty"`
+	LastError    string         `json:"last_error,omitempty"`
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	benchAll, _ := cmd.Flags().GetBool("all")
+	toolName, _ := cmd.Flags().GetString("tool")
+	requests, _ := cmd.Flags().GetInt("requests")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+	prompt, _ := cmd.Flags().GetString("prompt")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	format, _ := cmd.Flags().GetString("format")
+
+	if format != "table" && format != "csv" && format != "json" {
+		return fmt.Errorf("unsupported format '%s'. Supported formats: table, csv, json", format)
+	}
+	if requests < 1 {
+		return fmt.Errorf("--requests must be at least 1")
+	}
+
+	cfg := config.GetConfigManager().GetConfig()
+	resolver := config.NewResolver(cfg)
+
+	toolName = tool.GetCanonicalName(toolName)
+	protocol, ok := tool.GetProtocol(toolName)
+	if !ok {
+		return fmt.Errorf("tool '%s' does not support benchmarking", toolName)
+	}
+	if _, exists := cfg.GetTool(toolName); !exists {
+		return fmt.Errorf("tool '%s' not found", toolName)
+	}
+
+	var keys []string
+	switch {
+	case benchAll:
+		for keyName := range cfg.Keys {
+			keys = append(keys, keyName)
+		}
+		sort.Strings(keys)
+	case len(args) > 0:
+		for _, keyName := range args {
+			if _, exists := cfg.GetKey(keyName); !exists {
+				return fmt.Errorf("key '%s' not found", keyName)
+			}
+		}
+		keys = args
+	default:
+		if cfg.Settings.DefaultKey == "" {
+			return fmt.Errorf("no default key configured. Use 'aim config set default-key <key-name>' or specify keys to benchmark")
+		}
+		keys = []string{cfg.Settings.DefaultKey}
+	}
+
+	opts := bench.Options{
+		Requests:    requests,
+		Concurrency: concurrency,
+		Prompt:      prompt,
+		MaxTokens:   maxTokens,
+		Timeout:     timeout,
+	}
+	prober := probe.NewProber(&http.Client{})
+
+	var rows []benchRow
+	for _, keyName := range keys {
+		target := resolveTestTarget(resolver, keyName, toolName, "", !benchAll)
+		row := benchRow{Key: keyName, Tool: toolName}
+
+		if target.Skip != "" {
+			if format == "table" {
+				fmt.Fprintf(os.Stderr, "⏭️  %s: skipped (%s)\n", keyName, target.Skip)
+			}
+			continue
+		}
+		if target.Err != nil {
+			row.Requests = requests
+			row.Errors = requests
+			row.ErrorRate = 1
+			row.ErrorClasses = map[string]int{string(probe.ClassConfig): requests}
+			row.LastError = target.Err.Error()
+			rows = append(rows, row)
+			continue
+		}
+
+		runtime := target.Runtime
+		row.Provider = runtime.Provider
+		row.Model = runtime.Model
+
+		if format == "table" {
+			fmt.Fprintf(os.Stderr, "Benchmarking %s (%s, %s)...\n", keyName, runtime.Provider, runtime.Model)
+		}
+
+		summary := bench.Run(context.Background(), prober, probeTarget(protocol, runtime), opts)
+
+		row.Requests = summary.Requests
+		row.Errors = summary.Errors
+		row.ErrorRate = summary.ErrorRate
+		row.TTFTP50MS = summary.TTFTP50.Milliseconds()
+		row.TTFTP90MS = summary.TTFTP90.Milliseconds()
+		row.LatencyP50MS = summary.LatencyP50.Milliseconds()
+		row.LatencyP90MS = summary.LatencyP90.Milliseconds()
+		row.LatencyP99MS = summary.LatencyP99.Milliseconds()
+		row.TokensPerSec = summary.TokensPerSec
+		row.ErrorClasses = summary.ErrorClasses
+		row.LastError = summary.LastError
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return fmt.Errorf("no keys have a '%s' profile to benchmark", toolName)
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "csv":
+		return writeBenchCSV(rows)
+	default:
+		printBenchTable(rows)
+	}
+
+	return nil
+}
+
+// printBenchTable prints a comparison table, fastest median latency first
+func printBenchTable(rows []benchRow) {
+	sorted := make([]benchRow, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		// Rows without any successful request go last
+		if (sorted[i].Errors == sorted[i].Requests) != (sorted[j].Errors == sorted[j].Requests) {
+			return sorted[j].Errors == sorted[j].Requests
+		}
+		return sorted[i].LatencyP50MS < sorted[j].LatencyP50MS
+	})
+
+	fmt.Println()
+	fmt.Printf("%-20s %-12s %-24s %9s %9s %9s %9s %9s %8s %7s\n",
+		"KEY", "PROVIDER", "MODEL", "TTFT P50", "TTFT P90", "LAT P50", "LAT P90", "LAT P99", "TOK/S", "ERRORS")
+	for _, row := range sorted {
+		fmt.Printf("%-20s %-12s %-24s %9s %9s %9s %9s %9s %8.1f %7s\n",
+			row.Key,
+			valueOrDash(row.Provider),
+			valueOrDash(row.Model),
+			formatMS(row.TTFTP50MS),
+			formatMS(row.TTFTP90MS),
+			formatMS(row.LatencyP50MS),
+			formatMS(row.LatencyP90MS),
+			formatMS(row.LatencyP99MS),
+			row.TokensPerSec,
+			fmt.Sprintf("%d/%d", row.Errors, row.Requests))
+	}
+
+	for _, row := range sorted {
+		if row.LastError != "" {
+			fmt.Printf("\n⚠️  %s: %s\n", row.Key, row.LastError)
+		}
+	}
+}
+
+// writeBenchCSV writes rows as CSV with a header
+func writeBenchCSV(rows []benchRow) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"key", "tool", "provider", "model", "requests", "errors", "error_rate",
+		"ttft_p50_ms", "ttft_p90_ms", "latency_p50_ms", "latency_p90_ms", "latency_p99_ms", "tokens_per_sec"})
+	for _, row := range rows {
+		_ = w.Write([]string{
+			row.Key,
+			row.Tool,
+			row.Provider,
+			row.Model,
+			strconv.Itoa(row.Requests),
+			strconv.Itoa(row.Errors),
+			strconv.FormatFloat(row.ErrorRate, 'f', 3, 64),
+			strconv.FormatInt(row.TTFTP50MS, 10),
+			strconv.FormatInt(row.TTFTP90MS, 10),
+			strconv.FormatInt(row.LatencyP50MS, 10),
+			strconv.FormatInt(row.LatencyP90MS, 10),
+			strconv.FormatInt(row.LatencyP99MS, 10),
+			strconv.FormatFloat(row.TokensPerSec, 'f', 1, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// formatMS renders milliseconds for the table, "-" when there is no sample
+func formatMS(ms int64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%dms", ms)
+}
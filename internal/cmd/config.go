@@ -5,8 +5,10 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 
 	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -222,6 +224,10 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 	value := args[1]
 
+	if key == "region" && !provider.IsKnownRegion(value) {
+		return fmt.Errorf("unknown region '%s' (supported: %s)", value, strings.Join(provider.KnownRegions, ", "))
+	}
+
 	// Get global configuration manager
 	cm := config.GetConfigManager()
 
@@ -242,6 +248,12 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 			// If invalid, keep the existing value (silently ignore)
 		case "language":
 			cfg.Settings.Language = value
+		case "low-balance-threshold":
+			if threshold, err := strconv.ParseFloat(value, 64); err == nil {
+				cfg.Settings.LowBalanceThreshold = threshold
+			}
+		case "region":
+			cfg.Settings.Region = value
 		default:
 			return
 		}
@@ -282,6 +294,12 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		}
 	case "language":
 		value = cfg.Settings.Language
+	case "low-balance-threshold":
+		if cfg.Settings.LowBalanceThreshold > 0 {
+			value = strconv.FormatFloat(cfg.Settings.LowBalanceThreshold, 'f', -1, 64)
+		}
+	case "region":
+		value = cfg.Settings.Region
 	default:
 		return fmt.Errorf("unknown setting key: %s", key)
 	}
@@ -311,6 +329,8 @@ func runConfigList(cmd *cobra.Command, args []string) error {
 		{"default-key", cfg.Settings.DefaultKey},
 		{"timeout", fmt.Sprintf("%d", cfg.Settings.Timeout)},
 		{"language", cfg.Settings.Language},
+		{"low-balance-threshold", formatThreshold(cfg.Settings.LowBalanceThreshold)},
+		{"region", cfg.Settings.Region},
 	}
 
 	for _, setting := range settings {
@@ -383,6 +403,14 @@ func runConfigEdit(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println("\n✓ Configuration updated successfully")
+	warnDeprecatedProfileModels(newCfg)
 	return nil
 }
 
+// formatThreshold renders the low-balance threshold, empty when disabled
+func formatThreshold(threshold float64) string {
+	if threshold <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(threshold, 'f', -1, 64)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show the references between keys, providers, profiles and tools",
+	Long: `Show which keys, tool profiles, settings and state entries reference each
+provider, key and tool. These references decide whether 'aim provider remove',
+'aim keys remove' and 'aim tool remove' need --cascade or --reassign.
+
+References to names that don't exist are listed as dangling.
+
+Examples:
+  aim config graph
+  aim config graph --format dot | dot -Tsvg > aim.svg`,
+	Args: cobra.NoArgs,
+	RunE: runConfigGraph,
+}
+
+func init() {
+	configGraphCmd.Flags().String("format", "text", "Output format: text, dot")
+	configCmd.AddCommand(configGraphCmd)
+}
+
+func runConfigGraph(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "text" && format != "dot" {
+		return fmt.Errorf("unsupported format '%s'. Supported formats: text, dot", format)
+	}
+
+	cm := config.GetConfigManager()
+	cfg := cm.GetConfig()
+	graph := config.BuildGraph(cfg, cm.GetState())
+
+	dangling := make(map[config.Entity]bool)
+	for _, e := range graph.Dangling(cfg) {
+		dangling[e] = true
+	}
+
+	if format == "dot" {
+		printGraphDOT(graph, dangling)
+		return nil
+	}
+
+	entities := append([]config.Entity(nil), graph.Entities...)
+	sort.SliceStable(entities, func(i, j int) bool { return entities[i].Name < entities[j].Name })
+
+	fmt.Println("\nReference graph:")
+	for _, kind := range []string{config.EntityProvider, config.EntityKey, config.EntityTool} {
+		for _, e := range entities {
+			if e.Kind != kind {
+				continue
+			}
+			refs := graph.ReferencesTo(e)
+			suffix := ""
+			if dangling[e] {
+				suffix = "  ⚠️  missing"
+			}
+			if len(refs) == 0 {
+				fmt.Printf("\n%s%s (no references)\n", e, suffix)
+				continue
+			}
+			fmt.Printf("\n%s%s\n", e, suffix)
+			for _, edge := range refs {
+				fmt.Printf("  ← %-30s %s\n", edge.From, edge.Location)
+			}
+		}
+	}
+
+	if len(dangling) > 0 {
+		fmt.Println("\n⚠️  Dangling references:")
+		for _, edge := range graph.Edges {
+			if dangling[edge.To] {
+				fmt.Printf("  • %s → %s\n", edge.Location, edge.To)
+			}
+		}
+	}
+
+	return nil
+}
+
+// graphNodeShapes gives each entity kind its own DOT shape
+var graphNodeShapes = map[string]string{
+	config.EntityKey:      "ellipse",
+	config.EntityProvider: "box",
+	config.EntityProfile:  "note",
+	config.EntityTool:     "component",
+	config.EntitySettings: "folder",
+	config.EntityState:    "folder",
+}
+
+// printGraphDOT prints the graph in Graphviz DOT syntax
+func printGraphDOT(graph *config.Graph, dangling map[config.Entity]bool) {
+	nodeID := func(e config.Entity) string {
+		return strconv.Quote(e.Kind + ":" + e.Name)
+	}
+
+	fmt.Println("digraph aim {")
+	fmt.Println("  rankdir=LR;")
+	for _, e := range graph.Entities {
+		attrs := fmt.Sprintf("label=%s, shape=%s", strconv.Quote(e.String()), graphNodeShapes[e.Kind])
+		if dangling[e] {
+			attrs += ", style=dashed, color=red"
+		}
+		fmt.Printf("  %s [%s];\n", nodeID(e), attrs)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Printf("  %s -> %s [tooltip=%s];\n", nodeID(edge.From), nodeID(edge.To), strconv.Quote(edge.Location))
+	}
+	fmt.Println("}")
+}
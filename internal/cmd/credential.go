@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fakecore/aim/internal/audit"
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var credentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Credential helper for AI tools",
+	Long: `Credential helper commands that tools call to fetch API keys on demand.
+
+Tools configured with a helper always receive the current key value, so
+rotating a key with aim takes effect without reinstalling tool configs.`,
+}
+
+var credentialGetCmd = &cobra.Command{
+	Use:   "get --tool <tool> [--key <key-name>]",
+	Short: "Print the resolved API key",
+	Long: `Print only the resolved API key for the current context.
+
+The key is resolved exactly like 'aim run' does: --key if given, otherwise
+the default key from settings (including project .aim.yaml overrides).
+
+Examples:
+  # Claude Code apiKeyHelper (installed by 'aim setup install cc')
+  aim credential get --tool claude-code --key glm-work
+
+  # Use the default key
+  aim credential get --tool codex`,
+	Args: cobra.NoArgs,
+	RunE: runCredentialGet,
+}
+
+func init() {
+	credentialGetCmd.Flags().String("tool", "claude-code", "Tool requesting the credential")
+	credentialGetCmd.Flags().String("key", "", "Key name (default: settings.default_key)")
+	credentialGetCmd.Flags().String("provider", "", "Profile to resolve (overrides key's default provider)")
+
+	credentialCmd.AddCommand(credentialGetCmd)
+}
+
+func runCredentialGet(cmd *cobra.Command, args []string) error {
+	toolName, _ := cmd.Flags().GetString("tool")
+	keyName, _ := cmd.Flags().GetString("key")
+	providerName, _ := cmd.Flags().GetString("provider")
+
+	cfg := config.GetConfigManager().GetConfig()
+	resolver := config.NewResolver(cfg)
+
+	if keyName == "" {
+		keyName = cfg.Settings.DefaultKey
+		if keyName == "" {
+			return fmt.Errorf("no key specified. Use --key <key-name> or set default key with 'aim config set default-key <key-name>'")
+		}
+	}
+
+	if err := resolver.ValidateKey(keyName); err != nil {
+		return fmt.Errorf("invalid key: %w", err)
+	}
+	if key, _ := cfg.GetKey(keyName); key.Credentials != nil {
+		return fmt.Errorf("key '%s' uses %s cloud credentials, which tools read from the environment. Use 'aim run' instead", keyName, key.Credentials.Type)
+	}
+
+	runtime, err := resolver.Resolve(tool.GetCanonicalName(toolName), keyName, providerName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve configuration: %w", err)
+	}
+
+	audit.Record(audit.ActionCredential, keyName, runtime.Tool, runtime.Provider, "")
+
+	// Only the key goes to stdout so tools can consume it verbatim
+	fmt.Println(runtime.APIKey)
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fakecore/aim/internal/identity"
+	"github.com/spf13/cobra"
+)
+
+var identityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Manage your key sharing identity",
+	Long: `Manage the X25519 keypair used to receive shared API keys.
+
+Teammates encrypt keys to your public key with 'aim keys share',
+and you decrypt them with 'aim keys receive'.`,
+}
+
+var identityInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create your identity keypair",
+	Long: `Create a new X25519 identity keypair and show its public key.
+
+Examples:
+  aim identity init            # Create ~/.aim/identity
+  aim identity init --force    # Replace an existing identity`,
+	RunE: runIdentityInit,
+}
+
+var identityShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show your public key",
+	Long:  `Show the public key teammates should pass to 'aim keys share --to'.`,
+	RunE:  runIdentityShow,
+}
+
+func init() {
+	identityCmd.PersistentFlags().String("identity", "", "Identity file (default: ~/.aim/identity)")
+	identityInitCmd.Flags().Bool("force", false, "Overwrite an existing identity")
+
+	identityCmd.AddCommand(identityInitCmd)
+	identityCmd.AddCommand(identityShowCmd)
+}
+
+func runIdentityInit(cmd *cobra.Command, args []string) error {
+	path := identityPath(cmd)
+	force, _ := cmd.Flags().GetBool("force")
+
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("identity already exists at %s. Use --force to replace it (keys shared to the old identity can no longer be received)", path)
+	}
+
+	id, err := identity.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := identity.Save(path, id); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Created identity at %s\n", path)
+	fmt.Printf("\nPublic key: %s\n", id.Recipient())
+	fmt.Println("\nShare the public key with teammates so they can run:")
+	fmt.Printf("  aim keys share <key-name> --to %s\n", id.Recipient())
+
+	return nil
+}
+
+func runIdentityShow(cmd *cobra.Command, args []string) error {
+	id, err := loadIdentity(cmd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id.Recipient())
+	return nil
+}
+
+// identityPath returns the identity file path from --identity or the default
+func identityPath(cmd *cobra.Command) string {
+	if path, _ := cmd.Flags().GetString("identity"); path != "" {
+		return path
+	}
+	return identity.DefaultPath()
+}
+
+// loadIdentity loads the identity selected by --identity
+func loadIdentity(cmd *cobra.Command) (*identity.Identity, error) {
+	path := identityPath(cmd)
+	id, err := identity.Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no identity found at %s. Run 'aim identity init' first", path)
+		}
+		return nil, fmt.Errorf("failed to load identity: %w", err)
+	}
+	return id, nil
+}
@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 
+	"github.com/fakecore/aim/internal/audit"
 	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/identity"
 	"github.com/fakecore/aim/internal/provider"
+	"github.com/fakecore/aim/internal/setup"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +32,19 @@ Examples:
   # Add a GLM key
   aim keys add glm-shared --provider glm --key glm-xxx --description "Shared GLM account"
 
+  # Local providers (Ollama, LM Studio, llama.cpp) need no --key
+  aim keys add ollama --provider ollama
+
+  # Claude on AWS Bedrock with an access key, or with --aws-profile
+  aim keys add bedrock-prod --provider bedrock --aws-region us-east-1 \
+    --aws-access-key-id AKIA... --aws-secret-access-key xxx
+
+  # Claude on Google Vertex AI with application default credentials
+  aim keys add vertex-dev --provider vertex --gcp-project my-project --gcp-region us-east5
+
+  # Azure OpenAI deployment (provider added with --kind azure)
+  aim keys add corp-gpt --provider corp-azure --key xxx --deployment gpt-5-prod --api-version 2025-04-01-preview
+
 Available providers:
 ` + provider.FormatProviderForHelp() + `
 
@@ -75,8 +94,8 @@ Use 'aim provider list' to see all available providers.`,
 			if provider.IsBuiltinProvider(providerName) {
 				fmt.Printf("❌ Error: Provider '%s' is not configured\n\n", providerName)
 				fmt.Printf("'%s' is a builtin provider but hasn't been added to your configuration yet.\n\n", providerName)
-				fmt.Println("To use this provider, it will be automatically available in the default configuration.")
-				fmt.Println("However, you may need to reinitialize your config or manually add it.")
+				fmt.Println("Add it first (creates the provider and a profile for each tool):")
+				fmt.Printf("  aim provider add --builtin %s\n", providerName)
 				fmt.Println("\nConfigured providers:")
 				listConfiguredProviders(cfg)
 				return fmt.Errorf("provider '%s' not configured", providerName)
@@ -103,8 +122,7 @@ Use 'aim provider list' to see all available providers.`,
 					fmt.Printf("'%s' appears to be an endpoint variant of '%s', but it's not in your configuration.\n\n", providerName, baseProvider)
 					fmt.Println("To add this provider endpoint to your configuration:")
 					fmt.Printf("  1. Check available endpoints: aim provider info %s\n", baseProvider)
-					fmt.Println("  2. Manually add it to your config.yaml, or")
-					fmt.Println("  3. Reinitialize your configuration")
+					fmt.Printf("  2. Add it: aim provider add %s --builtin %s --endpoint <endpoint>\n", providerName, baseProvider)
 					fmt.Println("\nConfigured providers:")
 					listConfiguredProviders(cfg)
 					return fmt.Errorf("provider '%s' not configured", providerName)
@@ -120,7 +138,29 @@ Use 'aim provider list' to see all available providers.`,
 			}
 		}
 
-		if apiKey == "" {
+		creds, err := credentialsFromFlags(cmd, providerName)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return err
+		}
+		if creds != nil {
+			if apiKey != "" {
+				return fmt.Errorf("--key cannot be combined with cloud credential flags")
+			}
+			return nil
+		}
+
+		deployment, _ := cmd.Flags().GetString("deployment")
+		apiVersion, _ := cmd.Flags().GetString("api-version")
+		if cfg.ProviderKind(providerName) == config.ProviderKindAzure {
+			if p, _ := cfg.GetProvider(providerName); deployment == "" && p.Model == "" {
+				return fmt.Errorf("--deployment is required for Azure OpenAI provider '%s'", providerName)
+			}
+		} else if deployment != "" || apiVersion != "" {
+			return fmt.Errorf("--deployment and --api-version only apply to Azure OpenAI providers (kind: azure)")
+		}
+
+		if apiKey == "" && cfg.ProviderRequiresKey(providerName) {
 			fmt.Println("❌ Error: Missing required flag --key")
 			fmt.Println("\nExample usage:")
 			fmt.Println("  aim keys add my-key --provider glm --key your-api-key")
@@ -144,9 +184,14 @@ var keysRemoveCmd = &cobra.Command{
 	Short: "Remove an API key",
 	Long: `Remove an API key configuration.
 
+Keys still referenced by settings.default_key, state or a {keys.<name>}
+header are only removed with --cascade (clear or drop those references)
+or --reassign <key> (point them at another key).
+
 Examples:
   aim keys remove deepseek-work    # Remove the DeepSeek API key
-  aim keys remove glm-shared       # Remove the GLM API key`,
+  aim keys remove glm-shared       # Remove the GLM API key
+  aim keys remove glm-old --reassign glm-new --dry-run`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			fmt.Println("❌ Error: Missing key name")
@@ -209,19 +254,97 @@ Examples:
 	RunE: runKeysShow,
 }
 
+var keysRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename an API key and update its references",
+	Long: `Rename an API key and update every reference to it: settings.default_key,
+{keys.<name>} references in provider and profile headers, state entries and
+AIM-managed tool configs installed with the key, including
+aider's managed header and the key's aim-<key> provider in opencode.json.
+opencode's {env:AIM_<KEY>_...} references are renamed too, so re-export them
+with 'aim setup env opencode --key <new-name>'.
+
+Examples:
+  aim keys rename glm-shared glm-team
+  aim keys rename glm-shared glm-team --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runKeysRename,
+}
+
+var keysShareCmd = &cobra.Command{
+	Use:   "share <key-name> --to <recipient-pubkey>",
+	Short: "Encrypt an API key for a teammate",
+	Long: `Encrypt an API key to a teammate's public key.
+
+The recipient gets their public key from 'aim identity init' and imports
+the blob with 'aim keys receive'. Only the recipient can decrypt it.
+Cloud credentials (Bedrock, Vertex) and Azure deployment settings travel
+with the key; AWS profiles and credential files must exist on the recipient's machine.
+
+Examples:
+  # Write the encrypted blob to a file
+  aim keys share glm-shared --to aim1... -o glm-shared.aimkey
+
+  # Print the encrypted blob to stdout
+  aim keys share glm-shared --to aim1...`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeysShare,
+}
+
+var keysReceiveCmd = &cobra.Command{
+	Use:   "receive <file>",
+	Short: "Import an API key shared with you",
+	Long: `Decrypt a shared key blob with your identity and add it to your configuration.
+
+Examples:
+  aim keys receive glm-shared.aimkey
+  aim keys receive glm-shared.aimkey --name glm-team
+  cat glm-shared.aimkey | aim keys receive -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeysReceive,
+}
+
 func init() {
 	// Flags for add command
 	keysAddCmd.Flags().String("provider", "", "Provider name (required)")
-	keysAddCmd.Flags().String("key", "", "API key value (required)")
+	keysAddCmd.Flags().String("key", "", "API key value (required except for local providers)")
 	keysAddCmd.Flags().String("description", "", "Description of the key")
+	keysAddCmd.Flags().String("aws-access-key-id", "", "AWS access key ID (Bedrock)")
+	keysAddCmd.Flags().String("aws-secret-access-key", "", "AWS secret access key (Bedrock)")
+	keysAddCmd.Flags().String("aws-session-token", "", "AWS session token for temporary credentials (Bedrock)")
+	keysAddCmd.Flags().String("aws-profile", "", "AWS named profile instead of an access key (Bedrock)")
+	keysAddCmd.Flags().String("aws-region", "", "AWS region, e.g. us-east-1 (Bedrock)")
+	keysAddCmd.Flags().String("gcp-project", "", "GCP project ID (Vertex)")
+	keysAddCmd.Flags().String("gcp-region", "", "Vertex region, e.g. us-east5 (Vertex)")
+	keysAddCmd.Flags().String("gcp-credentials-file", "", "Service account JSON file; application default credentials if omitted (Vertex)")
+	keysAddCmd.Flags().String("deployment", "", "Deployment name, used as the model (Azure OpenAI)")
+	keysAddCmd.Flags().String("api-version", "", "API version query parameter (Azure OpenAI, default: "+config.DefaultAzureAPIVersion+")")
 	keysAddCmd.MarkFlagRequired("provider")
-	keysAddCmd.MarkFlagRequired("key")
+	// --key is checked in PreRunE: local providers accept keys without a value
+
+	// Flags for remove command
+	addRemoveFlags(keysRemoveCmd, "key")
+
+	// Flags for rename command
+	keysRenameCmd.Flags().Bool("dry-run", false, "List the references that would change without renaming")
+
+	// Flags for share command
+	keysShareCmd.Flags().String("to", "", "Recipient public key (required)")
+	keysShareCmd.Flags().StringP("output", "o", "", "Write the encrypted blob to a file instead of stdout")
+	keysShareCmd.MarkFlagRequired("to")
+
+	// Flags for receive command
+	keysReceiveCmd.Flags().String("name", "", "Store the key under a different name")
+	keysReceiveCmd.Flags().String("identity", "", "Identity file (default: ~/.aim/identity)")
 
 	// Add subcommands
 	keysCmd.AddCommand(keysAddCmd)
 	keysCmd.AddCommand(keysListCmd)
 	keysCmd.AddCommand(keysRemoveCmd)
 	keysCmd.AddCommand(keysShowCmd)
+	keysCmd.AddCommand(keysRenameCmd)
+	keysCmd.AddCommand(keysShareCmd)
+	keysCmd.AddCommand(keysReceiveCmd)
 }
 
 func runKeysAdd(cmd *cobra.Command, args []string) error {
@@ -229,6 +352,11 @@ func runKeysAdd(cmd *cobra.Command, args []string) error {
 	provider, _ := cmd.Flags().GetString("provider")
 	apiKey, _ := cmd.Flags().GetString("key")
 	description, _ := cmd.Flags().GetString("description")
+	deployment, _ := cmd.Flags().GetString("deployment")
+	apiVersion, _ := cmd.Flags().GetString("api-version")
+
+	// Validated in PreRunE
+	creds, _ := credentialsFromFlags(cmd, provider)
 
 	// Get global configuration manager
 	cm := config.GetConfigManager()
@@ -249,6 +377,9 @@ func runKeysAdd(cmd *cobra.Command, args []string) error {
 			Provider:    provider,
 			Key:         apiKey,
 			Description: description,
+			Credentials: creds,
+			Deployment:  deployment,
+			APIVersion:  apiVersion,
 		}
 	})
 
@@ -261,11 +392,26 @@ func runKeysAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
+	audit.Record(audit.ActionAdd, keyName, "", provider, "")
+
 	fmt.Printf("✓ Added key '%s' for provider '%s'\n", keyName, provider)
 	if description != "" {
 		fmt.Printf("  Description: %s\n", description)
 	}
-	fmt.Printf("  Key: %s\n", maskKey(apiKey))
+	switch {
+	case creds != nil:
+		fmt.Printf("  Credentials: %s\n", creds.Summary())
+	case apiKey == "":
+		fmt.Printf("  Key: (none, local provider)\n")
+	default:
+		fmt.Printf("  Key: %s\n", maskKey(apiKey))
+	}
+	if deployment != "" {
+		fmt.Printf("  Deployment: %s\n", deployment)
+	}
+	if apiVersion != "" {
+		fmt.Printf("  API version: %s\n", apiVersion)
+	}
 
 	return nil
 }
@@ -283,12 +429,18 @@ func runKeysList(cmd *cobra.Command, args []string) error {
 		// Show configured keys
 		for name, key := range cfg.Keys {
 			maskedKey := maskKey(key.Key)
+			if key.Credentials != nil {
+				maskedKey = key.Credentials.Summary()
+			}
 			providerDisplay := key.Provider
 
 			fmt.Printf("  ✓ %-20s %-15s %s\n", name+":", providerDisplay+":", maskedKey)
 			if key.Description != "" {
 				fmt.Printf("    %s\n", key.Description)
 			}
+			if key.Deployment != "" {
+				fmt.Printf("    Deployment: %s\n", key.Deployment)
+			}
 		}
 	}
 
@@ -321,33 +473,25 @@ func runKeysRemove(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Show confirmation
-	fmt.Printf("Are you sure you want to remove key '%s'? (y/N): ", keyName)
-	var confirm string
-	fmt.Scanln(&confirm)
-
-	if confirm != "y" && confirm != "Y" {
-		fmt.Println("Cancelled")
-		return nil
-	}
-
-	// Update configuration
-	err := cm.UpdateConfig(func(cfg *config.Config) {
-		delete(cfg.Keys, keyName)
-	})
-
+	plan, err := config.PlanKeyRemoval(cfg, cm.GetState(), keyName, removeOptions(cmd))
 	if err != nil {
-		return fmt.Errorf("failed to update config: %w", err)
+		return explainReferenced(err)
 	}
 
-	// Force save to disk immediately
-	if err := cm.ForceSave(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
-	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !dryRun {
+		// Show confirmation
+		fmt.Printf("Are you sure you want to remove key '%s'? (y/N): ", keyName)
+		var confirm string
+		fmt.Scanln(&confirm)
 
-	fmt.Printf("✓ Removed key '%s'\n", keyName)
+		if confirm != "y" && confirm != "Y" {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
 
-	return nil
+	return executeRemoval(plan, dryRun)
 }
 
 func runKeysShow(cmd *cobra.Command, args []string) error {
@@ -374,6 +518,8 @@ func runKeysShow(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	audit.Record(audit.ActionReveal, keyName, "", key.Provider, "")
+
 	// Show warning
 	fmt.Println("\n⚠️  WARNING: This will display the full API key")
 
@@ -383,11 +529,217 @@ func runKeysShow(cmd *cobra.Command, args []string) error {
 	if key.Description != "" {
 		fmt.Printf("Description: %s\n", key.Description)
 	}
-	fmt.Printf("API Key: %s\n\n", key.Key)
+	if key.Credentials != nil {
+		printCredentials(key.Credentials)
+		fmt.Println()
+	} else {
+		fmt.Printf("API Key: %s\n", key.Key)
+		if key.Deployment != "" {
+			fmt.Printf("Deployment: %s\n", key.Deployment)
+		}
+		if key.APIVersion != "" {
+			fmt.Printf("API Version: %s\n", key.APIVersion)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runKeysRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cm := config.GetConfigManager()
+
+	plan, err := config.PlanKeyRename(cm.GetConfig(), cm.GetState(), oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	managed, err := setup.NewSetupManager(nil).PlanManagedKeyRename(oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	if err := executeRename(plan, managed, dryRun); err != nil || dryRun {
+		return err
+	}
+
+	audit.Record(audit.ActionRename, newName, "", "", "renamed from "+oldName)
+	return nil
+}
+
+func runKeysShare(cmd *cobra.Command, args []string) error {
+	keyName := args[0]
+	to, _ := cmd.Flags().GetString("to")
+	output, _ := cmd.Flags().GetString("output")
+
+	cfg := config.GetConfigManager().GetConfig()
+
+	key, exists := cfg.GetKey(keyName)
+	if !exists {
+		return fmt.Errorf("key '%s' not found", keyName)
+	}
+
+	recipient, err := identity.ParseRecipient(to)
+	if err != nil {
+		return err
+	}
+
+	// Profiles and credential files are only names and paths on this machine
+	if creds := key.Credentials; creds != nil && (creds.Profile != "" || creds.CredentialsFile != "") {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: key '%s' uses a local AWS profile or credentials file; the recipient needs the same one\n", keyName)
+	}
+
+	envelope, err := identity.Seal(&identity.SharedKey{
+		Name:        keyName,
+		Provider:    key.Provider,
+		Key:         key.Key,
+		Description: key.Description,
+		Credentials: key.Credentials,
+		Deployment:  key.Deployment,
+		APIVersion:  key.APIVersion,
+	}, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+
+	data, err := envelope.Marshal()
+	if err != nil {
+		return err
+	}
+
+	audit.Record(audit.ActionShare, keyName, "", key.Provider, "to "+envelope.Recipient)
+
+	if output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("✓ Encrypted key '%s' for %s\n", keyName, identity.EncodePublicKey(recipient))
+	fmt.Printf("  File: %s\n", output)
+	fmt.Println("\nThe recipient can import it with:")
+	fmt.Printf("  aim keys receive %s\n", filepath.Base(output))
+
+	return nil
+}
+
+func runKeysReceive(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	rename, _ := cmd.Flags().GetString("name")
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	envelope, err := identity.ParseEnvelope(data)
+	if err != nil {
+		return err
+	}
+
+	id, err := loadIdentity(cmd)
+	if err != nil {
+		return err
+	}
+
+	shared, err := identity.Open(envelope, id)
+	if err != nil {
+		return err
+	}
+
+	keyName := shared.Name
+	if rename != "" {
+		keyName = rename
+	}
+	if keyName == "" {
+		return fmt.Errorf("shared key has no name. Use --name to choose one")
+	}
+
+	cm := config.GetConfigManager()
+	cfg := cm.GetConfig()
+
+	if _, exists := cfg.GetKey(keyName); exists {
+		return fmt.Errorf("key '%s' already exists. Use --name to import it under a different name", keyName)
+	}
+
+	if shared.Credentials != nil {
+		if err := shared.Credentials.Validate(); err != nil {
+			return fmt.Errorf("shared key has invalid credentials: %w", err)
+		}
+	} else if shared.Key == "" {
+		return fmt.Errorf("shared key has no API key or credentials")
+	}
+
+	if !isProviderConfigured(cfg, shared.Provider) {
+		fmt.Printf("⚠️  Warning: provider '%s' is not configured yet\n", shared.Provider)
+	}
+
+	err = cm.UpdateConfig(func(cfg *config.Config) {
+		if cfg.Keys == nil {
+			cfg.Keys = make(map[string]*config.Key)
+		}
+
+		cfg.Keys[keyName] = &config.Key{
+			Provider:    shared.Provider,
+			Key:         shared.Key,
+			Description: shared.Description,
+			Credentials: shared.Credentials,
+			Deployment:  shared.Deployment,
+			APIVersion:  shared.APIVersion,
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	if err := cm.ForceSave(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	audit.Record(audit.ActionAdd, keyName, "", shared.Provider, "received via aim keys receive")
+
+	fmt.Printf("✓ Received key '%s' for provider '%s'\n", keyName, shared.Provider)
+	if shared.Description != "" {
+		fmt.Printf("  Description: %s\n", shared.Description)
+	}
+	if shared.Credentials != nil {
+		fmt.Printf("  Credentials: %s\n", shared.Credentials.Summary())
+	} else {
+		fmt.Printf("  Key: %s\n", maskKey(shared.Key))
+	}
 
 	return nil
 }
 
+// isProviderConfigured checks whether a provider is defined globally or by any tool profile
+func isProviderConfigured(cfg *config.Config, providerName string) bool {
+	if _, exists := cfg.Providers[providerName]; exists {
+		return true
+	}
+
+	for _, tool := range cfg.Tools {
+		for _, profile := range tool.Profiles {
+			if profile.Provider == providerName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // maskKey masks an API key for display
 func maskKey(key string) string {
 	if key == "" {
@@ -449,3 +801,79 @@ func listConfiguredProviders(cfg *config.Config) {
 		}
 	}
 }
+
+// credentialsFromFlags builds structured cloud credentials from the keys add flags
+// It returns nil when no credential flag is set and the provider uses API keys.
+func credentialsFromFlags(cmd *cobra.Command, providerName string) (*config.Credentials, error) {
+	get := func(name string) string {
+		value, _ := cmd.Flags().GetString(name)
+		return value
+	}
+
+	aws := &config.Credentials{
+		Type:            config.CredentialTypeAWS,
+		Region:          get("aws-region"),
+		AccessKeyID:     get("aws-access-key-id"),
+		SecretAccessKey: get("aws-secret-access-key"),
+		SessionToken:    get("aws-session-token"),
+		Profile:         get("aws-profile"),
+	}
+	gcp := &config.Credentials{
+		Type:            config.CredentialTypeGCP,
+		Region:          get("gcp-region"),
+		ProjectID:       get("gcp-project"),
+		CredentialsFile: get("gcp-credentials-file"),
+	}
+	awsSet := *aws != config.Credentials{Type: config.CredentialTypeAWS}
+	gcpSet := *gcp != config.Credentials{Type: config.CredentialTypeGCP}
+
+	required := provider.CredentialType(providerName)
+
+	var creds *config.Credentials
+	switch {
+	case awsSet && gcpSet:
+		return nil, fmt.Errorf("--aws-* and --gcp-* flags cannot be combined")
+	case awsSet:
+		creds = aws
+	case gcpSet:
+		creds = gcp
+	case required == config.CredentialTypeAWS:
+		return nil, fmt.Errorf("provider '%s' uses AWS credentials: set --aws-region plus --aws-access-key-id/--aws-secret-access-key or --aws-profile", providerName)
+	case required == config.CredentialTypeGCP:
+		return nil, fmt.Errorf("provider '%s' uses GCP credentials: set --gcp-project and --gcp-region", providerName)
+	default:
+		return nil, nil
+	}
+
+	if required != "" && creds.Type != required {
+		return nil, fmt.Errorf("provider '%s' uses %s credentials, not %s", providerName, required, creds.Type)
+	}
+	if creds.CredentialsFile != "" {
+		if abs, err := filepath.Abs(creds.CredentialsFile); err == nil {
+			creds.CredentialsFile = abs
+		}
+	}
+	if err := creds.Validate(); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// printCredentials prints every credential field, including secrets
+func printCredentials(c *config.Credentials) {
+	fields := []struct{ label, value string }{
+		{"Type", c.Type},
+		{"Region", c.Region},
+		{"Access Key ID", c.AccessKeyID},
+		{"Secret Access Key", c.SecretAccessKey},
+		{"Session Token", c.SessionToken},
+		{"Profile", c.Profile},
+		{"Project", c.ProjectID},
+		{"Credentials File", c.CredentialsFile},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			fmt.Printf("%s: %s\n", f.label, f.value)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/models"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// runBalanceCheckTimeout bounds the low-balance check before 'aim run' starts a tool
+const runBalanceCheckTimeout = 3 * time.Second
+
+var keysBalanceCmd = &cobra.Command{
+	Use:   "balance [key-name]",
+	Short: "Show remaining credit for keys",
+	Long: `Query the provider's balance endpoint and print remaining credit and currency.
+
+Supported providers: deepseek, kimi. Keys of other providers are reported as
+unsupported. Kimi keys are queried on the host of their region (api.moonshot.cn
+in CNY, or api.moonshot.ai in USD for intl), following the key's base URL and
+settings.region.
+
+Set a low-balance threshold to get a warning from 'aim run' when a key's
+remaining credit drops below it:
+  aim config set low-balance-threshold 10
+
+'aim run' checks the balance at most once an hour and caches it under
+~/.aim/cache/balance; 'aim keys balance' always queries and refreshes the cache.
+
+Examples:
+  # Balance of the default key
+  aim keys balance
+
+  # Balance of every key
+  aim keys balance --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runKeysBalance,
+}
+
+func init() {
+	keysBalanceCmd.Flags().Bool("all", false, "Show balance for all configured keys")
+	keysBalanceCmd.Flags().Duration("timeout", 15*time.Second, "Request timeout per key")
+
+	keysCmd.AddCommand(keysBalanceCmd)
+}
+
+func runKeysBalance(cmd *cobra.Command, args []string) error {
+	showAll, _ := cmd.Flags().GetBool("all")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	cfg := config.GetConfigManager().GetConfig()
+
+	var keyNames []string
+	switch {
+	case showAll:
+		keyNames = sortedKeyNames(cfg)
+	case len(args) > 0:
+		if _, exists := cfg.GetKey(args[0]); !exists {
+			return fmt.Errorf("key '%s' not found", args[0])
+		}
+		keyNames = []string{args[0]}
+	default:
+		if cfg.Settings.DefaultKey == "" {
+			return fmt.Errorf("no default key configured. Use 'aim config set default-key <key-name>' or specify a key")
+		}
+		keyNames = []string{cfg.Settings.DefaultKey}
+	}
+
+	if len(keyNames) == 0 {
+		return fmt.Errorf("no keys configured")
+	}
+
+	threshold := cfg.Settings.LowBalanceThreshold
+	client := &http.Client{Timeout: timeout}
+	cache := models.NewBalanceCache()
+	resolver := config.NewResolver(cfg)
+	failed := 0
+
+	for _, keyName := range keyNames {
+		key, _ := cfg.GetKey(keyName)
+		region := resolver.KeyRegion(keyName)
+		label := fmt.Sprintf("%s (%s)", keyName, key.Provider)
+		if region != "" {
+			label = fmt.Sprintf("%s (%s, %s)", keyName, key.Provider, region)
+		}
+
+		adapter, err := provider.GetBalanceAdapter(key.Provider, region)
+		if err != nil {
+			fmt.Printf("  %s: not supported\n", label)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		balance, err := adapter.Balance(ctx, client, key.Key)
+		cancel()
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", label, err)
+			failed++
+			continue
+		}
+
+		// Refresh the cache the 'aim run' low-balance warning reads
+		cache.Save(&models.BalanceEntry{
+			Key:         keyName,
+			Fingerprint: models.BalanceFingerprint(key.Key, key.Provider+"/"+region),
+			Available:   balance.Available,
+			Currency:    balance.Currency,
+		})
+
+		icon := "✓"
+		if threshold > 0 && balance.Available < threshold {
+			icon = "⚠️ "
+		}
+		fmt.Printf("%s %s: %s%s\n", icon, label, formatBalance(balance.Available, balance.Currency), formatBalanceDetails(balance))
+	}
+
+	if threshold > 0 {
+		fmt.Printf("\nLow-balance threshold: %s\n", formatThreshold(threshold))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to query %d balance(s)", failed)
+	}
+	return nil
+}
+
+// warnLowBalance warns when the key's balance is below settings.low_balance_threshold
+// Unsupported providers and query failures are silent so 'aim run' is never blocked
+func warnLowBalance(cfg *config.Config, keyName string) {
+	threshold := cfg.Settings.LowBalanceThreshold
+	if threshold <= 0 {
+		return
+	}
+
+	key, ok := cfg.GetKey(keyName)
+	if !ok {
+		return
+	}
+
+	region := config.NewResolver(cfg).KeyRegion(keyName)
+	adapter, err := provider.GetBalanceAdapter(key.Provider, region)
+	if err != nil {
+		return
+	}
+
+	// The balance is cached so only the first run in an hour waits for the provider
+	cache := models.NewBalanceCache()
+	fingerprint := models.BalanceFingerprint(key.Key, key.Provider+"/"+region)
+	entry, fresh := cache.Load(keyName, fingerprint)
+	if !fresh {
+		ctx, cancel := context.WithTimeout(context.Background(), runBalanceCheckTimeout)
+		defer cancel()
+
+		entry = &models.BalanceEntry{Key: keyName, Fingerprint: fingerprint}
+		balance, err := adapter.Balance(ctx, &http.Client{}, key.Key)
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Available = balance.Available
+			entry.Currency = balance.Currency
+		}
+		if err := cache.Save(entry); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Failed to cache balance: %v\n", err)
+		}
+	}
+
+	if entry.Error != "" {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Balance check failed: %s\n", entry.Error)
+		}
+		return
+	}
+
+	if entry.Available < threshold {
+		fmt.Fprintf(os.Stderr, "⚠️  Key '%s' is low on credit: %s (threshold %s)\n",
+			keyName, formatBalance(entry.Available, entry.Currency), formatThreshold(threshold))
+	}
+}
+
+// formatBalance renders an amount with its currency
+func formatBalance(amount float64, currency string) string {
+	if currency == "" {
+		return fmt.Sprintf("%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
+
+// formatBalanceDetails renders the provider-specific breakdown
+func formatBalanceDetails(balance *provider.Balance) string {
+	if len(balance.Details) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(balance.Details))
+	for name := range balance.Details {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %.2f", name, balance.Details[name])
+	}
+	return "  [" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/identity"
+	"github.com/fakecore/aim/internal/scan"
+	"github.com/fakecore/aim/internal/setup"
+	"github.com/spf13/cobra"
+)
+
+var keysScanCmd = &cobra.Command{
+	Use:   "scan [path...]",
+	Short: "Scan for leaked API keys",
+	Long: `Scan files for leaked API keys.
+
+The scanner looks for the values of your configured keys (compared by hash,
+never printed) and for strings shaped like provider API keys. It scans the
+working tree, the project .aim.yaml and tool configs written by
+'aim setup install', and optionally the git history.
+
+Examples:
+  # Scan the current directory
+  aim keys scan
+
+  # Also scan every commit in the repository
+  aim keys scan --history
+
+  # Use as a git pre-commit hook (scans staged changes, exits non-zero on hits)
+  aim keys scan --pre-commit`,
+	RunE: runKeysScan,
+}
+
+func init() {
+	keysScanCmd.Flags().Bool("history", false, "Also scan lines added in git history (git log -p)")
+	keysScanCmd.Flags().Bool("pre-commit", false, "Scan only staged changes and exit non-zero when keys are found")
+	keysScanCmd.Flags().Bool("no-patterns", false, "Only report configured key values, not provider-shaped strings")
+
+	keysCmd.AddCommand(keysScanCmd)
+}
+
+func runKeysScan(cmd *cobra.Command, args []string) error {
+	history, _ := cmd.Flags().GetBool("history")
+	preCommit, _ := cmd.Flags().GetBool("pre-commit")
+	noPatterns, _ := cmd.Flags().GetBool("no-patterns")
+
+	cm := config.GetConfigManager()
+	cfg := cm.GetConfig()
+
+	keyValues := make(map[string]string, len(cfg.Keys))
+	for name, key := range cfg.Keys {
+		if key != nil {
+			keyValues[name] = key.Key
+		}
+	}
+
+	scanner := scan.NewScanner(keyValues)
+	scanner.SetPatternsEnabled(!noPatterns)
+	// aim's own secret stores are expected to contain keys
+	scanner.Skip(cm.GetConfigPath())
+	scanner.Skip(identity.DefaultPath())
+
+	if preCommit {
+		hits, err := scanner.ScanStaged(".")
+		if err != nil {
+			return err
+		}
+		if len(hits) == 0 {
+			return nil
+		}
+		printScanHits("Staged changes", hits)
+		fmt.Println("\nCommit blocked: remove the keys above or reference them via ${ENV_VAR}.")
+		return fmt.Errorf("found %d potential leaked key(s)", len(hits))
+	}
+
+	total := 0
+
+	// 1. Working tree
+	roots := args
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	for _, root := range roots {
+		hits, err := scanner.ScanTree(root)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+		printScanHits(fmt.Sprintf("Working tree (%s)", root), hits)
+		total += len(hits)
+	}
+
+	// 2. Project config, which may live in a parent directory
+	if localPath, err := config.NewLoader().FindLocalConfig(); err == nil {
+		if !isUnderAny(localPath, roots) {
+			hits, err := scanner.ScanFile(localPath)
+			if err == nil {
+				printScanHits("Project config", hits)
+				total += len(hits)
+			}
+		}
+	}
+
+	// 3. Tool configs written by 'aim setup install'
+	for toolName, path := range setup.NewSetupManager(nil).ConfigPaths() {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		hits, err := scanner.ScanFile(path)
+		if err != nil {
+			continue
+		}
+		printScanHits(fmt.Sprintf("Tool config (%s)", toolName), hits)
+		total += len(hits)
+	}
+
+	// 4. Git history
+	if history {
+		hits, err := scanner.ScanGitHistory(".")
+		if err != nil {
+			return err
+		}
+		printScanHits("Git history", hits)
+		total += len(hits)
+	}
+
+	if total == 0 {
+		fmt.Println("✓ No leaked keys found")
+		return nil
+	}
+
+	fmt.Printf("\n⚠️  Found %d potential leaked key(s)\n", total)
+	fmt.Println("Rotate any key that reached a shared repository, then remove it from the files above.")
+	return nil
+}
+
+// printScanHits prints hits under a section heading
+func printScanHits(section string, hits []scan.Hit) {
+	if len(hits) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", section)
+	for _, hit := range hits {
+		fmt.Printf("  %s\n", hit)
+	}
+}
+
+// isUnderAny reports whether path is inside one of the given roots
+func isUnderAny(path string, roots []string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	for _, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(absRoot, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
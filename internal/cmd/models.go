@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/fakecore/aim/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Browse the builtin model catalog",
+	Long: `Browse models served by builtin providers with their context windows,
+output limits, tool-use and vision support, list prices and deprecation dates.
+
+Prices are USD per million tokens at list price and may vary by tier.
+For the live list from a provider's API, use 'aim provider models <provider>'.
+
+Without a subcommand, shows how each configured profile maps the model tiers
+(fast, balanced, strong) and aliases to model IDs; see 'aim models aliases'.`,
+	Args: cobra.NoArgs,
+	RunE: runModelsAliases,
+}
+
+var modelsAliasesCmd = &cobra.Command{
+	Use:   "aliases",
+	Short: "Show model tiers and aliases per provider",
+	Long: `Show how each configured profile maps model tiers and aliases to model IDs.
+
+Tiers are fast, balanced and strong; haiku, sonnet and opus are accepted as
+synonyms. Mappings come from the builtin provider presets and the models: maps
+of providers and profiles (profiles win). Use an alias with:
+  aim run cc --key glm-work --model fast
+  aim run codex --key deepseek --model strong
+
+Claude Code receives the tiers as ANTHROPIC_DEFAULT_{HAIKU,SONNET,OPUS}_MODEL;
+codex receives them as profiles selectable with 'codex --profile <tier>'.
+
+Examples:
+  aim models
+  aim models aliases --provider glm
+  aim models aliases --tool codex`,
+	Args: cobra.NoArgs,
+	RunE: runModelsAliases,
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List catalog models",
+	Long: `List catalog models, optionally filtered by provider, capability and context window.
+
+Examples:
+  aim models list
+  aim models list --provider glm
+  aim models list --capability tools --capability vision
+  aim models list --min-context 128k`,
+	Args: cobra.NoArgs,
+	RunE: runModelsList,
+}
+
+var modelsInfoCmd = &cobra.Command{
+	Use:   "info <model-id>",
+	Short: "Show catalog details for a model",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runModelsInfo,
+}
+
+func init() {
+	modelsListCmd.Flags().String("provider", "", "Only show models of this builtin provider")
+	modelsListCmd.Flags().StringSlice("capability", nil, "Require a capability: tools, vision (repeatable)")
+	modelsListCmd.Flags().String("min-context", "", "Minimum context window, e.g. 128k or 1m")
+
+	for _, c := range []*cobra.Command{modelsCmd, modelsAliasesCmd} {
+		c.Flags().String("provider", "", "Only show profiles of this provider")
+		c.Flags().String("tool", "", "Only show profiles of this tool")
+	}
+
+	modelsCmd.AddCommand(modelsListCmd)
+	modelsCmd.AddCommand(modelsInfoCmd)
+	modelsCmd.AddCommand(modelsAliasesCmd)
+}
+
+func runModelsList(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	capabilities, _ := cmd.Flags().GetStringSlice("capability")
+	minContext, _ := cmd.Flags().GetString("min-context")
+
+	filter := provider.ModelFilter{
+		Provider:     providerName,
+		Capabilities: capabilities,
+	}
+	if minContext != "" {
+		n, err := provider.ParseTokenCount(minContext)
+		if err != nil {
+			return fmt.Errorf("invalid --min-context: %w", err)
+		}
+		filter.MinContext = n
+	}
+
+	list, err := provider.ListModels(filter)
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No models match the given filters.")
+		return nil
+	}
+
+	fmt.Printf("%-10s %-32s %8s %8s %-12s %9s %9s %s\n",
+		"PROVIDER", "MODEL", "CONTEXT", "OUTPUT", "CAPS", "IN $/M", "OUT $/M", "STATUS")
+	for _, m := range list {
+		fmt.Printf("%-10s %-32s %8s %8s %-12s %9s %9s %s\n",
+			m.Provider,
+			m.ID,
+			provider.FormatTokenCount(m.ContextWindow),
+			provider.FormatTokenCount(m.MaxOutputTokens),
+			formatCapabilities(&m.ModelInfo),
+			formatPrice(m.InputPrice),
+			formatPrice(m.OutputPrice),
+			formatDeprecation(&m.ModelInfo))
+	}
+
+	warnDeprecatedProfileModels(config.GetConfigManager().GetConfig())
+	return nil
+}
+
+func runModelsInfo(cmd *cobra.Command, args []string) error {
+	m, ok := provider.FindModel(args[0])
+	if !ok {
+		return fmt.Errorf("model '%s' not found in the builtin catalog. Use 'aim models list' to see available models", args[0])
+	}
+
+	fmt.Printf("\nModel: %s\n", m.ID)
+	fmt.Printf("  Provider: %s (%s)\n", m.Provider, provider.GetProviderDisplayName(m.Provider))
+	fmt.Printf("  Context window: %s tokens\n", provider.FormatTokenCount(m.ContextWindow))
+	fmt.Printf("  Max output: %s tokens\n", provider.FormatTokenCount(m.MaxOutputTokens))
+	fmt.Printf("  Tool use: %s\n", yesNo(m.Tools))
+	fmt.Printf("  Vision: %s\n", yesNo(m.Vision))
+	fmt.Printf("  Input price: %s per million tokens\n", formatPrice(m.InputPrice))
+	fmt.Printf("  Output price: %s per million tokens\n", formatPrice(m.OutputPrice))
+	if m.Deprecated != "" {
+		fmt.Printf("  Deprecated: %s\n", m.Deprecated)
+		if m.Replacement != "" {
+			fmt.Printf("  Replacement: %s\n", m.Replacement)
+		}
+	}
+
+	// Show which configured profiles pin this model
+	cfg := config.GetConfigManager().GetConfig()
+	var pinned []string
+	for _, toolName := range sortedToolNames(cfg) {
+		for _, profileName := range sortedProfileNames(cfg.Tools[toolName]) {
+			if profile := cfg.Tools[toolName].Profiles[profileName]; profile != nil && profile.Model == m.ID {
+				pinned = append(pinned, fmt.Sprintf("%s (profile: %s)", toolName, profileName))
+			}
+		}
+	}
+	if len(pinned) > 0 {
+		fmt.Printf("\n  Pinned by:\n")
+		for _, p := range pinned {
+			fmt.Printf("    • %s\n", p)
+		}
+	}
+
+	return nil
+}
+
+func runModelsAliases(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	toolFilter, _ := cmd.Flags().GetString("tool")
+	if toolFilter != "" {
+		toolFilter = tool.GetCanonicalName(toolFilter)
+	}
+
+	cfg := config.GetConfigManager().GetConfig()
+	resolver := config.NewResolver(cfg)
+
+	rows := 0
+	for _, toolName := range sortedToolNames(cfg) {
+		if toolFilter != "" && toolName != toolFilter {
+			continue
+		}
+		for _, profileName := range sortedProfileNames(cfg.Tools[toolName]) {
+			profile := cfg.Tools[toolName].Profiles[profileName]
+			if profile == nil || (providerName != "" && profile.Provider != providerName && profileName != providerName) {
+				continue
+			}
+			aliases, err := resolver.ModelAliases(toolName, profileName)
+			if err != nil || len(aliases) == 0 {
+				continue
+			}
+
+			if rows == 0 {
+				fmt.Printf("%-20s %-12s %-10s %s\n", "PROFILE", "TOOL", "ALIAS", "MODEL")
+			}
+			for _, alias := range config.ModelAliasNames(aliases) {
+				fmt.Printf("%-20s %-12s %-10s %s\n", profileName, toolName, alias, aliases[alias])
+				rows++
+			}
+		}
+	}
+
+	if rows == 0 {
+		fmt.Println("No model tiers or aliases configured.")
+		fmt.Println("Add a models: map to a provider or profile, e.g.")
+		fmt.Println("  providers:\n    my-proxy:\n      models:\n        fast: small-model\n        strong: large-model")
+		return nil
+	}
+	fmt.Println("\nUse an alias with: aim run <tool> --key <key-name> --model <alias>")
+	warnDeprecatedProfileModels(cfg)
+	return nil
+}
+
+// warnDeprecatedModel warns when the resolved model is deprecated in the builtin catalog
+func warnDeprecatedModel(model string) {
+	m, status, ok := deprecationStatus(model)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Model '%s' %s\n", m.ID, status)
+	if m.Replacement != "" {
+		fmt.Fprintf(os.Stderr, "   Consider switching to '%s'\n", m.Replacement)
+	}
+}
+
+// warnDeprecatedProfileModels warns about providers and profiles that pin a deprecated catalog model
+func warnDeprecatedProfileModels(cfg *config.Config) {
+	warn := func(owner, model string) {
+		m, status, ok := deprecationStatus(model)
+		if !ok {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  %s pins model '%s', which %s\n", owner, m.ID, status)
+		if m.Replacement != "" {
+			fmt.Fprintf(os.Stderr, "   Consider switching to '%s'\n", m.Replacement)
+		}
+	}
+
+	providerNames := make([]string, 0, len(cfg.Providers))
+	for name := range cfg.Providers {
+		providerNames = append(providerNames, name)
+	}
+	sort.Strings(providerNames)
+	for _, name := range providerNames {
+		if p := cfg.Providers[name]; p != nil {
+			warn(fmt.Sprintf("Provider '%s'", name), p.Model)
+		}
+	}
+
+	for _, toolName := range sortedToolNames(cfg) {
+		for _, profileName := range sortedProfileNames(cfg.Tools[toolName]) {
+			if profile := cfg.Tools[toolName].Profiles[profileName]; profile != nil {
+				warn(fmt.Sprintf("Profile '%s' of %s", profileName, toolName), profile.Model)
+			}
+		}
+	}
+}
+
+// deprecationStatus looks up a deprecated catalog model and describes when it is deprecated
+func deprecationStatus(model string) (provider.CatalogModel, string, bool) {
+	m, ok := provider.FindModel(model)
+	if !ok {
+		return m, "", false
+	}
+	date, deprecated := m.DeprecationDate()
+	if !deprecated {
+		return m, "", false
+	}
+
+	if date.After(time.Now()) {
+		return m, "will be deprecated on " + m.Deprecated, true
+	}
+	return m, "is deprecated as of " + m.Deprecated, true
+}
+
+// formatCapabilities renders capability flags for the table
+func formatCapabilities(m *provider.ModelInfo) string {
+	caps := ""
+	if m.Tools {
+		caps += provider.CapabilityTools
+	}
+	if m.Vision {
+		if caps != "" {
+			caps += ","
+		}
+		caps += provider.CapabilityVision
+	}
+	return valueOrDash(caps)
+}
+
+// formatPrice renders a USD price, "-" when unknown
+func formatPrice(price float64) string {
+	if price == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", price)
+}
+
+// formatDeprecation renders the status column
+func formatDeprecation(m *provider.ModelInfo) string {
+	if m.Deprecated == "" {
+		return "current"
+	}
+	return "deprecated " + m.Deprecated
+}
+
+// yesNo renders a boolean for detail output
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// sortedToolNames returns configured tool names in stable order
+func sortedToolNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Tools))
+	for name := range cfg.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedProfileNames returns a tool's profile names in stable order
+func sortedProfileNames(toolCfg *config.ToolConfig) []string {
+	if toolCfg == nil {
+		return nil
+	}
+	names := make([]string, 0, len(toolCfg.Profiles))
+	for name := range toolCfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
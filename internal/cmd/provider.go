@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/fakecore/aim/internal/config"
 	"github.com/fakecore/aim/internal/provider"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var providerCmd = &cobra.Command{
@@ -18,24 +20,80 @@ var providerCmd = &cobra.Command{
 var providerListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all providers",
-	Long:  `List all available providers (builtin and configured).`,
-	RunE:  runProviderList,
+	Long: `List all available providers (builtin and configured).
+
+Builtin providers include definitions from providers.d next to the global
+config; see 'aim provider sync'.`,
+	RunE: runProviderList,
 }
 
 var providerAddCmd = &cobra.Command{
-	Use:   "add <name>",
+	Use:   "add [name]",
 	Short: "Add a global provider configuration",
-	Long:  `Add a global provider configuration that can be used by all tools.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runProviderAdd,
+	Long: `Add a global provider configuration that can be used by all tools.
+
+With --builtin, the provider's endpoint preset is used to create the global
+provider plus a profile under every configured tool, including the field
+mapping that passes the API key. The name defaults to a suggestion based on
+the endpoint (e.g. "glm-coding").
+
+Examples:
+  # Custom provider
+  aim provider add my-proxy --base-url https://proxy.example.com/v1 --model gpt-4o
+
+  # Builtin provider with its default endpoint
+  aim provider add --builtin deepseek
+
+  # Builtin provider endpoint preset, previewed first
+  aim provider add --builtin glm --endpoint coding --dry-run
+  aim provider add glm-team --builtin glm --endpoint coding
+
+  # International hosts (api.moonshot.ai, api.z.ai, dashscope-intl)
+  aim provider add --builtin kimi --region intl
+
+  # Custom codex provider wiring
+  aim provider add my-proxy --base-url https://proxy.example.com/v1 --model gpt-4o \
+    --display-name "My Proxy" --env-key MY_PROXY_KEY --wire-api responses --header X-Team=infra
+
+  # Gateway that wants x-api-key and a header referencing another key's secret
+  aim provider add my-gateway --base-url https://gateway.example.com --auth-scheme x-api-key \
+    --header "Helicone-Auth=Bearer {keys.helicone}"
+
+  # Azure OpenAI resource for codex; keys name the deployment
+  aim provider add corp-azure --kind azure --base-url https://corp.openai.azure.com/openai
+  aim keys add corp-gpt --provider corp-azure --key xxx --deployment gpt-5-prod`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProviderAdd,
 }
 
 var providerRemoveCmd = &cobra.Command{
 	Use:   "remove <name>",
 	Short: "Remove a global provider configuration",
-	Long:  `Remove a global AI provider configuration.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runProviderRemove,
+	Long: `Remove a global AI provider configuration.
+
+A provider still used by keys, tool profiles, settings.default_provider or
+state is only removed with --cascade (remove those keys and profiles, clear
+the rest) or --reassign <provider> (point them at another provider).
+
+Examples:
+  aim provider remove my-proxy
+  aim provider remove glm --cascade --dry-run
+  aim provider remove kimi-old --reassign kimi`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProviderRemove,
+}
+
+var providerRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a provider and update its references",
+	Long: `Rename a provider and update every reference to it: keys, tool profile
+provider fields (and profiles named after it), settings.default_provider and state.
+
+Examples:
+  aim provider rename glm zhipu
+  aim provider rename glm zhipu --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runProviderRename,
 }
 
 var providerInfoCmd = &cobra.Command{
@@ -51,11 +109,29 @@ func init() {
 	providerAddCmd.Flags().String("base-url", "", "Base URL for the provider")
 	providerAddCmd.Flags().String("model", "", "Default model for the provider")
 	providerAddCmd.Flags().Int("timeout", 0, "Timeout in milliseconds")
+	providerAddCmd.Flags().String("kind", "", "Provider kind: azure for Azure OpenAI deployments (default: OpenAI/Anthropic-compatible)")
+	providerAddCmd.Flags().String("display-name", "", "Name codex shows for the provider (default: the provider name)")
+	providerAddCmd.Flags().String("env-key", "", "Environment variable that passes the API key to codex (default: <NAME>_API_KEY)")
+	providerAddCmd.Flags().String("wire-api", "", "Codex wire protocol: chat or responses")
+	providerAddCmd.Flags().StringToString("query-param", nil, "Extra query parameter on every request, key=value (repeatable)")
+	providerAddCmd.Flags().StringToString("header", nil, "Extra HTTP header on every request, name=value; values may use {key}, {keys.<name>} or ${VAR} (repeatable)")
+	providerAddCmd.Flags().String("auth-scheme", "", "How the key is sent: bearer, x-api-key or none (default: bearer)")
+	providerAddCmd.Flags().String("builtin", "", "Create the provider and tool profiles from a builtin provider preset")
+	providerAddCmd.Flags().String("endpoint", "", "Endpoint preset of the builtin provider (default: its first endpoint)")
+	providerAddCmd.Flags().String("region", "", "Region of the builtin provider's hosts: cn, intl (default: settings.region)")
+	providerAddCmd.Flags().Bool("dry-run", false, "Show the configuration that would be added without saving")
+
+	// Flags for remove command
+	addRemoveFlags(providerRemoveCmd, "provider")
+
+	// Flags for rename command
+	providerRenameCmd.Flags().Bool("dry-run", false, "List the references that would change without renaming")
 
 	// Add subcommands
 	providerCmd.AddCommand(providerListCmd)
 	providerCmd.AddCommand(providerAddCmd)
 	providerCmd.AddCommand(providerRemoveCmd)
+	providerCmd.AddCommand(providerRenameCmd)
 	providerCmd.AddCommand(providerInfoCmd)
 }
 
@@ -86,6 +162,9 @@ func runProviderList(cmd *cobra.Command, args []string) error {
 		fmt.Println("\nGlobal Provider Configurations:")
 		for name, provider := range cfg.Providers {
 			fmt.Printf("  • %-20s (global)\n", name)
+			if provider != nil && provider.Kind != "" {
+				fmt.Printf("    Kind: %s\n", provider.Kind)
+			}
 			if provider != nil && provider.BaseURL != "" {
 				fmt.Printf("    Base URL: %s\n", provider.BaseURL)
 			}
@@ -95,6 +174,9 @@ func runProviderList(cmd *cobra.Command, args []string) error {
 			if provider != nil && provider.Timeout > 0 {
 				fmt.Printf("    Timeout: %dms\n", provider.Timeout)
 			}
+			if provider != nil {
+				printProviderWiring("    ", &provider.ProviderWiring)
+			}
 			fmt.Println()
 		}
 	} else {
@@ -107,6 +189,17 @@ func runProviderList(cmd *cobra.Command, args []string) error {
 }
 
 func runProviderAdd(cmd *cobra.Command, args []string) error {
+	builtinName, _ := cmd.Flags().GetString("builtin")
+	if builtinName != "" {
+		return runProviderAddBuiltin(cmd, args, builtinName)
+	}
+	if cmd.Flags().Changed("region") {
+		return fmt.Errorf("--region requires --builtin; set --base-url for a custom provider instead")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("provider name is required (or use --builtin <name> to add a builtin provider)")
+	}
+
 	providerName := args[0]
 
 	// Get global configuration manager
@@ -121,11 +214,46 @@ func runProviderAdd(cmd *cobra.Command, args []string) error {
 	baseURL, _ := cmd.Flags().GetString("base-url")
 	model, _ := cmd.Flags().GetString("model")
 	timeout, _ := cmd.Flags().GetInt("timeout")
+	kind, _ := cmd.Flags().GetString("kind")
+	var wiring config.ProviderWiring
+	wiring.DisplayName, _ = cmd.Flags().GetString("display-name")
+	wiring.EnvKey, _ = cmd.Flags().GetString("env-key")
+	wiring.WireAPI, _ = cmd.Flags().GetString("wire-api")
+	wiring.QueryParams, _ = cmd.Flags().GetStringToString("query-param")
+	wiring.Headers, _ = cmd.Flags().GetStringToString("header")
+	wiring.AuthScheme, _ = cmd.Flags().GetString("auth-scheme")
+
+	if err := config.ValidateProviderKind(kind); err != nil {
+		return err
+	}
+	if err := config.ValidateWireAPI(wiring.WireAPI); err != nil {
+		return err
+	}
+	if err := config.ValidateAuthScheme(wiring.AuthScheme); err != nil {
+		return err
+	}
+	if kind == config.ProviderKindAzure && baseURL == "" {
+		return fmt.Errorf("--base-url is required for Azure OpenAI, e.g. https://<resource>.openai.azure.com/openai")
+	}
+	if len(wiring.QueryParams) == 0 {
+		wiring.QueryParams = nil
+	}
+	if len(wiring.Headers) == 0 {
+		wiring.Headers = nil
+	}
+
+	// Codex selects providers through profiles; its wiring comes from the provider entry
+	var profileTools []string
+	if toolCfg, ok := cfg.GetTool("codex"); ok {
+		if _, exists := toolCfg.Profiles[providerName]; !exists {
+			profileTools = append(profileTools, "codex")
+		}
+	}
 
 	// Update configuration
 	err := cm.UpdateConfig(func(cfg *config.Config) {
 		// Create provider configuration
-		provider := &config.Provider{}
+		provider := &config.Provider{Kind: kind, ProviderWiring: wiring}
 
 		if baseURL != "" {
 			provider.BaseURL = baseURL
@@ -142,6 +270,14 @@ func runProviderAdd(cmd *cobra.Command, args []string) error {
 			cfg.Providers = make(map[string]*config.Provider)
 		}
 		cfg.Providers[providerName] = provider
+
+		for _, toolName := range profileTools {
+			toolCfg := cfg.Tools[toolName]
+			if toolCfg.Profiles == nil {
+				toolCfg.Profiles = make(map[string]*config.ToolProfile)
+			}
+			toolCfg.Profiles[providerName] = &config.ToolProfile{Provider: providerName}
+		}
 	})
 
 	if err != nil {
@@ -154,6 +290,9 @@ func runProviderAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("✓ Added global provider configuration '%s'\n", providerName)
+	if kind != "" {
+		fmt.Printf("  Kind: %s\n", kind)
+	}
 	if baseURL != "" {
 		fmt.Printf("  Base URL: %s\n", baseURL)
 	}
@@ -163,39 +302,44 @@ func runProviderAdd(cmd *cobra.Command, args []string) error {
 	if timeout > 0 {
 		fmt.Printf("  Timeout: %dms\n", timeout)
 	}
+	printProviderWiring("  ", &wiring)
+	for _, toolName := range profileTools {
+		fmt.Printf("  • %s profile: %s\n", toolName, providerName)
+	}
+	if kind == config.ProviderKindAzure {
+		fmt.Printf("\nNext: aim keys add <key-name> --provider %s --key <api-key> --deployment <deployment>\n", providerName)
+	}
 
 	return nil
 }
 
 func runProviderRemove(cmd *cobra.Command, args []string) error {
 	providerName := args[0]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 	// Get global configuration manager
 	cm := config.GetConfigManager()
-	cfg := cm.GetConfig()
 
-	// Check if provider exists
-	if _, exists := cfg.GetProvider(providerName); !exists {
-		return fmt.Errorf("provider '%s' not found", providerName)
+	plan, err := config.PlanProviderRemoval(cm.GetConfig(), cm.GetState(), providerName, removeOptions(cmd))
+	if err != nil {
+		return explainReferenced(err)
 	}
 
-	// Update configuration
-	err := cm.UpdateConfig(func(cfg *config.Config) {
-		delete(cfg.Providers, providerName)
-	})
+	return executeRemoval(plan, dryRun)
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to update config: %w", err)
-	}
+func runProviderRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-	// Force save to disk immediately
-	if err := cm.ForceSave(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
-	}
+	cm := config.GetConfigManager()
 
-	fmt.Printf("✓ Removed global provider configuration '%s'\n", providerName)
+	plan, err := config.PlanProviderRename(cm.GetConfig(), cm.GetState(), oldName, newName)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return executeRename(plan, nil, dryRun)
 }
 
 func runProviderInfo(cmd *cobra.Command, args []string) error {
@@ -227,6 +371,9 @@ func runProviderInfo(cmd *cobra.Command, args []string) error {
 	globalProvider, hasGlobal := cfg.GetProvider(providerName)
 	if hasGlobal {
 		fmt.Printf("  Type: global configuration\n")
+		if globalProvider.Kind != "" {
+			fmt.Printf("  Kind: %s\n", globalProvider.Kind)
+		}
 		if globalProvider.BaseURL != "" {
 			fmt.Printf("  Base URL: %s\n", globalProvider.BaseURL)
 		}
@@ -236,6 +383,7 @@ func runProviderInfo(cmd *cobra.Command, args []string) error {
 		if globalProvider.Timeout > 0 {
 			fmt.Printf("  Timeout: %dms\n", globalProvider.Timeout)
 		}
+		printProviderWiring("  ", &globalProvider.ProviderWiring)
 	}
 
 	if !isBuiltin && !hasGlobal {
@@ -261,3 +409,132 @@ func runProviderInfo(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runProviderAddBuiltin(cmd *cobra.Command, args []string, builtinName string) error {
+	endpointName, _ := cmd.Flags().GetString("endpoint")
+	region, _ := cmd.Flags().GetString("region")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	for _, flag := range []string{"base-url", "model", "timeout", "kind", "display-name", "env-key", "wire-api", "query-param", "header", "auth-scheme"} {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("--%s cannot be combined with --builtin; edit the generated profiles instead", flag)
+		}
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	cm := config.GetConfigManager()
+
+	m, err := config.MaterializeBuiltinProvider(cm.GetConfig(), builtinName, endpointName, name, region)
+	if err != nil {
+		return err
+	}
+
+	preview := map[string]interface{}{
+		"providers": map[string]*config.Provider{m.Name: m.Provider},
+	}
+	tools := make(map[string]interface{})
+	for toolName, profile := range m.Profiles {
+		tools[toolName] = map[string]interface{}{
+			"profiles": map[string]*config.ToolProfile{m.Name: profile},
+		}
+	}
+	if len(tools) > 0 {
+		preview["tools"] = tools
+	}
+
+	data, err := yaml.Marshal(preview)
+	if err != nil {
+		return fmt.Errorf("failed to render configuration: %w", err)
+	}
+
+	if dryRun {
+		fmt.Printf("Would add provider '%s' from builtin '%s' (%s):\n\n", m.Name, m.Builtin, presetLabel(m))
+		fmt.Print(string(data))
+		for _, toolName := range m.Skipped {
+			fmt.Printf("\n⚠️  Tool '%s' is not configured; no profile would be created for it\n", toolName)
+		}
+		fmt.Println("\nDry run: no changes saved")
+		return nil
+	}
+
+	if err := cm.UpdateConfig(m.Apply); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	// Force save to disk immediately
+	if err := cm.ForceSave(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Added provider '%s' from builtin '%s' (%s)\n", m.Name, m.Builtin, presetLabel(m))
+	for _, toolName := range sortedMapKeys(m.Profiles) {
+		fmt.Printf("  • %s profile: %s\n", toolName, m.Name)
+	}
+	for _, toolName := range m.Skipped {
+		fmt.Printf("  ⚠️  Tool '%s' is not configured; skipped\n", toolName)
+	}
+	switch {
+	case provider.IsLocalProvider(m.Builtin):
+		fmt.Printf("\nNext: aim keys add <key-name> --provider %s (no API key needed)\n", m.Name)
+	case provider.CredentialType(m.Builtin) != "":
+		fmt.Printf("\nNext: aim keys add <key-name> --provider %s with --%s-* credential flags (see 'aim keys add --help')\n", m.Name, provider.CredentialType(m.Builtin))
+	default:
+		fmt.Printf("\nNext: aim keys add <key-name> --provider %s --key <api-key>\n", m.Name)
+	}
+
+	return nil
+}
+
+// presetLabel describes the endpoint preset and region a provider was created from
+func presetLabel(m *config.BuiltinMaterialization) string {
+	if m.Region == "" {
+		return "endpoint: " + m.Endpoint
+	}
+	return fmt.Sprintf("endpoint: %s, region: %s", m.Endpoint, m.Region)
+}
+
+// sortedMapKeys returns profile map keys in stable order
+func sortedMapKeys(profiles map[string]*config.ToolProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printProviderWiring prints the wiring fields that are set
+func printProviderWiring(indent string, w *config.ProviderWiring) {
+	if w.DisplayName != "" {
+		fmt.Printf("%sDisplay name: %s\n", indent, w.DisplayName)
+	}
+	if w.EnvKey != "" {
+		fmt.Printf("%sEnv key: %s\n", indent, w.EnvKey)
+	}
+	if w.WireAPI != "" {
+		fmt.Printf("%sWire API: %s\n", indent, w.WireAPI)
+	}
+	if w.AuthScheme != "" {
+		fmt.Printf("%sAuth scheme: %s\n", indent, w.AuthScheme)
+	}
+	for _, name := range sortedStringKeys(w.QueryParams) {
+		fmt.Printf("%sQuery param: %s=%s\n", indent, name, w.QueryParams[name])
+	}
+	for _, name := range sortedStringKeys(w.Headers) {
+		fmt.Printf("%sHeader: %s: %s\n", indent, name, w.Headers[name])
+	}
+}
+
+// sortedStringKeys returns map keys in stable order
+func sortedStringKeys(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
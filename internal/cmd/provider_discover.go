@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/models"
+	"github.com/fakecore/aim/internal/probe"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/fakecore/aim/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var providerDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find local model servers and their models",
+	Long: `Probe the default ports of local model servers (Ollama on 11434, LM Studio
+on 1234, llama.cpp server on 8080) and any configured provider whose base URL
+points at this machine, and list the models each one serves.
+
+Discovered models are cached for completion of 'aim run --model'.
+Local providers need no API key:
+  aim provider add --builtin ollama
+  aim keys add ollama --provider ollama
+  aim run codex --key ollama --model qwen3-coder:30b
+
+Examples:
+  aim provider discover
+  aim provider discover --json`,
+	Args: cobra.NoArgs,
+	RunE: runProviderDiscover,
+}
+
+func init() {
+	providerDiscoverCmd.Flags().Duration("timeout", 2*time.Second, "Timeout for each server")
+	providerDiscoverCmd.Flags().Bool("json", false, "Output as JSON")
+
+	providerCmd.AddCommand(providerDiscoverCmd)
+}
+
+// localServer is one local endpoint probed by discovery
+type localServer struct {
+	Provider   string   `json:"provider"`
+	Builtin    string   `json:"builtin,omitempty"`
+	BaseURL    string   `json:"base_url"`
+	Running    bool     `json:"running"`
+	Models     []string `json:"models,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Configured bool     `json:"configured"`
+	cacheNames []string // Providers whose model cache is refreshed from this server
+}
+
+func runProviderDiscover(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cfg := config.GetConfigManager().GetConfig()
+	servers := localServers(cfg)
+
+	prober := probe.NewProber(&http.Client{Timeout: timeout})
+	var wg sync.WaitGroup
+	for i := range servers {
+		wg.Add(1)
+		go func(s *localServer) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			list, err := prober.ListModels(ctx, probe.Target{Protocol: tool.ProtocolOpenAI, BaseURL: s.BaseURL})
+			if err != nil {
+				s.Error = err.Error()
+				return
+			}
+			s.Running = true
+			s.Models = list
+		}(&servers[i])
+	}
+	wg.Wait()
+
+	cache := models.NewCache()
+	running := 0
+	for _, s := range servers {
+		if !s.Running {
+			continue
+		}
+		running++
+		for _, name := range s.cacheNames {
+			_, _ = cache.Save(name, s.BaseURL, s.Models)
+		}
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(servers, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, s := range servers {
+		if !s.Running {
+			fmt.Printf("❌ %s at %s: not reachable\n", s.Provider, s.BaseURL)
+			continue
+		}
+		fmt.Printf("✓ %s at %s: %d model(s)\n", s.Provider, s.BaseURL, len(s.Models))
+		for _, m := range s.Models {
+			fmt.Printf("    • %s\n", m)
+		}
+		if !s.Configured {
+			fmt.Printf("    Not configured yet: aim provider add --builtin %s && aim keys add %s --provider %s\n", s.Builtin, s.Builtin, s.Builtin)
+		}
+	}
+
+	if running == 0 {
+		fmt.Println("\nNo local model servers found.")
+		return nil
+	}
+	fmt.Println("\nUse a model with: aim run codex --key <key-name> --model <model>")
+	return nil
+}
+
+// localServers lists builtin local endpoints plus configured providers on loopback addresses, deduplicated by URL
+func localServers(cfg *config.Config) []localServer {
+	var servers []localServer
+	byURL := make(map[string]int)
+
+	add := func(s localServer, name string) {
+		key := strings.TrimRight(s.BaseURL, "/")
+		if i, ok := byURL[key]; ok {
+			servers[i].cacheNames = append(servers[i].cacheNames, name)
+			servers[i].Configured = servers[i].Configured || s.Configured
+			return
+		}
+		s.cacheNames = []string{name}
+		byURL[key] = len(servers)
+		servers = append(servers, s)
+	}
+
+	for _, name := range provider.LocalProviderNames() {
+		ep, err := provider.GetDefaultEndpoint(name)
+		if err != nil {
+			continue
+		}
+		codex, ok := ep.Tools[string(tool.ToolTypeCodex)]
+		if !ok || codex.BaseURL == "" {
+			continue
+		}
+		_, configured := cfg.GetProvider(name)
+		add(localServer{Provider: name, Builtin: name, BaseURL: codex.BaseURL, Configured: configured}, name)
+	}
+
+	names := make([]string, 0, len(cfg.Providers))
+	for name := range cfg.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := cfg.Providers[name]
+		if p == nil || !provider.IsLoopbackURL(p.BaseURL) {
+			continue
+		}
+		add(localServer{Provider: name, BaseURL: p.BaseURL, Configured: true}, name)
+	}
+
+	return servers
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/models"
+	"github.com/fakecore/aim/internal/probe"
+	"github.com/fakecore/aim/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var providerModelsCmd = &cobra.Command{
+	Use:   "models <provider>",
+	Short: "List models served by a provider",
+	Long: `List model IDs from the provider's /models endpoint using a configured key.
+
+OpenAI-compatible endpoints (codex profiles) are queried first, then
+Anthropic-compatible endpoints (claude-code profiles) where supported.
+Results are cached under ~/.aim/cache/models for 24 hours and power shell
+completion of 'aim run --model'.
+
+Examples:
+  aim provider models deepseek
+  aim provider models glm --key glm-work --refresh
+  aim provider models kimi --tool claude-code --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProviderModels,
+}
+
+func init() {
+	providerModelsCmd.Flags().String("key", "", "Key to authenticate with (default: a key for this provider)")
+	providerModelsCmd.Flags().String("tool", "", "Only query this tool's endpoint for the provider")
+	providerModelsCmd.Flags().Bool("refresh", false, "Ignore the cache and query the provider")
+	providerModelsCmd.Flags().Duration("timeout", 15*time.Second, "Request timeout")
+	providerModelsCmd.Flags().Bool("json", false, "Output as JSON")
+
+	providerCmd.AddCommand(providerModelsCmd)
+}
+
+func runProviderModels(cmd *cobra.Command, args []string) error {
+	providerName := args[0]
+	keyName, _ := cmd.Flags().GetString("key")
+	toolName, _ := cmd.Flags().GetString("tool")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cfg := config.GetConfigManager().GetConfig()
+	cache := models.NewCache()
+
+	// The cache is keyed by the endpoint, so resolve it before looking anything up
+	targets, errs, err := resolveModelsTargets(cfg, providerName, keyName, toolName)
+	if err != nil {
+		return err
+	}
+
+	entry, exists, fresh := loadCachedModels(cache, providerName, targetBaseURLs(targets))
+	if refresh || !exists || !fresh {
+		fetched, err := fetchProviderModels(cache, providerName, targets, errs, timeout)
+		if err != nil {
+			if !exists {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to refresh models, showing cached list: %v\n", err)
+		} else {
+			entry = fetched
+		}
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Models for provider '%s' (%d):\n", providerName, len(entry.Models))
+	for _, m := range entry.Models {
+		fmt.Printf("  • %s\n", m)
+	}
+	fmt.Printf("\nSource: %s (fetched %s ago)\n", entry.BaseURL, entry.Age().Round(time.Second))
+
+	return nil
+}
+
+// modelsTarget is a tool profile whose endpoint can list a provider's models
+type modelsTarget struct {
+	tool    string
+	runtime *config.RuntimeConfig
+}
+
+// resolveModelsTargets resolves the endpoints to list a provider's models from, OpenAI-compatible first
+// Candidates that fail to resolve are returned as messages, for reporting when none of the others answer.
+func resolveModelsTargets(cfg *config.Config, providerName, keyName, toolName string) ([]modelsTarget, []string, error) {
+	if keyName == "" {
+		keyName = keyForProvider(cfg, providerName)
+		if keyName == "" {
+			return nil, nil, fmt.Errorf("no key configured for provider '%s'. Use --key <key-name> or add one with 'aim keys add'", providerName)
+		}
+	}
+
+	resolver := config.NewResolver(cfg)
+	if err := resolver.ValidateKey(keyName); err != nil {
+		return nil, nil, fmt.Errorf("invalid key: %w", err)
+	}
+
+	var targets []modelsTarget
+	var errs []string
+	for _, candidate := range modelsCandidateTools(cfg, providerName, toolName) {
+		runtime, err := resolver.Resolve(candidate, keyName, providerName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", candidate, err))
+			continue
+		}
+		targets = append(targets, modelsTarget{tool: candidate, runtime: runtime})
+	}
+
+	if len(targets) == 0 && len(errs) == 0 {
+		return nil, nil, fmt.Errorf("no tool profile configured for provider '%s'", providerName)
+	}
+	return targets, errs, nil
+}
+
+// targetBaseURLs returns the targets' base URLs in query order
+func targetBaseURLs(targets []modelsTarget) []string {
+	urls := make([]string, len(targets))
+	for i, target := range targets {
+		urls[i] = target.runtime.BaseURL
+	}
+	return urls
+}
+
+// loadCachedModels returns the cached list of the first base URL that has one
+func loadCachedModels(cache *models.Cache, providerName string, baseURLs []string) (*models.CacheEntry, bool, bool) {
+	for _, baseURL := range baseURLs {
+		if entry, exists, fresh := cache.Load(providerName, baseURL); exists {
+			return entry, exists, fresh
+		}
+	}
+	return nil, false, false
+}
+
+// fetchProviderModels queries the targets' /models endpoints in order and caches the first answer
+func fetchProviderModels(cache *models.Cache, providerName string, targets []modelsTarget, errs []string, timeout time.Duration) (*models.CacheEntry, error) {
+	prober := probe.NewProber(&http.Client{Timeout: timeout})
+
+	for _, target := range targets {
+		protocol, _ := tool.GetProtocol(target.tool)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		list, err := prober.ListModels(ctx, probeTarget(protocol, target.runtime))
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s (%s): %v", target.tool, target.runtime.BaseURL, err))
+			continue
+		}
+
+		return cache.Save(providerName, target.runtime.BaseURL, list)
+	}
+
+	return nil, fmt.Errorf("failed to list models for provider '%s':\n  %s", providerName, strings.Join(errs, "\n  "))
+}
+
+// modelsCandidateTools returns tools with a profile for the provider, OpenAI-compatible endpoints first
+func modelsCandidateTools(cfg *config.Config, providerName, toolName string) []string {
+	if toolName != "" {
+		return []string{tool.GetCanonicalName(toolName)}
+	}
+
+	var candidates []string
+	for name, toolCfg := range cfg.Tools {
+		if _, ok := tool.GetProtocol(name); !ok {
+			continue
+		}
+		if _, ok := toolCfg.Profiles[providerName]; ok {
+			candidates = append(candidates, name)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		pi, _ := tool.GetProtocol(candidates[i])
+		pj, _ := tool.GetProtocol(candidates[j])
+		if pi != pj {
+			return pi == tool.ProtocolOpenAI
+		}
+		return candidates[i] < candidates[j]
+	})
+
+	return candidates
+}
+
+// keyForProvider picks a key for a provider, preferring the default key
+func keyForProvider(cfg *config.Config, providerName string) string {
+	if key, ok := cfg.GetKey(cfg.Settings.DefaultKey); ok && key.Provider == providerName {
+		return cfg.Settings.DefaultKey
+	}
+	for _, keyName := range sortedKeyNames(cfg) {
+		if cfg.Keys[keyName] != nil && cfg.Keys[keyName].Provider == providerName {
+			return keyName
+		}
+	}
+	return ""
+}
+
+// sortedKeyNames returns configured key names in stable order
+func sortedKeyNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Keys))
+	for name := range cfg.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeRunModel offers cached model IDs for 'aim run --model'
+func completeRunModel(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := config.GetConfigManager().GetConfig()
+
+	providerName, _ := cmd.Flags().GetString("provider")
+	keyName, _ := cmd.Flags().GetString("key")
+	if providerName == "" {
+		if keyName == "" {
+			keyName = cfg.Settings.DefaultKey
+		}
+		if key, ok := cfg.GetKey(keyName); ok {
+			providerName = key.Provider
+		}
+	}
+	if providerName == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var matches []string
+	if len(args) > 0 {
+		aliases, _ := config.NewResolver(cfg).ModelAliases(tool.GetCanonicalName(args[0]), providerName)
+		for _, alias := range config.ModelAliasNames(aliases) {
+			if strings.HasPrefix(alias, toComplete) {
+				matches = append(matches, alias)
+			}
+		}
+	}
+
+	// Stale entries are still better than nothing for completion
+	targets, _, _ := resolveModelsTargets(cfg, providerName, keyName, "")
+	entry, exists, _ := loadCachedModels(models.NewCache(), providerName, targetBaseURLs(targets))
+	if !exists {
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	for _, m := range entry.Models {
+		if strings.HasPrefix(m, toComplete) {
+			matches = append(matches, m)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// warnUnknownModel warns when a model is missing from the cached model list of the runtime's endpoint
+// The endpoint being run is checked first, then the ones 'aim provider models' queries for the profile.
+func warnUnknownModel(cfg *config.Config, runtime *config.RuntimeConfig) {
+	if runtime.Model == "" || runtime.Model == "-" {
+		return
+	}
+
+	targets, _, _ := resolveModelsTargets(cfg, runtime.Profile, runtime.Key, "")
+	baseURLs := append([]string{runtime.BaseURL}, targetBaseURLs(targets)...)
+	entry, exists, _ := loadCachedModels(models.NewCache(), runtime.Profile, baseURLs)
+	if !exists || len(entry.Models) == 0 || entry.Contains(runtime.Model) {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  Model '%s' is not in the cached model list for provider '%s' (fetched %s ago)\n",
+		runtime.Model, runtime.Profile, entry.Age().Round(time.Minute))
+	fmt.Fprintf(os.Stderr, "   Refresh with: aim provider models %s --refresh\n", runtime.Profile)
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// maxManifestSize bounds downloaded provider manifests
+const maxManifestSize = 4 * 1024 * 1024
+
+var providerSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Update builtin provider definitions from a manifest",
+	Long: `Fetch a versioned provider manifest and install it into providers.d.
+
+Provider definitions are read from the providers.d directory next to the
+global config (~/.config/aim/providers.d) and merged over the compiled-in
+builtin providers: endpoints merge by name and models by ID. Files are applied
+in name order; the synced manifest is stored as 00-catalog.yaml so
+hand-written files override it.
+
+The manifest must carry a positive "version" and is verified against a
+SHA-256 checksum, given with --sha256 or read from "<from>.sha256".
+Older manifests are refused unless --force is given.
+
+Examples:
+  aim provider sync --from https://example.com/aim/providers.yaml
+  aim provider sync --from ./providers.yaml --sha256 3f2a... --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runProviderSync,
+}
+
+func init() {
+	providerSyncCmd.Flags().String("from", "", "Manifest path or http(s) URL (required)")
+	providerSyncCmd.Flags().String("sha256", "", "Expected SHA-256 of the manifest (default: read <from>.sha256)")
+	providerSyncCmd.Flags().Bool("insecure-skip-checksum", false, "Install without verifying the checksum")
+	providerSyncCmd.Flags().Bool("force", false, "Install even if the manifest is older than the installed one")
+	providerSyncCmd.Flags().Bool("dry-run", false, "Show changes without installing")
+	providerSyncCmd.Flags().Duration("timeout", 30*time.Second, "Download timeout")
+	_ = providerSyncCmd.MarkFlagRequired("from")
+
+	providerCmd.AddCommand(providerSyncCmd)
+}
+
+func runProviderSync(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	expected, _ := cmd.Flags().GetString("sha256")
+	skipChecksum, _ := cmd.Flags().GetBool("insecure-skip-checksum")
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	client := &http.Client{Timeout: timeout}
+
+	data, err := fetchManifest(client, from)
+	if err != nil {
+		return err
+	}
+
+	// Verify the checksum before parsing anything from the manifest
+	if expected == "" && !skipChecksum {
+		sidecar, err := fetchManifest(client, from+".sha256")
+		if err != nil {
+			return fmt.Errorf("no checksum available: %w. Pass --sha256 <hex> or --insecure-skip-checksum", err)
+		}
+		fields := strings.Fields(string(sidecar))
+		if len(fields) == 0 {
+			return fmt.Errorf("checksum file %s.sha256 is empty", from)
+		}
+		expected = fields[0]
+	}
+	if expected != "" {
+		sum := sha256.Sum256(data)
+		actual := hex.EncodeToString(sum[:])
+		if !strings.EqualFold(actual, expected) {
+			return fmt.Errorf("checksum mismatch: expected %s, got %s", strings.ToLower(expected), actual)
+		}
+		fmt.Printf("✓ Checksum verified (sha256 %s)\n", actual)
+	} else {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping checksum verification\n")
+	}
+
+	catalog, err := provider.ParseCatalog(data)
+	if err != nil {
+		return err
+	}
+	if catalog.Version <= 0 {
+		return fmt.Errorf("manifest has no version; expected a positive 'version' field")
+	}
+
+	dir := config.GetConfigManager().GetProvidersDir()
+	target := filepath.Join(dir, provider.SyncedCatalogFile)
+
+	catalogs, err := provider.ReadCatalogDir(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+	}
+	if catalogs == nil {
+		catalogs = make(map[string]*provider.Catalog)
+	}
+
+	if installed, ok := catalogs[provider.SyncedCatalogFile]; ok {
+		switch {
+		case catalog.Version < installed.Version && !force:
+			return fmt.Errorf("manifest version %d is older than installed version %d. Use --force to downgrade", catalog.Version, installed.Version)
+		case catalog.Version == installed.Version && !force:
+			fmt.Printf("✓ Provider definitions already up to date (version %d)\n", catalog.Version)
+			return nil
+		}
+		fmt.Printf("Manifest version %d (installed: %d)\n", catalog.Version, installed.Version)
+	} else {
+		fmt.Printf("Manifest version %d (installed: none)\n", catalog.Version)
+	}
+
+	catalogs[provider.SyncedCatalogFile] = catalog
+	changes := provider.DiffProviders(provider.GetBuiltinProviders(), provider.MergeCatalogs(catalogs))
+	printProviderChanges(changes)
+
+	if dryRun {
+		fmt.Println("\n(dry run, nothing installed)")
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, constants.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(target, data, constants.ConfigFileMode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	fmt.Printf("\n✓ Installed provider definitions to %s\n", target)
+	return nil
+}
+
+// fetchManifest reads a manifest from an http(s) URL or a local path
+func fetchManifest(client *http.Client, from string) ([]byte, error) {
+	if !strings.HasPrefix(from, "http://") && !strings.HasPrefix(from, "https://") {
+		data, err := os.ReadFile(from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", from, err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", from, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", from, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: HTTP %d", from, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", from, err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("manifest %s exceeds %d bytes", from, maxManifestSize)
+	}
+	return data, nil
+}
+
+// printProviderChanges prints a provider registry diff
+func printProviderChanges(changes []provider.ProviderChange) {
+	if len(changes) == 0 {
+		fmt.Println("No changes to provider definitions.")
+		return
+	}
+
+	fmt.Println("\nChanges:")
+	for _, c := range changes {
+		mark := "~"
+		switch c.Kind {
+		case "added":
+			mark = "+"
+		case "removed":
+			mark = "-"
+		}
+		fmt.Printf("  %s %s (%s)\n", mark, c.Provider, c.Kind)
+		for _, d := range c.Details {
+			fmt.Printf("      %s\n", d)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/spf13/pflag"
+)
+
+// syncManifest returns a manifest renaming deepseek, tagged with version
+func syncManifest(version string) string {
+	return "version: " + version + "\nproviders:\n  - name: deepseek\n    display_name: DeepSeek v" + version + "\n"
+}
+
+// runSync runs 'aim provider sync' with args, starting from default flag values
+func runSync(t *testing.T, args ...string) error {
+	t.Helper()
+	providerSyncCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+	if err := providerSyncCmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return runProviderSync(providerSyncCmd, nil)
+}
+
+func TestProviderSync(t *testing.T) {
+	manifests := map[string]string{
+		"/v1.yaml":          syncManifest("1"),
+		"/v2.yaml":          syncManifest("2"),
+		"/unversioned.yaml": "providers:\n  - name: deepseek\n",
+		"/tampered.yaml":    syncManifest("3"),
+	}
+	sums := make(map[string]string)
+	for path, body := range manifests {
+		sum := sha256.Sum256([]byte(body))
+		sums[path+".sha256"] = hex.EncodeToString(sum[:]) + "  manifest.yaml\n"
+	}
+	sums["/tampered.yaml.sha256"] = sums["/v1.yaml.sha256"]
+	delete(sums, "/v2.yaml.sha256")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, ok := manifests[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		if body, ok := sums[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("AIM_CONFIG_PATH", filepath.Join(dir, "config.yaml"))
+	providersDir := config.GetConfigManager().GetProvidersDir()
+	if !strings.HasPrefix(providersDir, dir) {
+		t.Fatalf("providers.d = %s, want it under the test directory", providersDir)
+	}
+	target := filepath.Join(providersDir, provider.SyncedCatalogFile)
+	installed := func() string {
+		data, _ := os.ReadFile(target)
+		return string(data)
+	}
+
+	v2Sum := sha256.Sum256([]byte(manifests["/v2.yaml"]))
+	v2 := []string{"--from", server.URL + "/v2.yaml", "--sha256", hex.EncodeToString(v2Sum[:])}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		want    string // Installed manifest afterwards
+	}{
+		{
+			name:    "checksum mismatch",
+			args:    []string{"--from", server.URL + "/tampered.yaml"},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "no checksum",
+			args:    []string{"--from", server.URL + "/v2.yaml"},
+			wantErr: "no checksum available",
+		},
+		{
+			name:    "no version",
+			args:    []string{"--from", server.URL + "/unversioned.yaml"},
+			wantErr: "no version",
+		},
+		{
+			name: "dry run",
+			args: append([]string{"--dry-run"}, v2...),
+		},
+		{
+			name: "install",
+			args: v2,
+			want: manifests["/v2.yaml"],
+		},
+		{
+			name: "same version",
+			args: []string{"--from", server.URL + "/v2.yaml", "--insecure-skip-checksum"},
+			want: manifests["/v2.yaml"],
+		},
+		{
+			name:    "downgrade refused",
+			args:    []string{"--from", server.URL + "/v1.yaml"},
+			wantErr: "older than installed version 2",
+			want:    manifests["/v2.yaml"],
+		},
+		{
+			name: "downgrade forced",
+			args: []string{"--from", server.URL + "/v1.yaml", "--force"},
+			want: manifests["/v1.yaml"],
+		},
+	}
+
+	// Cases run in order: each starts from the previous one's installed manifest
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runSync(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := installed(); got != tt.want {
+				t.Errorf("installed manifest = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
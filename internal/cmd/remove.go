@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fakecore/aim/internal/audit"
+	"github.com/fakecore/aim/internal/config"
+)
+
+// addRemoveFlags adds the flags that decide what happens to references of a removed entity
+func addRemoveFlags(cmd *cobra.Command, kind string) {
+	cmd.Flags().Bool("cascade", false, "Also remove everything that references the "+kind)
+	cmd.Flags().String("reassign", "", "Point references at this "+kind+" instead")
+	cmd.Flags().Bool("dry-run", false, "List the changes without removing anything")
+}
+
+// removeOptions reads the flags added by addRemoveFlags
+func removeOptions(cmd *cobra.Command) config.RemoveOptions {
+	cascade, _ := cmd.Flags().GetBool("cascade")
+	reassign, _ := cmd.Flags().GetString("reassign")
+	return config.RemoveOptions{Cascade: cascade, Reassign: reassign}
+}
+
+// explainReferenced prints the references that block a removal
+func explainReferenced(err error) error {
+	var refErr *config.ReferencedError
+	if errors.As(err, &refErr) {
+		fmt.Printf("❌ Cannot remove %s '%s', it is still referenced by:\n\n", refErr.Entity.Kind, refErr.Entity.Name)
+		for _, edge := range refErr.References {
+			fmt.Printf("  • %-45s (%s)\n", edge.Location, edge.From)
+		}
+		fmt.Println()
+	}
+	return err
+}
+
+// executeRemoval previews or applies a removal plan; config and state are written in one transaction
+func executeRemoval(plan *config.RemovalPlan, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("Dry run: removing %s '%s' would change %d entry(s):\n\n", plan.Kind, plan.Name, len(plan.References))
+	} else {
+		fmt.Printf("Removing %s '%s':\n\n", plan.Kind, plan.Name)
+	}
+
+	for _, ref := range plan.References {
+		if ref.New == "" {
+			fmt.Printf("  • %-45s removed\n", ref.Location)
+		} else {
+			fmt.Printf("  • %-45s %s → %s\n", ref.Location, ref.Old, ref.New)
+		}
+	}
+
+	if dryRun {
+		fmt.Println("\nNo changes were made.")
+		return nil
+	}
+
+	cm := config.GetConfigManager()
+	if err := cm.Transaction(plan.Apply, nil); err != nil {
+		return err
+	}
+
+	for _, keyName := range plan.Keys {
+		audit.Record(audit.ActionRemove, keyName, "", "", "")
+	}
+
+	fmt.Printf("\n✓ Removed %s '%s' (%d entry(s) changed)\n", plan.Kind, plan.Name, len(plan.References))
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/setup"
+)
+
+// executeRename previews or applies a rename plan together with managed tool config rewrites
+// Config, state and tool configs are written in one transaction
+func executeRename(plan *config.RenamePlan, managed []setup.ManagedConfigChange, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("Dry run: renaming %s '%s' to '%s' would change %d reference(s):\n\n", plan.Kind, plan.Old, plan.New, len(plan.References)+len(managed))
+	} else {
+		fmt.Printf("Renaming %s '%s' to '%s':\n\n", plan.Kind, plan.Old, plan.New)
+	}
+
+	for _, ref := range plan.References {
+		fmt.Printf("  • %-45s %s → %s\n", ref.Location, ref.Old, ref.New)
+	}
+
+	var extra []config.FileWrite
+	written := make(map[string]bool)
+	for _, change := range managed {
+		fmt.Printf("  • %-45s %s → %s\n", fmt.Sprintf("%s: %s", change.Path, change.Location), change.Old, change.New)
+		if !written[change.Path] {
+			extra = append(extra, config.FileWrite{Path: change.Path, Data: change.Data})
+			written[change.Path] = true
+		}
+	}
+
+	if len(plan.Blockers) > 0 {
+		fmt.Printf("\nStill selecting %s '%s' by name (not rewritten):\n\n", plan.Kind, plan.Old)
+		for _, ref := range plan.Blockers {
+			fmt.Printf("  • %-45s %s\n", ref.Location, ref.Old)
+		}
+		fmt.Printf("\nThese would stop resolving. Point them elsewhere first, or use 'aim provider rename %s <new-name>'\nto rename the provider and its profiles in every tool.\n", plan.Old)
+	}
+
+	if dryRun {
+		fmt.Println("\nNo changes were made.")
+		return nil
+	}
+	if len(plan.Blockers) > 0 {
+		return fmt.Errorf("cannot rename %s '%s': %d reference(s) select it by name", plan.Kind, plan.Old, len(plan.Blockers))
+	}
+
+	cm := config.GetConfigManager()
+	if err := cm.Transaction(plan.Apply, extra); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✓ Renamed %s '%s' to '%s' (%d reference(s) updated)\n", plan.Kind, plan.Old, plan.New, len(plan.References)+len(managed))
+	return nil
+}
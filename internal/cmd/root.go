@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/tool"
 	"github.com/spf13/cobra"
 )
 
@@ -70,9 +72,14 @@ func init() {
 	rootCmd.AddCommand(useCmd)
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(benchCmd)
 	rootCmd.AddCommand(providerCmd)
+	rootCmd.AddCommand(modelsCmd)
 	rootCmd.AddCommand(toolCmd)
 	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(identityCmd)
+	rootCmd.AddCommand(credentialCmd)
+	rootCmd.AddCommand(auditCmd)
 }
 
 // initConfig reads in config file and ENV variables if set
@@ -83,4 +90,7 @@ func initConfig() {
 
 	// Configuration is now initialized in main.go
 	// No need for individual command loading
+
+	// Tools declared in config run alongside the builtin ones
+	tool.RegisterConfigTools(config.GetConfigManager().GetConfig())
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fakecore/aim/internal/audit"
 	"github.com/fakecore/aim/internal/config"
 	"github.com/fakecore/aim/internal/tool"
 	"github.com/spf13/cobra"
@@ -31,6 +32,9 @@ Examples:
   # Pass additional arguments to the tool
   aim run claude-code --key deepseek-work -- --help
 
+  # Use the international host of a builtin provider (e.g. api.moonshot.ai)
+  aim run claude-code --key kimi-work --region intl
+
   # Pass CLI-specific arguments (space-separated in one flag)
   aim run claude-code --key glm-coding --cli-args "--dangerously-skip-permissions"
 
@@ -51,6 +55,9 @@ func init() {
 	runCmd.Flags().Int("timeout", 0, "Timeout in milliseconds (overrides configuration)")
 	runCmd.Flags().StringSlice("cli-args", []string{}, "Additional arguments to pass to the CLI tool")
 	runCmd.Flags().Bool("native", false, "Use tool's native configuration (no env vars)")
+	runCmd.Flags().String("region", "", "Host region for builtin providers: cn, intl (default: settings.region)")
+
+	_ = runCmd.RegisterFlagCompletionFunc("model", completeRunModel)
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
@@ -61,13 +68,14 @@ func runRun(cmd *cobra.Command, args []string) error {
 	timeout, _ := cmd.Flags().GetInt("timeout")
 	additionalArgs, _ := cmd.Flags().GetStringSlice("cli-args")
 	nativeMode, _ := cmd.Flags().GetBool("native")
+	region, _ := cmd.Flags().GetString("region")
 
 	// Get canonical name (handle aliases like cc -> claude-code)
 	canonicalToolName := tool.GetCanonicalName(toolName)
 
 	// Check if tool is supported
 	if !tool.IsToolSupported(canonicalToolName) {
-		return fmt.Errorf("unsupported tool: %s. Currently supported tools: [codex claude-code (cc)]", toolName)
+		return fmt.Errorf("unsupported tool: %s. Currently supported tools: [%s]", toolName, tool.SupportedToolNames())
 	}
 
 	// If native mode is enabled, skip all configuration
@@ -108,6 +116,11 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 	// Initialize environment preparer manager
 	preparerManager := tool.NewEnvironmentPreparerManager()
+	defer func() {
+		if err := preparerManager.Cleanup(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
 
 	// Validate tool environment configuration with resolved provider
 	if err := preparerManager.ValidateEnvironment(canonicalToolName, runtime.Provider); err != nil {
@@ -116,12 +129,20 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 	// Apply command line overrides
 	if modelName != "" {
-		runtime.Model = modelName
+		// Tiers and aliases (fast, opus, ...) map to the provider's model IDs
+		runtime.Model = runtime.LookupModel(modelName)
 		runtime.ModelOverride = true
 	}
 	if timeout > 0 {
 		runtime.Timeout = time.Duration(timeout) * time.Millisecond
 	}
+	if _, err := resolver.ApplyRegion(runtime, region); err != nil {
+		return fmt.Errorf("invalid region: %w", err)
+	}
+
+	warnUnknownModel(cfg, runtime)
+	warnDeprecatedModel(runtime.Model)
+	warnLowBalance(cfg, keyName)
 
 	// Rebuild env vars after overrides so model/timeout changes are reflected.
 	if err := resolver.UpdateRuntimeEnvVars(runtime); err != nil {
@@ -196,6 +217,8 @@ func runRun(cmd *cobra.Command, args []string) error {
 			toolName, canonicalToolName, keyName, runtime.Provider, runtime.Profile, runtime.Model)
 	}
 
+	audit.Record(audit.ActionRun, keyName, canonicalToolName, runtime.Provider, realBinary)
+
 	// Execute with environment
 	return execWithEnv(realBinary, toolArgs, runtime.EnvVars)
 }
@@ -307,7 +330,7 @@ func runNative(cmd *cobra.Command, args []string, canonicalToolName string) erro
 
 	// Check if tool is supported
 	if !tool.IsToolSupported(canonicalToolName) {
-		return fmt.Errorf("unsupported tool: %s. Currently supported tools: [codex claude-code (cc)]", toolName)
+		return fmt.Errorf("unsupported tool: %s. Currently supported tools: [%s]", toolName, tool.SupportedToolNames())
 	}
 
 	// Get tool command
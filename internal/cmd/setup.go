@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/fakecore/aim/internal/audit"
 	"github.com/fakecore/aim/internal/setup"
 	"github.com/spf13/cobra"
 )
@@ -85,6 +86,9 @@ var setupInstallCmd = &cobra.Command{
 	Short: "Install configuration to tool",
 	Long: `Install configuration to tool's config file.
 
+Tools added with 'aim tool add --install-path' write the values declared
+under their install block into that file, keeping its other settings.
+
 Examples:
   # Install to Claude Code
   aim setup install cc --key glm-test
@@ -92,6 +96,19 @@ Examples:
   # Install to Codex with dry run
   aim setup install codex --key glm-test --dry-run
 
+  # Install to aider (~/.aider.conf.yml)
+  aim setup install aider --key deepseek-work
+
+  # Install to Qwen Code (~/.qwen/settings.json)
+  aim setup install qwen --key qwen-work
+
+  # Install to Gemini CLI (~/.gemini/settings.json and ~/.gemini/.env)
+  aim setup install gemini --key google-work
+
+  # Add every key as a provider in opencode.json, with deepseek-work as the default;
+  # keys are read from the environment, see 'aim setup env opencode --key <key>'
+  aim setup install opencode --key deepseek-work
+
   # Force overwrite existing config
   aim setup install cc --key glm-test --force`,
 	Args: cobra.MinimumNArgs(1),
@@ -269,6 +286,8 @@ func runSetupInstall(cmd *cobra.Command, args []string) error {
 	if dryRun {
 		fmt.Println("Dry run completed. No changes were made.")
 	} else {
+		audit.Record(audit.ActionInstall, keyName, result.Runtime.Tool, result.Runtime.Provider, result.Metadata.ConfigPath)
+
 		fmt.Printf("✓ Configuration installed to %s\n", result.Metadata.ConfigPath)
 		if result.Metadata.BackupPath != "" {
 			fmt.Printf("✓ Backup created at %s\n", result.Metadata.BackupPath)
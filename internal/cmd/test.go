@@ -1,28 +1,99 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/probe"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/fakecore/aim/internal/tool"
 	"github.com/spf13/cobra"
 )
 
 var testCmd = &cobra.Command{
 	Use:   "test [key-name]",
 	Short: "Test key configuration",
-	Long:  `Test connectivity and configuration for keys using the v2.0 simplified configuration.`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runTest,
+	Long: `Test keys by sending a minimal real request to each configured tool's endpoint.
+
+claude-code profiles are probed with an Anthropic /v1/messages request and
+codex profiles with the request codex itself sends: /chat/completions, or
+/responses when the profile's wire_api is responses (e.g. Azure OpenAI).
+Each probe asks for a handful of tokens, so the cost is negligible.
+
+Failures are classified as auth, model_not_found, rate_limit, network,
+server, http or config. When a key is rejected by a builtin provider that has
+separate mainland China and international hosts, the other region is probed
+too and the result says which region the key belongs to.
+
+Examples:
+  # Test the default key against every tool
+  aim test
+
+  # Test one key against codex only
+  aim test glm-work --tool codex
+
+  # Test all keys, 8 at a time, as JSON
+  aim test --all --concurrency 8 --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTest,
 }
 
 func init() {
 	testCmd.Flags().Bool("all", false, "Test all configured keys")
 	testCmd.Flags().Bool("verbose", false, "Verbose output")
+	testCmd.Flags().String("tool", "", "Only test this tool (default: every tool with a matching profile)")
+	testCmd.Flags().Int("concurrency", 4, "Number of probes to run in parallel")
+	testCmd.Flags().Duration("timeout", 15*time.Second, "Timeout for each probe")
+	testCmd.Flags().String("format", "text", "Output format: text, json")
+	testCmd.Flags().String("region", "", "Host region for builtin providers: cn, intl (default: settings.region)")
+}
+
+// testTarget is one key/tool combination to probe
+type testTarget struct {
+	Key     string
+	Tool    string
+	Runtime *config.RuntimeConfig
+	Skip    string // Reason the target is not probed
+	Err     error  // Resolution failure
+}
+
+// testResult is the reported outcome of probing one target
+type testResult struct {
+	Key        string `json:"key"`
+	Tool       string `json:"tool"`
+	Provider   string `json:"provider,omitempty"`
+	BaseURL    string `json:"base_url,omitempty"`
+	Model      string `json:"model,omitempty"`
+	Status     string `json:"status"` // ok, failed or skipped
+	HTTPStatus int    `json:"http_status,omitempty"`
+	LatencyMS  int64  `json:"latency_ms,omitempty"`
+	ErrorClass string `json:"error_class,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// OtherRegion is set when the key was rejected here but authenticates against this region's host
+	OtherRegion string `json:"other_region,omitempty"`
 }
 
 func runTest(cmd *cobra.Command, args []string) error {
 	testAll, _ := cmd.Flags().GetBool("all")
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	toolName, _ := cmd.Flags().GetString("tool")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	format, _ := cmd.Flags().GetString("format")
+	region, _ := cmd.Flags().GetString("region")
+
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format '%s'. Supported formats: text, json", format)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
 
 	// Get global configuration manager
 	cm := config.GetConfigManager()
@@ -38,6 +109,7 @@ func runTest(cmd *cobra.Command, args []string) error {
 		for keyName := range cfg.Keys {
 			keysToTest = append(keysToTest, keyName)
 		}
+		sort.Strings(keysToTest)
 	} else if len(args) > 0 {
 		// Test specific key
 		keyName := args[0]
@@ -58,78 +130,253 @@ func runTest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no keys to test")
 	}
 
-	fmt.Printf("Testing %d key(s)...\n\n", len(keysToTest))
+	var tools []string
+	if toolName != "" {
+		canonical := tool.GetCanonicalName(toolName)
+		if _, exists := cfg.GetTool(canonical); !exists {
+			return fmt.Errorf("tool '%s' not found", toolName)
+		}
+		tools = []string{canonical}
+	} else {
+		for name := range cfg.Tools {
+			tools = append(tools, name)
+		}
+		sort.Strings(tools)
+	}
 
-	// Test each key
-	successCount := 0
+	var targets []testTarget
 	for _, keyName := range keysToTest {
-		if err := testSingleKey(resolver, keyName, verbose); err != nil {
-			fmt.Printf("❌ %s: %v\n", keyName, err)
-		} else {
-			fmt.Printf("✅ %s: OK\n", keyName)
+		for _, name := range tools {
+			targets = append(targets, resolveTestTarget(resolver, keyName, name, region, toolName != ""))
+		}
+	}
+
+	if format == "text" {
+		fmt.Printf("Testing %d key(s)...\n\n", len(keysToTest))
+	}
+
+	prober := probe.NewProber(&http.Client{Timeout: timeout})
+	results := probeTargets(prober, targets, concurrency, timeout)
+
+	successCount, failCount := 0, 0
+	for _, result := range results {
+		switch result.Status {
+		case "ok":
 			successCount++
+		case "failed":
+			failCount++
+		}
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, result := range results {
+			printTestResult(result, verbose)
 		}
+		fmt.Printf("\nTest Results: %d/%d passed\n", successCount, successCount+failCount)
 	}
 
-	fmt.Printf("\nTest Results: %d/%d passed\n", successCount, len(keysToTest))
-	if successCount < len(keysToTest) {
+	if failCount > 0 {
 		return fmt.Errorf("some tests failed")
 	}
+	if successCount == 0 {
+		return fmt.Errorf("no tool profiles matched the selected keys")
+	}
 
 	return nil
 }
 
-// testSingleKey tests a single key configuration
-func testSingleKey(resolver *config.Resolver, keyName string, verbose bool) error {
-	// Validate key
+// resolveTestTarget resolves a key against a tool, skipping tools that have no profile for the key's provider
+// When the tool was requested explicitly a missing profile is reported as a failure instead
+func resolveTestTarget(resolver *config.Resolver, keyName, toolName, region string, explicit bool) testTarget {
+	target := testTarget{Key: keyName, Tool: toolName}
+
 	if err := resolver.ValidateKey(keyName); err != nil {
-		return fmt.Errorf("invalid key: %w", err)
+		target.Err = fmt.Errorf("invalid key: %w", err)
+		return target
 	}
 
-	// Get key configuration
 	cfg := resolver.GetConfig()
 	key, _ := cfg.GetKey(keyName)
 
-	if verbose {
-		fmt.Printf("Testing key '%s' (provider: %s)...\n", keyName, key.Provider)
+	if _, ok := tool.GetProtocol(toolName); !ok {
+		target.Skip = "no connectivity probe for this tool"
+		return target
+	}
+
+	if _, ok := cfg.GetToolProfile(toolName, key.Provider); !ok && !explicit {
+		target.Skip = fmt.Sprintf("no '%s' profile", key.Provider)
+		return target
 	}
 
-	// Test by trying to resolve with a known tool
-	// We'll use claude-code as the test tool since it's commonly available
-	runtime, err := resolver.Resolve("claude-code", keyName, "")
+	if key.Credentials != nil {
+		target.Skip = fmt.Sprintf("%s cloud credentials are not probed", key.Credentials.Type)
+		return target
+	}
+
+	runtime, err := resolver.Resolve(toolName, keyName, "")
 	if err != nil {
-		return fmt.Errorf("failed to resolve configuration: %w", err)
+		target.Err = fmt.Errorf("failed to resolve configuration: %w", err)
+		return target
 	}
+	if _, err := resolver.ApplyRegion(runtime, region); err != nil {
+		target.Err = fmt.Errorf("invalid region: %w", err)
+		return target
+	}
+	target.Runtime = runtime
+
+	return target
+}
+
+// probeTargets probes every resolved target with bounded concurrency, preserving order
+func probeTargets(prober *probe.Prober, targets []testTarget, concurrency int, timeout time.Duration) []testResult {
+	results := make([]testResult, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		result := testResult{Key: target.Key, Tool: target.Tool}
+
+		switch {
+		case target.Skip != "":
+			result.Status = "skipped"
+			result.Error = target.Skip
+			results[i] = result
+			continue
+		case target.Err != nil:
+			result.Status = "failed"
+			result.ErrorClass = string(probe.ClassConfig)
+			result.Error = target.Err.Error()
+			results[i] = result
+			continue
+		}
+
+		runtime := target.Runtime
+		result.Provider = runtime.Provider
+		result.BaseURL = runtime.BaseURL
+		result.Model = runtime.Model
+		protocol, _ := tool.GetProtocol(target.Tool)
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target testTarget, result testResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
 
-	if verbose {
-		fmt.Printf("  Resolved configuration:\n")
-		fmt.Printf("    Tool: %s\n", runtime.Tool)
-		fmt.Printf("    Provider: %s\n", runtime.Provider)
-		fmt.Printf("    Base URL: %s\n", runtime.BaseURL)
-		fmt.Printf("    Model: %s\n", runtime.Model)
-		fmt.Printf("    Timeout: %v\n", runtime.Timeout)
+			outcome := prober.Probe(ctx, probeTarget(protocol, runtime))
+
+			result.HTTPStatus = outcome.StatusCode
+			result.LatencyMS = outcome.Latency.Milliseconds()
+			if outcome.OK() {
+				result.Status = "ok"
+			} else {
+				result.Status = "failed"
+				result.ErrorClass = string(outcome.Class)
+				result.Error = outcome.Message
+				if outcome.Class == probe.ClassAuth {
+					result.OtherRegion = probeOtherRegions(ctx, prober, protocol, target.Tool, runtime)
+				}
+			}
+			results[i] = result
+		}(i, target, result)
 	}
 
-	// Basic validation - check if we have the required fields
-	if runtime.APIKey == "" {
-		return fmt.Errorf("API key is empty")
+	wg.Wait()
+	return results
+}
+
+// printTestResult prints one probe result as a text line
+func printTestResult(result testResult, verbose bool) {
+	label := fmt.Sprintf("%s [%s]", result.Key, result.Tool)
+
+	switch result.Status {
+	case "ok":
+		fmt.Printf("✅ %s: OK (%d, %dms)\n", label, result.HTTPStatus, result.LatencyMS)
+	case "skipped":
+		if verbose {
+			fmt.Printf("⏭️  %s: skipped (%s)\n", label, result.Error)
+		}
+		return
+	default:
+		if result.HTTPStatus != 0 {
+			fmt.Printf("❌ %s: %s: %s (%d, %dms)\n", label, result.ErrorClass, result.Error, result.HTTPStatus, result.LatencyMS)
+		} else {
+			fmt.Printf("❌ %s: %s: %s\n", label, result.ErrorClass, result.Error)
+		}
+		if result.OtherRegion != "" {
+			fmt.Printf("    ⚠️  Key authenticates against the '%s' region only. Use --region %s or 'aim config set region %s'\n",
+				result.OtherRegion, result.OtherRegion, result.OtherRegion)
+		}
 	}
 
-	if runtime.BaseURL == "" {
-		return fmt.Errorf("base URL is not configured")
+	if verbose && result.BaseURL != "" {
+		fmt.Printf("    Provider: %s\n", result.Provider)
+		fmt.Printf("    Base URL: %s\n", result.BaseURL)
+		fmt.Printf("    Model: %s\n", result.Model)
 	}
+}
 
-	if runtime.Model == "" {
-		return fmt.Errorf("model is not configured")
+// probeOtherRegions retries a rejected key against the provider's hosts in other regions
+// It returns the first region where the key authenticates, or "" if none does.
+func probeOtherRegions(ctx context.Context, prober *probe.Prober, protocol tool.Protocol, toolName string, runtime *config.RuntimeConfig) string {
+	current, urls, ok := provider.RegionalBaseURLs(runtime.Provider, toolName, runtime.BaseURL)
+	if !ok {
+		return ""
 	}
 
-	// For now, we'll just validate the configuration structure
-	// In a real implementation, you might want to make a test API call
-	// But since we're in alpha stage, basic validation is sufficient
+	regions := make([]string, 0, len(urls))
+	for region := range urls {
+		if region != current {
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
 
-	if verbose {
-		fmt.Printf("  Configuration validation: PASSED\n")
+	for _, region := range regions {
+		target := probeTarget(protocol, runtime)
+		target.BaseURL = urls[region]
+		outcome := prober.Probe(ctx, target)
+		// Any answer other than an auth rejection means the key is known there
+		if outcome.StatusCode != 0 && outcome.Class != probe.ClassAuth {
+			return region
+		}
 	}
+	return ""
+}
 
-	return nil
+// probeTarget builds the probe target for a resolved runtime, including Azure OpenAI wiring, auth scheme and headers
+func probeTarget(protocol tool.Protocol, runtime *config.RuntimeConfig) probe.Target {
+	target := probe.Target{
+		Protocol: protocol,
+		BaseURL:  runtime.BaseURL,
+		APIKey:   runtime.APIKey,
+		Model:    runtime.Model,
+	}
+	if runtime.ProviderKind == config.ProviderKindAzure {
+		target.AzureAPIVersion = runtime.QueryParams["api-version"]
+	}
+	if runtime.Tool == string(tool.ToolTypeCodex) {
+		// Probe the endpoint codex itself calls, e.g. <base>/responses for Azure deployments
+		target.WireAPI = runtime.WireAPI
+		if target.WireAPI == "" {
+			target.WireAPI = config.WireAPIChat
+		}
+		target.QueryParams = runtime.QueryParams
+	}
+	switch runtime.AuthScheme {
+	case config.AuthSchemeXAPIKey:
+		target.KeyHeader = "x-api-key"
+	case config.AuthSchemeNone:
+		target.APIKey = ""
+	}
+	target.Headers = runtime.Headers
+	return target
 }
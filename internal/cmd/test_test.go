@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/probe"
+)
+
+// newTestConfig returns a config whose "stub" provider points codex at baseURL
+func newTestConfig(baseURL string, keys map[string]string) *config.Config {
+	cfg := &config.Config{
+		Keys: map[string]*config.Key{},
+		Tools: map[string]*config.ToolConfig{
+			"codex": {
+				Command: "codex",
+				Profiles: map[string]*config.ToolProfile{
+					"stub": {Provider: "stub", BaseURL: baseURL, Model: "stub-model"},
+				},
+			},
+			"claude-code": {
+				Command:  "claude",
+				Profiles: map[string]*config.ToolProfile{},
+			},
+			"gemini-cli": {
+				Command:  "gemini",
+				Profiles: map[string]*config.ToolProfile{},
+			},
+		},
+	}
+	for name, key := range keys {
+		cfg.Keys[name] = &config.Key{Provider: "stub", Key: key}
+	}
+	return cfg
+}
+
+func TestResolveTestTarget(t *testing.T) {
+	resolver := config.NewResolver(newTestConfig("http://127.0.0.1:1/v1", map[string]string{"work": "sk-work"}))
+
+	tests := []struct {
+		name     string
+		key      string
+		tool     string
+		explicit bool
+		wantSkip bool
+		wantErr  bool
+	}{
+		{"resolved", "work", "codex", false, false, false},
+		{"no profile", "work", "claude-code", false, true, false},
+		{"no profile explicit", "work", "claude-code", true, false, true},
+		{"no protocol", "work", "gemini-cli", true, true, false},
+		{"unknown key", "missing", "codex", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := resolveTestTarget(resolver, tt.key, tt.tool, "", tt.explicit)
+			if (target.Skip != "") != tt.wantSkip {
+				t.Errorf("Skip = %q, want skipped=%v", target.Skip, tt.wantSkip)
+			}
+			if (target.Err != nil) != tt.wantErr {
+				t.Errorf("Err = %v, want error=%v", target.Err, tt.wantErr)
+			}
+			if !tt.wantSkip && !tt.wantErr && target.Runtime == nil {
+				t.Error("Runtime was not resolved")
+			}
+		})
+	}
+}
+
+func TestProbeTargets(t *testing.T) {
+	const concurrency = 2
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+
+		if r.Header.Get("Authorization") != "Bearer sk-good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	keys := map[string]string{"a": "sk-good", "b": "sk-bad", "c": "sk-good", "d": "sk-good", "e": "sk-bad"}
+	resolver := config.NewResolver(newTestConfig(server.URL+"/v1", keys))
+
+	var targets []testTarget
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		targets = append(targets, resolveTestTarget(resolver, name, "codex", "", false))
+	}
+	targets = append(targets, resolveTestTarget(resolver, "a", "claude-code", "", false))
+
+	results := probeTargets(probe.NewProber(server.Client()), targets, concurrency, 5*time.Second)
+
+	want := []struct{ key, status, class string }{
+		{"a", "ok", ""},
+		{"b", "failed", string(probe.ClassAuth)},
+		{"c", "ok", ""},
+		{"d", "ok", ""},
+		{"e", "failed", string(probe.ClassAuth)},
+		{"a", "skipped", ""},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Key != w.key || got.Status != w.status || got.ErrorClass != w.class {
+			t.Errorf("results[%d] = %s/%s/%s, want %s/%s/%s", i, got.Key, got.Status, got.ErrorClass, w.key, w.status, w.class)
+		}
+	}
+	if maxInFlight > concurrency {
+		t.Errorf("%d probes ran at once, concurrency is %d", maxInFlight, concurrency)
+	}
+
+	data, err := json.Marshal(results[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[0]["status"] != "ok" || decoded[0]["model"] != "stub-model" || decoded[0]["http_status"] != float64(200) {
+		t.Errorf("unexpected ok result JSON: %s", data)
+	}
+	if _, ok := decoded[0]["error_class"]; ok {
+		t.Errorf("ok result should omit error_class: %s", data)
+	}
+	if decoded[1]["error_class"] != "auth" || !strings.Contains(decoded[1]["error"].(string), "invalid api key") {
+		t.Errorf("unexpected failed result JSON: %s", data)
+	}
+}
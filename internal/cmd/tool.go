@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/tool"
 	"github.com/spf13/cobra"
 )
 
@@ -23,31 +25,101 @@ var toolListCmd = &cobra.Command{
 var toolAddCmd = &cobra.Command{
 	Use:   "add <name>",
 	Short: "Add a tool configuration",
-	Long:  `Add a new tool configuration.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runToolAdd,
+	Long: `Add a new tool configuration that 'aim run' can launch.
+
+A tool declares the protocol it speaks, how it receives the key, base URL
+and model (environment variables and argument templates) and optionally the
+config file 'aim setup install' writes for it. Tools that need more logic
+can name an external --preparer (see 'aim tool preparer'). Profiles and
+provider presets come from --profiles-from (default: codex for openai,
+claude-code for anthropic).
+
+Tools aim ships (claude-code, codex, aider, qwen-code, gemini-cli, opencode)
+are added from their builtin definition when --command is omitted, e.g. to
+configs created before they existed.
+
+Argument and install templates may use {base_url}, {model}, {provider},
+{profile}, {timeout}, {env_key}, {display_name}, {wire_api} and {models.<alias>}.
+Install templates may also use {key}; install files holding it are written
+with mode 0600. Arguments cannot, since command lines are visible to other
+users: pass the key with --key-env. An argument is left out when one of its
+placeholders has no value.
+
+Examples:
+  aim tool add aider
+  aim tool add qwen-code
+  aim tool add mytool --command mytool --protocol openai \
+    --key-env OPENAI_API_KEY --base-url-env OPENAI_BASE_URL --arg "--model {model}"
+  aim tool add mycli --command mycli --protocol anthropic --alias mc \
+    --install-path ~/.mycli/config.json --install-value api.base_url={base_url}`,
+	Args: cobra.ExactArgs(1),
+	RunE: runToolAdd,
 }
 
 var toolRemoveCmd = &cobra.Command{
 	Use:   "remove <name>",
 	Short: "Remove a tool configuration",
-	Long:  `Remove a tool configuration.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runToolRemove,
+	Long: `Remove a tool configuration together with its profiles and state.
+
+A tool still named by settings.default_tool or the current state is only
+removed with --cascade (clear those settings) or --reassign <tool>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runToolRemove,
+}
+
+var toolProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage tool profiles",
+	Long:  `Manage provider profiles of a tool configuration.`,
+}
+
+var toolProfileRenameCmd = &cobra.Command{
+	Use:   "rename <tool> <old-name> <new-name>",
+	Short: "Rename a tool profile and update its references",
+	Long: `Rename a tool profile and update field mappings that name it explicitly
+(profiles.<name>.*).
+
+Keys and settings select profiles by provider name in every tool, so a profile
+they still select is not renamed; use 'aim provider rename' for that.
+
+Examples:
+  aim tool profile rename codex glm zhipu
+  aim tool profile rename codex glm zhipu --dry-run`,
+	Args: cobra.ExactArgs(3),
+	RunE: runToolProfileRename,
 }
 
 func init() {
 	// Flags for add command
-	toolAddCmd.Flags().String("command", "", "Command to execute (required)")
+	toolAddCmd.Flags().String("command", "", "Command to execute (required unless adding a tool aim ships)")
 	toolAddCmd.Flags().String("provider", "", "Default provider for this tool")
 	toolAddCmd.Flags().String("base-url", "", "Default base URL for this tool")
 	toolAddCmd.Flags().String("model", "", "Default model for this tool")
-	toolAddCmd.MarkFlagRequired("command")
+	toolAddCmd.Flags().StringSlice("alias", nil, "Other names the tool runs under (repeatable)")
+	toolAddCmd.Flags().String("protocol", "", "API the tool speaks: anthropic, openai")
+	toolAddCmd.Flags().String("profiles-from", "", "Tool whose profiles and provider presets this tool uses")
+	toolAddCmd.Flags().StringArray("arg", nil, "Argument template, e.g. \"--model {model}\" (repeatable)")
+	toolAddCmd.Flags().String("key-env", "", "Environment variable that receives the API key")
+	toolAddCmd.Flags().String("base-url-env", "", "Environment variable that receives the base URL")
+	toolAddCmd.Flags().String("model-env", "", "Environment variable that receives the model")
+	toolAddCmd.Flags().String("preparer", "", "External preparer executable, see 'aim tool preparer'")
+	toolAddCmd.Flags().Int("preparer-timeout", 0, "Preparer timeout in milliseconds (default: 10000)")
+	toolAddCmd.Flags().String("install-path", "", "Config file written by 'aim setup install'")
+	toolAddCmd.Flags().String("install-format", "", "Install file format: json, yaml, toml (default: from the extension)")
+	toolAddCmd.Flags().StringArray("install-value", nil, "Install value as dotted.key=template (repeatable)")
+
+	// Flags for remove command
+	addRemoveFlags(toolRemoveCmd, "tool")
+
+	// Flags for profile rename command
+	toolProfileRenameCmd.Flags().Bool("dry-run", false, "List the references that would change without renaming")
+	toolProfileCmd.AddCommand(toolProfileRenameCmd)
 
 	// Add subcommands
 	toolCmd.AddCommand(toolListCmd)
 	toolCmd.AddCommand(toolAddCmd)
 	toolCmd.AddCommand(toolRemoveCmd)
+	toolCmd.AddCommand(toolProfileCmd)
 }
 
 func runToolList(cmd *cobra.Command, args []string) error {
@@ -63,6 +135,24 @@ func runToolList(cmd *cobra.Command, args []string) error {
 		for name, tool := range cfg.Tools {
 			fmt.Printf("  • %s\n", name)
 			fmt.Printf("    Command: %s\n", tool.Command)
+			if len(tool.Aliases) > 0 {
+				fmt.Printf("    Aliases: %s\n", strings.Join(tool.Aliases, ", "))
+			}
+			if tool.Protocol != "" {
+				fmt.Printf("    Protocol: %s\n", tool.Protocol)
+			}
+			if tool.ProfilesFrom != "" {
+				fmt.Printf("    Profiles from: %s\n", tool.ProfilesFrom)
+			}
+			if len(tool.Args) > 0 {
+				fmt.Printf("    Args: %s\n", strings.Join(tool.Args, " "))
+			}
+			if tool.Preparer != nil {
+				fmt.Printf("    Preparer: %s\n", strings.TrimSpace(tool.Preparer.Command+" "+strings.Join(tool.Preparer.Args, " ")))
+			}
+			if tool.Install != nil {
+				fmt.Printf("    Install: %s (%s, %d values)\n", tool.Install.Path, tool.Install.InstallFormat(), len(tool.Install.Values))
+			}
 			if tool.Enabled {
 				fmt.Printf("    Status: enabled\n")
 			} else {
@@ -97,6 +187,18 @@ func runToolAdd(cmd *cobra.Command, args []string) error {
 	provider, _ := cmd.Flags().GetString("provider")
 	baseURL, _ := cmd.Flags().GetString("base-url")
 	model, _ := cmd.Flags().GetString("model")
+	aliases, _ := cmd.Flags().GetStringSlice("alias")
+	protocol, _ := cmd.Flags().GetString("protocol")
+	profilesFrom, _ := cmd.Flags().GetString("profiles-from")
+	argTemplates, _ := cmd.Flags().GetStringArray("arg")
+	keyEnv, _ := cmd.Flags().GetString("key-env")
+	baseURLEnv, _ := cmd.Flags().GetString("base-url-env")
+	modelEnv, _ := cmd.Flags().GetString("model-env")
+	preparer, _ := cmd.Flags().GetString("preparer")
+	preparerTimeout, _ := cmd.Flags().GetInt("preparer-timeout")
+	installPath, _ := cmd.Flags().GetString("install-path")
+	installFormat, _ := cmd.Flags().GetString("install-format")
+	installValues, _ := cmd.Flags().GetStringArray("install-value")
 
 	// Get global configuration manager
 	cm := config.GetConfigManager()
@@ -107,35 +209,121 @@ func runToolAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("tool '%s' already exists", toolName)
 	}
 
-	// Update configuration
-	err := cm.UpdateConfig(func(cfg *config.Config) {
-		// Create tool configuration
-		tool := &config.ToolConfig{
-			Command:      command,
-			Enabled:      true,
-			Profiles:     make(map[string]*config.ToolProfile),
-			FieldMapping: make(map[string]string),
+	// Tools aim ships are added from their builtin definition, also when named by alias
+	if builtinName := tool.GetCanonicalName(toolName); command == "" {
+		if builtinTool, ok := config.DefaultConfig().Tools[builtinName]; ok {
+			if _, exists := cfg.GetTool(builtinName); exists {
+				return fmt.Errorf("tool '%s' already exists", builtinName)
+			}
+			return addTool(cm, builtinName, builtinTool)
+		}
+	}
+	if command == "" {
+		return fmt.Errorf("--command is required for tools aim doesn't ship")
+	}
+	if tool.IsToolSupported(toolName) {
+		return fmt.Errorf("'%s' is already the name or alias of tool '%s'", toolName, tool.GetCanonicalName(toolName))
+	}
+	for _, alias := range aliases {
+		if tool.IsToolSupported(alias) || alias == toolName {
+			return fmt.Errorf("alias '%s' is already a tool name or alias", alias)
 		}
+	}
 
-		// Add default profile if specified
-		if provider != "" {
-			profile := &config.ToolProfile{
-				Provider: provider,
-			}
-			if baseURL != "" {
-				profile.BaseURL = baseURL
-			}
-			if model != "" {
-				profile.Model = model
+	// Profiles and presets come from the builtin tool speaking the same protocol
+	if profilesFrom == "" {
+		switch protocol {
+		case string(tool.ProtocolOpenAI):
+			profilesFrom = string(tool.ToolTypeCodex)
+		case string(tool.ProtocolAnthropic):
+			profilesFrom = string(tool.ToolTypeClaudeCode)
+		}
+		if _, ok := cfg.GetTool(profilesFrom); !ok {
+			profilesFrom = ""
+		}
+	}
+
+	newTool := &config.ToolConfig{
+		Command:      command,
+		Enabled:      true,
+		Aliases:      aliases,
+		Protocol:     protocol,
+		ProfilesFrom: profilesFrom,
+		Args:         argTemplates,
+		Profiles:     make(map[string]*config.ToolProfile),
+		FieldMapping: make(map[string]string),
+	}
+	if keyEnv != "" {
+		newTool.FieldMapping[keyEnv] = "keys.{current_key}.key"
+	}
+	if baseURLEnv != "" {
+		newTool.FieldMapping[baseURLEnv] = "profiles.{current_profile}.base_url"
+	}
+	if modelEnv != "" {
+		newTool.FieldMapping[modelEnv] = "profiles.{current_profile}.model"
+	}
+	if preparer != "" {
+		newTool.Preparer = &config.ToolPreparer{Command: preparer, Timeout: preparerTimeout}
+	} else if preparerTimeout != 0 {
+		return fmt.Errorf("--preparer-timeout requires --preparer")
+	}
+	if installPath != "" {
+		newTool.Install = &config.ToolInstall{Path: installPath, Format: installFormat, Values: make(map[string]string)}
+		for _, value := range installValues {
+			key, tmpl, ok := strings.Cut(value, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid --install-value '%s', expected dotted.key=template", value)
 			}
-			tool.Profiles[provider] = profile
+			newTool.Install.Values[key] = tmpl
 		}
+	} else if len(installValues) > 0 || installFormat != "" {
+		return fmt.Errorf("--install-value and --install-format require --install-path")
+	}
 
-		// Add to configuration
+	// Add default profile if specified
+	if provider != "" {
+		profile := &config.ToolProfile{
+			Provider: provider,
+		}
+		if baseURL != "" {
+			profile.BaseURL = baseURL
+		}
+		if model != "" {
+			profile.Model = model
+		}
+		newTool.Profiles[provider] = profile
+	}
+
+	if err := addTool(cm, toolName, newTool); err != nil {
+		return err
+	}
+	if len(aliases) > 0 {
+		fmt.Printf("  Aliases: %s\n", strings.Join(aliases, ", "))
+	}
+	fmt.Printf("\nRun it with: aim run %s --key <key-name>\n", toolName)
+
+	return nil
+}
+
+// addTool validates a tool definition against the rest of the config and saves it
+func addTool(cm *config.ConfigManager, toolName string, newTool *config.ToolConfig) error {
+	cfg := cm.GetConfig()
+	candidate := *cfg
+	candidate.Tools = make(map[string]*config.ToolConfig, len(cfg.Tools)+1)
+	for name, t := range cfg.Tools {
+		candidate.Tools[name] = t
+	}
+	candidate.Tools[toolName] = newTool
+	if err := candidate.Validate(); err != nil {
+		return fmt.Errorf("invalid tool definition: %w", err)
+	}
+
+	// Update configuration
+	err := cm.UpdateConfig(func(cfg *config.Config) {
 		if cfg.Tools == nil {
 			cfg.Tools = make(map[string]*config.ToolConfig)
 		}
-		cfg.Tools[toolName] = tool
+		cfg.Tools[toolName] = newTool
 	})
 
 	if err != nil {
@@ -148,9 +336,15 @@ func runToolAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("✓ Added tool '%s'\n", toolName)
-	fmt.Printf("  Command: %s\n", command)
-	if provider != "" {
-		fmt.Printf("  Default Provider: %s\n", provider)
+	fmt.Printf("  Command: %s\n", newTool.Command)
+	if newTool.Protocol != "" {
+		fmt.Printf("  Protocol: %s\n", newTool.Protocol)
+	}
+	if newTool.ProfilesFrom != "" {
+		fmt.Printf("  Profiles from: %s\n", newTool.ProfilesFrom)
+	}
+	if len(newTool.Profiles) > 0 {
+		fmt.Printf("  Profiles: %s\n", config.GetProfileList(newTool.Profiles))
 	}
 
 	return nil
@@ -158,31 +352,29 @@ func runToolAdd(cmd *cobra.Command, args []string) error {
 
 func runToolRemove(cmd *cobra.Command, args []string) error {
 	toolName := args[0]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 	// Get global configuration manager
 	cm := config.GetConfigManager()
-	cfg := cm.GetConfig()
 
-	// Check if tool exists
-	if _, exists := cfg.GetTool(toolName); !exists {
-		return fmt.Errorf("tool '%s' not found", toolName)
+	plan, err := config.PlanToolRemoval(cm.GetConfig(), cm.GetState(), toolName, removeOptions(cmd))
+	if err != nil {
+		return explainReferenced(err)
 	}
 
-	// Update configuration
-	err := cm.UpdateConfig(func(cfg *config.Config) {
-		delete(cfg.Tools, toolName)
-	})
+	return executeRemoval(plan, dryRun)
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to update config: %w", err)
-	}
+func runToolProfileRename(cmd *cobra.Command, args []string) error {
+	toolName, oldName, newName := args[0], args[1], args[2]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-	// Force save to disk immediately
-	if err := cm.ForceSave(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
-	}
+	cm := config.GetConfigManager()
 
-	fmt.Printf("✓ Removed tool '%s'\n", toolName)
+	plan, err := config.PlanProfileRename(cm.GetConfig(), cm.GetState(), tool.GetCanonicalName(toolName), oldName, newName)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return executeRename(plan, nil, dryRun)
 }
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var toolPreparerCmd = &cobra.Command{
+	Use:   "preparer",
+	Short: "Develop and check external tool preparers",
+	Long: `External preparers are executables named under a tool's preparer block.
+Before 'aim run' starts the tool, aim writes the resolved runtime to the
+preparer's stdin as JSON and reads back:
+
+  {"schema_version": 1, "args": [...], "env": {...},
+   "files": [{"path": "...", "content": "...", "mode": "0600"}],
+   "cleanup": ["..."]}
+
+File and cleanup paths are relative to the request's work_dir, which is
+removed after the tool exits. Absolute paths and paths that leave work_dir
+with .. are rejected.`,
+}
+
+var toolPreparerTestCmd = &cobra.Command{
+	Use:   "test <tool>",
+	Short: "Run a tool's preparer and check its response",
+	Long: `Run a tool's external preparer once with the resolved runtime and show what it
+returned, without writing files or starting the tool.
+
+Use --command to try a preparer before adding it to the tool config, and
+--request to replay a saved request instead of resolving a key.
+
+Examples:
+  aim tool preparer test mycli --key work
+  aim tool preparer test mycli --key work --command ./prepare.py
+  aim tool preparer test mycli --key work --print-request > request.json
+  aim tool preparer test mycli --request request.json --command ./prepare.py`,
+	Args: cobra.ExactArgs(1),
+	RunE: runToolPreparerTest,
+}
+
+func init() {
+	toolPreparerTestCmd.Flags().String("key", "", "Key to resolve the runtime with (default: settings.default_key)")
+	toolPreparerTestCmd.Flags().String("provider", "", "Provider profile to use (default: the key's provider)")
+	toolPreparerTestCmd.Flags().String("model", "", "Model, tier or alias to send")
+	toolPreparerTestCmd.Flags().String("command", "", "Preparer executable to run instead of the configured one")
+	toolPreparerTestCmd.Flags().Int("timeout", 0, "Timeout in milliseconds (default: the configured timeout)")
+	toolPreparerTestCmd.Flags().String("request", "", "Read the request from this JSON file instead of resolving a key")
+	toolPreparerTestCmd.Flags().Bool("print-request", false, "Print the request JSON and exit")
+	toolPreparerTestCmd.Flags().Bool("show-secrets", false, "Show the API key unmasked in the request and response")
+
+	toolPreparerCmd.AddCommand(toolPreparerTestCmd)
+	toolCmd.AddCommand(toolPreparerCmd)
+}
+
+func runToolPreparerTest(cmd *cobra.Command, args []string) error {
+	toolName := tool.GetCanonicalName(args[0])
+	keyName, _ := cmd.Flags().GetString("key")
+	providerName, _ := cmd.Flags().GetString("provider")
+	modelName, _ := cmd.Flags().GetString("model")
+	command, _ := cmd.Flags().GetString("command")
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	requestFile, _ := cmd.Flags().GetString("request")
+	printRequest, _ := cmd.Flags().GetBool("print-request")
+	showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+
+	cfg := config.GetConfigManager().GetConfig()
+	toolCfg, ok := cfg.GetTool(cfg.ResolveAlias(toolName))
+	if !ok {
+		return fmt.Errorf("tool '%s' not found", toolName)
+	}
+
+	plugin := config.ToolPreparer{}
+	if toolCfg.Preparer != nil {
+		plugin = *toolCfg.Preparer
+	}
+	if command != "" {
+		plugin = config.ToolPreparer{Command: command, Timeout: plugin.Timeout}
+	}
+	if timeout > 0 {
+		plugin.Timeout = timeout
+	}
+	if plugin.Command == "" && !printRequest {
+		return fmt.Errorf("tool '%s' has no preparer configured; use --command <executable>", toolName)
+	}
+
+	workDir, err := os.MkdirTemp("", "aim-preparer-test-")
+	if err != nil {
+		return fmt.Errorf("failed to create work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	req, err := preparerTestRequest(cfg, toolName, keyName, providerName, modelName, requestFile, workDir)
+	if err != nil {
+		return err
+	}
+
+	if printRequest {
+		printed := *req
+		if !showSecrets && printed.APIKey != "" {
+			// Keep saved requests free of the key; replayed plugins get the masked value
+			printed.Env = make(map[string]string, len(req.Env))
+			for name, value := range req.Env {
+				printed.Env[name] = strings.ReplaceAll(value, req.APIKey, maskKey(req.APIKey))
+			}
+			printed.APIKey = maskKey(req.APIKey)
+		}
+		data, _ := json.MarshalIndent(printed, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	preparer := tool.NewPluginPreparer(&plugin)
+	fmt.Printf("Running preparer: %s (timeout %s)\n", strings.Join(append([]string{plugin.Command}, plugin.Args...), " "), preparer.Timeout())
+
+	start := time.Now()
+	resp, err := preparer.Call(req)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return fmt.Errorf("preparer check failed")
+	}
+	fmt.Printf("✓ Valid response, schema_version %d (%s)\n", resp.SchemaVersion, time.Since(start).Round(time.Millisecond))
+
+	reveal := func(value string) string {
+		if showSecrets || req.APIKey == "" {
+			return value
+		}
+		return strings.ReplaceAll(value, req.APIKey, maskKey(req.APIKey))
+	}
+
+	fmt.Println("\nArgs:")
+	if len(resp.Args) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, arg := range resp.Args {
+		fmt.Printf("  %s\n", reveal(arg))
+	}
+
+	fmt.Println("\nEnv:")
+	if len(resp.Env) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, name := range sortedStringKeys(resp.Env) {
+		note := ""
+		if _, exists := req.Env[name]; exists {
+			note = "  (overrides aim)"
+		}
+		fmt.Printf("  %s=%s%s\n", name, reveal(resp.Env[name]), note)
+	}
+
+	fmt.Println("\nFiles:")
+	if len(resp.Files) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, file := range resp.Files {
+		path := filepath.Join("<work_dir>", file.Path)
+		mode := file.Mode
+		if mode == "" {
+			mode = "0600"
+		}
+		fmt.Printf("  %s (%s, %d bytes)\n", path, mode, len(file.Content))
+	}
+
+	if len(resp.Cleanup) > 0 {
+		fmt.Println("\nCleanup:")
+		for _, path := range resp.Cleanup {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	// Warn about things that work but are likely mistakes
+	var warnings []string
+	if req.APIKey != "" {
+		for _, arg := range resp.Args {
+			if strings.Contains(arg, req.APIKey) {
+				warnings = append(warnings, "an argument contains the API key; it will be visible in the process list, pass it through env or a file instead")
+				break
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Println()
+		for _, warning := range warnings {
+			fmt.Printf("⚠️  %s\n", warning)
+		}
+	}
+
+	return nil
+}
+
+// preparerTestRequest reads a saved request or resolves one for the tool and key
+func preparerTestRequest(cfg *config.Config, toolName, keyName, providerName, modelName, requestFile, workDir string) (*tool.PluginRequest, error) {
+	if requestFile != "" {
+		data, err := os.ReadFile(requestFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request: %w", err)
+		}
+		var req tool.PluginRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("failed to parse request: %w", err)
+		}
+		req.WorkDir = workDir
+		return &req, nil
+	}
+
+	if keyName == "" {
+		keyName = cfg.Settings.DefaultKey
+		if keyName == "" {
+			return nil, fmt.Errorf("no key specified. Use --key <key-name> or --request <file>")
+		}
+	}
+
+	resolver := config.NewResolver(cfg)
+	runtime, err := resolver.Resolve(toolName, keyName, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve configuration: %w", err)
+	}
+	if modelName != "" {
+		runtime.Model = runtime.LookupModel(modelName)
+		runtime.ModelOverride = true
+		if err := resolver.UpdateRuntimeEnvVars(runtime); err != nil {
+			return nil, fmt.Errorf("failed to update runtime env vars: %w", err)
+		}
+	}
+	return tool.NewPluginRequest(runtime, workDir), nil
+}
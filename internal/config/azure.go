@@ -0,0 +1,45 @@
+package config
+
+import "fmt"
+
+// Provider kinds that need wiring beyond a base URL and a bearer key
+const (
+	ProviderKindAzure = "azure" // Azure OpenAI deployments
+)
+
+// Azure OpenAI defaults
+const (
+	AzureEnvKeyName        = "AZURE_OPENAI_API_KEY"
+	DefaultAzureAPIVersion = "2025-04-01-preview"
+	azureKeyHeader         = "api-key"
+)
+
+// ValidateProviderKind checks a provider kind value
+func ValidateProviderKind(kind string) error {
+	switch kind {
+	case "", ProviderKindAzure:
+		return nil
+	}
+	return fmt.Errorf("unknown provider kind '%s' (supported: %s)", kind, ProviderKindAzure)
+}
+
+// ProviderKind returns the kind of a configured provider, "" for plain compatible endpoints
+func (c *Config) ProviderKind(name string) string {
+	if p, ok := c.GetProvider(name); ok && p != nil {
+		return p.Kind
+	}
+	return ""
+}
+
+// kindWiring returns the wiring a provider kind implies, applied below the provider's own settings
+// Azure OpenAI takes the API version as a query parameter and reads the key from AZURE_OPENAI_API_KEY.
+func kindWiring(kind string) ProviderWiring {
+	if kind != ProviderKindAzure {
+		return ProviderWiring{}
+	}
+	return ProviderWiring{
+		EnvKey:      AzureEnvKeyName,
+		WireAPI:     WireAPIResponses,
+		QueryParams: map[string]string{"api-version": DefaultAzureAPIVersion},
+	}
+}
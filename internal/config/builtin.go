@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// keyFieldPath is the field mapping path that injects the current key's value
+const keyFieldPath = "keys.{current_key}.key"
+
+// BuiltinMaterialization is the configuration generated from a builtin provider endpoint preset
+type BuiltinMaterialization struct {
+	Name     string                  // Provider and profile name written to the config
+	Builtin  string                  // Builtin provider, e.g. "glm"
+	Endpoint string                  // Endpoint preset, e.g. "coding"
+	Region   string                  // Region of the preset hosts, empty for single-region providers
+	Provider *Provider               // Global provider entry
+	Profiles map[string]*ToolProfile // Tool name -> profile
+	Skipped  []string                // Preset tools that are not configured locally
+}
+
+// MaterializeBuiltinProvider builds a global provider and one profile per configured tool from an endpoint preset
+// An empty endpoint selects the provider's default preset; an empty name uses the suggested name.
+// An empty region falls back to settings.region, which is ignored for single-region providers.
+func MaterializeBuiltinProvider(cfg *Config, builtinName, endpointName, name, region string) (*BuiltinMaterialization, error) {
+	var preset *provider.EndpointPreset
+	var err error
+	if endpointName == "" {
+		preset, err = provider.GetDefaultEndpoint(builtinName)
+	} else {
+		preset, err = provider.GetProviderEndpoint(builtinName, endpointName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	explicitRegion := region != ""
+	if !explicitRegion {
+		region = cfg.Settings.Region
+	}
+	if region != "" {
+		regional, err := preset.ForRegion(region)
+		if err != nil {
+			if explicitRegion {
+				return nil, fmt.Errorf("provider '%s': %w", builtinName, err)
+			}
+		} else {
+			preset = regional
+		}
+	}
+
+	existing := ConfiguredProviderNames(cfg)
+	if name == "" {
+		name, err = provider.SuggestProviderName(builtinName, preset.Name, existing)
+		if err != nil {
+			return nil, err
+		}
+	} else if existing[name] {
+		return nil, fmt.Errorf("provider '%s' already exists. Choose another name or remove it first", name)
+	}
+
+	m := &BuiltinMaterialization{
+		Name:     name,
+		Builtin:  builtinName,
+		Endpoint: preset.Name,
+		Region:   preset.Region,
+		Profiles: make(map[string]*ToolProfile),
+	}
+
+	if len(preset.Tools) == 0 {
+		return nil, fmt.Errorf("endpoint '%s' of provider '%s' has no tool configuration", preset.Name, builtinName)
+	}
+
+	// The global provider mirrors the OpenAI-compatible endpoint when there is one, like the default config
+	globalTool := "codex"
+	if _, ok := preset.Tools[globalTool]; !ok {
+		globalTool = sortedPresetTools(preset)[0]
+	}
+	global := preset.Tools[globalTool]
+	m.Provider = &Provider{BaseURL: global.BaseURL, Model: global.Model, Timeout: global.Timeout}
+
+	for _, toolName := range sortedPresetTools(preset) {
+		toolCfg, ok := cfg.Tools[toolName]
+		if !ok {
+			m.Skipped = append(m.Skipped, toolName)
+			continue
+		}
+		m.Profiles[toolName] = presetProfile(name, m.Provider, toolCfg, preset.Tools[toolName])
+	}
+
+	return m, nil
+}
+
+// presetProfile builds a tool profile, omitting fields inherited from the global provider
+func presetProfile(name string, global *Provider, toolCfg *ToolConfig, preset provider.ToolConfig) *ToolProfile {
+	profile := &ToolProfile{
+		Provider: name,
+		ProviderWiring: ProviderWiring{
+			DisplayName: preset.DisplayName,
+			WireAPI:     preset.WireAPI,
+			QueryParams: overlayMap(nil, preset.QueryParams),
+			Headers:     overlayMap(nil, preset.Headers),
+		},
+		Models: overlayMap(nil, preset.Models),
+	}
+
+	if preset.BaseURL != global.BaseURL {
+		profile.BaseURL = preset.BaseURL
+	}
+	if preset.Model != global.Model {
+		profile.Model = preset.Model
+	}
+	if preset.Timeout != global.Timeout {
+		profile.Timeout = preset.Timeout
+	}
+
+	// Tool-wide defaults already apply to every profile
+	for envKey, value := range preset.Env {
+		if toolCfg.Defaults != nil && toolCfg.Defaults.Env[envKey] == value {
+			continue
+		}
+		if profile.Env == nil {
+			profile.Env = make(map[string]string)
+		}
+		profile.Env[envKey] = value
+	}
+
+	// Tools without a tool-level key mapping read the key from a provider-specific variable
+	if preset.EnvKeyName != "" && toolCfg.FieldMapping[preset.EnvKeyName] != keyFieldPath {
+		profile.FieldMapping = map[string]string{preset.EnvKeyName: keyFieldPath}
+	}
+
+	return profile
+}
+
+// Apply adds the materialized provider and profiles to the config
+func (m *BuiltinMaterialization) Apply(cfg *Config) {
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]*Provider)
+	}
+	cfg.Providers[m.Name] = m.Provider
+
+	for toolName, profile := range m.Profiles {
+		toolCfg := cfg.Tools[toolName]
+		if toolCfg.Profiles == nil {
+			toolCfg.Profiles = make(map[string]*ToolProfile)
+		}
+		toolCfg.Profiles[m.Name] = profile
+	}
+}
+
+// ConfiguredProviderNames returns every name used by a global provider or tool profile
+func ConfiguredProviderNames(cfg *Config) map[string]bool {
+	names := make(map[string]bool)
+	for name := range cfg.Providers {
+		names[name] = true
+	}
+	for _, tool := range cfg.Tools {
+		if tool == nil {
+			continue
+		}
+		for profileName := range tool.Profiles {
+			names[profileName] = true
+		}
+	}
+	return names
+}
+
+// sortedPresetTools returns the preset's tool names in stable order
+func sortedPresetTools(preset *provider.EndpointPreset) []string {
+	names := make([]string, 0, len(preset.Tools))
+	for name := range preset.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
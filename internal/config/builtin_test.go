@@ -0,0 +1,126 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fakecore/aim/internal/constants"
+)
+
+func TestMaterializeBuiltinProvider(t *testing.T) {
+	cfg := DefaultConfig()
+
+	m, err := MaterializeBuiltinProvider(cfg, "glm", "coding", "zai", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "zai" || m.Builtin != "glm" || m.Endpoint != "coding" || m.Region != "cn" || len(m.Skipped) != 0 {
+		t.Errorf("materialization = %+v", m)
+	}
+
+	// The global provider mirrors the preset's codex endpoint
+	wantProvider := &Provider{
+		BaseURL: "https://open.bigmodel.cn/api/coding/paas/v4",
+		Model:   "glm-4.6",
+		Timeout: int(constants.GLMCodingTimeout.Milliseconds()),
+	}
+	if !reflect.DeepEqual(m.Provider, wantProvider) {
+		t.Errorf("provider = %+v, want %+v", m.Provider, wantProvider)
+	}
+
+	tiers := map[string]string{"fast": "glm-4.5-air", "balanced": "glm-4.6", "strong": "glm-4.6"}
+	wantProfiles := map[string]*ToolProfile{
+		// codex has no tool-level key mapping, so the profile maps the provider's variable
+		"codex": {
+			Provider:       "zai",
+			FieldMapping:   map[string]string{"GLM_API_KEY": keyFieldPath},
+			Models:         tiers,
+			ProviderWiring: ProviderWiring{DisplayName: "GLM"},
+		},
+		// claude-code maps the key tool-wide and its defaults already set the preset env
+		"claude-code": {
+			Provider: "zai",
+			BaseURL:  "https://open.bigmodel.cn/api/anthropic",
+			Timeout:  int(constants.GLMTimeout.Milliseconds()),
+			Models:   tiers,
+		},
+	}
+	if !reflect.DeepEqual(m.Profiles, wantProfiles) {
+		for name, profile := range m.Profiles {
+			t.Errorf("profile %s = %+v", name, profile)
+		}
+	}
+
+	m.Apply(cfg)
+	cfg.Keys = map[string]*Key{"z": {Provider: "zai", Key: "sk-z"}}
+	resolver := NewResolver(cfg)
+
+	tests := []struct {
+		tool string
+		want map[string]string
+	}{
+		{"codex", map[string]string{
+			"GLM_API_KEY":     "sk-z",
+			"OPENAI_BASE_URL": "https://open.bigmodel.cn/api/coding/paas/v4",
+			"OPENAI_MODEL":    "glm-4.6",
+		}},
+		{"claude-code", map[string]string{
+			"ANTHROPIC_AUTH_TOKEN": "sk-z",
+			"ANTHROPIC_BASE_URL":   "https://open.bigmodel.cn/api/anthropic",
+			"ANTHROPIC_MODEL":      "glm-4.6",
+		}},
+	}
+	for _, tt := range tests {
+		runtime, err := resolver.Resolve(tt.tool, "z", "zai")
+		if err != nil {
+			t.Fatalf("Resolve(%s): %v", tt.tool, err)
+		}
+		for name, want := range tt.want {
+			if got := runtime.EnvVars[name]; got != want {
+				t.Errorf("%s %s = %q, want %q", tt.tool, name, got, want)
+			}
+		}
+	}
+}
+
+func TestMaterializeBuiltinProviderRegionAndNames(t *testing.T) {
+	cfg := DefaultConfig()
+	delete(cfg.Tools, "claude-code")
+
+	// An explicit region moves the preset hosts; tools the config lacks are reported
+	m, err := MaterializeBuiltinProvider(cfg, "glm", "coding", "", "intl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Region != "intl" || m.Provider.BaseURL != "https://api.z.ai/api/coding/paas/v4" {
+		t.Errorf("region = %q, base URL = %q", m.Region, m.Provider.BaseURL)
+	}
+	if !reflect.DeepEqual(m.Skipped, []string{"claude-code"}) {
+		t.Errorf("skipped = %v, want [claude-code]", m.Skipped)
+	}
+	// glm-coding is taken by the default config, so another name is suggested
+	if m.Name == "" || m.Name == "glm-coding" || ConfiguredProviderNames(cfg)[m.Name] {
+		t.Errorf("suggested name %q is empty or taken", m.Name)
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		provider string
+		region   string
+		wantErr  string
+	}{
+		{"existing name", "coding", "glm", "", "already exists"},
+		{"unknown endpoint", "premium", "zai", "", "premium"},
+		{"unknown region", "coding", "zai", "mars", "glm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MaterializeBuiltinProvider(cfg, "glm", tt.endpoint, tt.provider, tt.region)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Structured credential types for providers that don't authenticate with a single API key
+const (
+	CredentialTypeAWS = "aws" // AWS Bedrock
+	CredentialTypeGCP = "gcp" // Google Vertex AI
+)
+
+// Credentials holds multi-field cloud credentials
+// Values may reference environment variables like ${AWS_SECRET_ACCESS_KEY}.
+type Credentials struct {
+	Type   string `yaml:"type"`             // aws or gcp
+	Region string `yaml:"region,omitempty"` // AWS region or Vertex region
+
+	// AWS: either an access key pair (optionally with a session token) or a named profile
+	AccessKeyID     string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	SessionToken    string `yaml:"session_token,omitempty"`
+	Profile         string `yaml:"profile,omitempty"`
+
+	// GCP: project plus an optional service account file (application default credentials otherwise)
+	ProjectID       string `yaml:"project_id,omitempty"`
+	CredentialsFile string `yaml:"credentials_file,omitempty"`
+}
+
+// Validate checks that the fields required by the credential type are set
+func (c *Credentials) Validate() error {
+	switch c.Type {
+	case CredentialTypeAWS:
+		if c.Region == "" {
+			return fmt.Errorf("aws credentials need a region")
+		}
+		if c.Profile == "" && (c.AccessKeyID == "" || c.SecretAccessKey == "") {
+			return fmt.Errorf("aws credentials need an access key ID and secret access key, or a profile")
+		}
+	case CredentialTypeGCP:
+		if c.ProjectID == "" {
+			return fmt.Errorf("gcp credentials need a project ID")
+		}
+		if c.Region == "" {
+			return fmt.Errorf("gcp credentials need a region")
+		}
+	default:
+		return fmt.Errorf("unknown credential type '%s' (supported: %s, %s)", c.Type, CredentialTypeAWS, CredentialTypeGCP)
+	}
+	return nil
+}
+
+// Env returns the environment variables the cloud SDKs read for these credentials
+func (c *Credentials) Env() map[string]string {
+	env := make(map[string]string)
+	set := func(name, value string) {
+		if value != "" {
+			env[name] = value
+		}
+	}
+
+	switch c.Type {
+	case CredentialTypeAWS:
+		set("AWS_REGION", c.Region)
+		set("AWS_ACCESS_KEY_ID", c.AccessKeyID)
+		set("AWS_SECRET_ACCESS_KEY", c.SecretAccessKey)
+		set("AWS_SESSION_TOKEN", c.SessionToken)
+		set("AWS_PROFILE", c.Profile)
+	case CredentialTypeGCP:
+		set("ANTHROPIC_VERTEX_PROJECT_ID", c.ProjectID)
+		set("CLOUD_ML_REGION", c.Region)
+		set("GOOGLE_APPLICATION_CREDENTIALS", c.CredentialsFile)
+	}
+	return env
+}
+
+// Summary describes the credentials without revealing secrets
+func (c *Credentials) Summary() string {
+	switch c.Type {
+	case CredentialTypeAWS:
+		if c.Profile != "" {
+			return fmt.Sprintf("aws profile %s, region %s", c.Profile, c.Region)
+		}
+		return fmt.Sprintf("aws access key %s, region %s", maskCredential(c.AccessKeyID), c.Region)
+	case CredentialTypeGCP:
+		return fmt.Sprintf("gcp project %s, region %s", c.ProjectID, c.Region)
+	}
+	return c.Type
+}
+
+// hasLiteralSecret reports whether a secret field holds a value instead of an environment reference
+func (c *Credentials) hasLiteralSecret() bool {
+	for _, secret := range []string{c.SecretAccessKey, c.SessionToken} {
+		if secret != "" && !strings.Contains(secret, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+// expandEnv expands environment variable references in every field
+func (c *Credentials) expandEnv() {
+	for _, field := range []*string{&c.Region, &c.AccessKeyID, &c.SecretAccessKey, &c.SessionToken, &c.Profile, &c.ProjectID, &c.CredentialsFile} {
+		*field = os.ExpandEnv(*field)
+	}
+}
+
+// maskCredential shows the first and last four characters of an identifier
+func maskCredential(value string) string {
+	if len(value) <= 8 {
+		return "****"
+	}
+	return value[:4] + "****" + value[len(value)-4:]
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// Entity kinds in the reference graph
+const (
+	EntityKey      = "key"
+	EntityProvider = "provider"
+	EntityProfile  = "profile" // Named <tool>/<profile>
+	EntityTool     = "tool"
+	EntitySettings = "settings"
+	EntityState    = "state"
+)
+
+// Entity is a node in the reference graph
+type Entity struct {
+	Kind string
+	Name string
+}
+
+// String renders an entity as "<kind> <name>", or just the kind for settings and state
+func (e Entity) String() string {
+	if e.Name == "" {
+		return e.Kind
+	}
+	return e.Kind + " " + e.Name
+}
+
+// Edge is a reference from one entity to another, found at a dotted config or state location
+type Edge struct {
+	From     Entity
+	To       Entity
+	Location string
+	repoint  func(cfg *Config, state *State, name string) // Points the reference at another entity
+	drop     func(cfg *Config, state *State)              // Removes or clears the referencing entry
+}
+
+// Graph holds every reference between keys, providers, profiles, tools, settings and state
+type Graph struct {
+	Entities []Entity
+	Edges    []Edge
+}
+
+// BuildGraph collects references from keys to providers, profiles to providers,
+// tools to the tools they take profiles from, headers to keys, settings to
+// key/tool/provider and state to tool/key/provider
+func BuildGraph(cfg *Config, state *State) *Graph {
+	g := &Graph{}
+	seen := make(map[Entity]bool)
+	addEntity := func(e Entity) {
+		if !seen[e] {
+			seen[e] = true
+			g.Entities = append(g.Entities, e)
+		}
+	}
+	addEdge := func(edge Edge) {
+		addEntity(edge.From)
+		addEntity(edge.To)
+		g.Edges = append(g.Edges, edge)
+	}
+	// field builds an edge for a plain name field; dropping it clears the field
+	field := func(from, to Entity, location string, set func(cfg *Config, state *State, name string)) Edge {
+		return Edge{From: from, To: to, Location: location, repoint: set, drop: func(cfg *Config, state *State) {
+			set(cfg, state, "")
+		}}
+	}
+	// headerEdges adds an edge per {keys.<name>} reference; dropping it removes the header
+	headerEdges := func(from Entity, location string, headers func(cfg *Config) map[string]string) {
+		current := headers(cfg)
+		for _, header := range sortedStrings(current) {
+			for _, keyName := range headerKeyRefs(current[header]) {
+				oldRef := "{keys." + keyName + "}"
+				addEdge(Edge{
+					From:     from,
+					To:       Entity{EntityKey, keyName},
+					Location: fmt.Sprintf("%s.headers.%s", location, header),
+					repoint: func(cfg *Config, state *State, name string) {
+						if h := headers(cfg); h != nil {
+							h[header] = strings.ReplaceAll(h[header], oldRef, "{keys."+name+"}")
+						}
+					},
+					drop: func(cfg *Config, state *State) {
+						delete(headers(cfg), header)
+					},
+				})
+			}
+		}
+	}
+
+	for _, name := range sortedProviders(cfg.Providers) {
+		addEntity(Entity{EntityProvider, name})
+		headerEdges(Entity{EntityProvider, name}, "providers."+name, func(cfg *Config) map[string]string {
+			if p := cfg.Providers[name]; p != nil {
+				return p.Headers
+			}
+			return nil
+		})
+	}
+	for _, name := range sortedKeys(cfg.Keys) {
+		addEntity(Entity{EntityKey, name})
+		if key := cfg.Keys[name]; key != nil && key.Provider != "" {
+			edge := field(Entity{EntityKey, name}, Entity{EntityProvider, key.Provider}, fmt.Sprintf("keys.%s.provider", name),
+				func(cfg *Config, state *State, value string) {
+					if key := cfg.Keys[name]; key != nil {
+						key.Provider = value
+					}
+				})
+			edge.drop = func(cfg *Config, state *State) {
+				delete(cfg.Keys, name)
+			}
+			addEdge(edge)
+		}
+	}
+	for _, toolName := range sortedTools(cfg.Tools) {
+		tool := cfg.Tools[toolName]
+		addEntity(Entity{EntityTool, toolName})
+		if tool == nil {
+			continue
+		}
+		if tool.ProfilesFrom != "" {
+			addEdge(field(Entity{EntityTool, toolName}, Entity{EntityTool, tool.ProfilesFrom}, fmt.Sprintf("tools.%s.profiles_from", toolName),
+				func(cfg *Config, state *State, name string) {
+					if tool := cfg.Tools[toolName]; tool != nil {
+						tool.ProfilesFrom = name
+					}
+				}))
+		}
+		for _, profileName := range sortedProfiles(tool.Profiles) {
+			profile := tool.Profiles[profileName]
+			from := Entity{EntityProfile, toolName + "/" + profileName}
+			location := fmt.Sprintf("tools.%s.profiles.%s", toolName, profileName)
+			profileOf := func(cfg *Config) *ToolProfile {
+				if tool := cfg.Tools[toolName]; tool != nil {
+					return tool.Profiles[profileName]
+				}
+				return nil
+			}
+			// Profiles belong to their tool and go with it, so this edge only records ownership
+			addEdge(Edge{From: from, To: Entity{EntityTool, toolName}, Location: location})
+			if profile == nil {
+				continue
+			}
+			if profile.Provider != "" {
+				edge := field(from, Entity{EntityProvider, profile.Provider}, location+".provider",
+					func(cfg *Config, state *State, value string) {
+						if profile := profileOf(cfg); profile != nil {
+							profile.Provider = value
+						}
+					})
+				edge.drop = func(cfg *Config, state *State) {
+					if tool := cfg.Tools[toolName]; tool != nil {
+						delete(tool.Profiles, profileName)
+					}
+				}
+				addEdge(edge)
+			}
+			headerEdges(from, location, func(cfg *Config) map[string]string {
+				if profile := profileOf(cfg); profile != nil {
+					return profile.Headers
+				}
+				return nil
+			})
+		}
+	}
+
+	settings := Entity{Kind: EntitySettings}
+	if cfg.Settings.DefaultKey != "" {
+		addEdge(field(settings, Entity{EntityKey, cfg.Settings.DefaultKey}, "settings.default_key",
+			func(cfg *Config, state *State, name string) { cfg.Settings.DefaultKey = name }))
+	}
+	if cfg.Settings.DefaultTool != "" {
+		addEdge(field(settings, Entity{EntityTool, cfg.Settings.DefaultTool}, "settings.default_tool",
+			func(cfg *Config, state *State, name string) { cfg.Settings.DefaultTool = name }))
+	}
+	if cfg.Settings.DefaultProvider != "" {
+		addEdge(field(settings, Entity{EntityProvider, cfg.Settings.DefaultProvider}, "settings.default_provider",
+			func(cfg *Config, state *State, name string) { cfg.Settings.DefaultProvider = name }))
+	}
+
+	if state != nil {
+		st := Entity{Kind: EntityState}
+		if state.Current.Tool != "" {
+			addEdge(field(st, Entity{EntityTool, state.Current.Tool}, "state.current.tool",
+				func(cfg *Config, state *State, name string) { state.Current.Tool = name }))
+		}
+		if state.Current.Key != "" {
+			addEdge(field(st, Entity{EntityKey, state.Current.Key}, "state.current.key",
+				func(cfg *Config, state *State, name string) { state.Current.Key = name }))
+		}
+		if state.Current.Provider != "" {
+			addEdge(field(st, Entity{EntityProvider, state.Current.Provider}, "state.current.provider",
+				func(cfg *Config, state *State, name string) { state.Current.Provider = name }))
+		}
+		for _, toolName := range sortedToolStates(state) {
+			toolState := state.Tools[toolName]
+			if toolState.Key != "" {
+				addEdge(field(st, Entity{EntityKey, toolState.Key}, fmt.Sprintf("state.tools.%s.key", toolName),
+					func(cfg *Config, state *State, name string) { state.Tools[toolName].Key = name }))
+			}
+			if toolState.Provider != "" {
+				addEdge(field(st, Entity{EntityProvider, toolState.Provider}, fmt.Sprintf("state.tools.%s.provider", toolName),
+					func(cfg *Config, state *State, name string) { state.Tools[toolName].Provider = name }))
+			}
+		}
+	}
+
+	return g
+}
+
+// ReferencesTo returns the edges that point at an entity
+func (g *Graph) ReferencesTo(to Entity) []Edge {
+	var edges []Edge
+	for _, edge := range g.Edges {
+		if edge.To == to {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Dangling returns the referenced entities that don't exist: keys and tools missing from config,
+// and providers that are neither configured, builtin nor the name of a tool profile
+func (g *Graph) Dangling(cfg *Config) []Entity {
+	var missing []Entity
+	for _, e := range g.Entities {
+		switch e.Kind {
+		case EntityKey:
+			if _, ok := cfg.Keys[e.Name]; !ok {
+				missing = append(missing, e)
+			}
+		case EntityTool:
+			if _, ok := cfg.Tools[e.Name]; !ok {
+				missing = append(missing, e)
+			}
+		case EntityProvider:
+			if !cfg.knownProvider(e.Name) {
+				missing = append(missing, e)
+			}
+		}
+	}
+	return missing
+}
+
+// knownProvider reports whether a provider name resolves to a configured provider, builtin preset or tool profile
+func (c *Config) knownProvider(name string) bool {
+	if _, ok := c.Providers[name]; ok {
+		return true
+	}
+	if _, ok := provider.ResolveBuiltinProvider(name); ok {
+		return true
+	}
+	for _, tool := range c.Tools {
+		if tool == nil {
+			continue
+		}
+		if _, ok := tool.Profiles[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// headerKeyRefs returns the key names a header value references with {keys.<name>}
+func headerKeyRefs(value string) []string {
+	var names []string
+	for _, m := range headerRefPattern.FindAllStringSubmatch(value, -1) {
+		if m[2] != "" {
+			names = append(names, m[2])
+		}
+	}
+	return names
+}
+
+// sortedProviders returns provider names in stable order
+func sortedProviders(providers map[string]*Provider) []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
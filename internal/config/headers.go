@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// Authentication schemes for sending the key
+const (
+	AuthSchemeBearer  = "bearer"    // Authorization: Bearer <key> (default)
+	AuthSchemeXAPIKey = "x-api-key" // x-api-key: <key>
+	AuthSchemeNone    = "none"      // No key is sent, e.g. gateways that authenticate through headers
+)
+
+// ValidateAuthScheme checks an auth_scheme value
+func ValidateAuthScheme(scheme string) error {
+	switch scheme {
+	case "", AuthSchemeBearer, AuthSchemeXAPIKey, AuthSchemeNone:
+		return nil
+	}
+	return fmt.Errorf("unknown auth_scheme '%s' (supported: %s, %s, %s)", scheme, AuthSchemeBearer, AuthSchemeXAPIKey, AuthSchemeNone)
+}
+
+// headerRefPattern matches secret references in header values: ${VAR}, {key} and {keys.<name>}
+var headerRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\{key\}|\{keys\.([^{}]+)\}`)
+
+// resolveHeaders expands secret references in header values
+// {key} is the current key, {keys.<name>} another configured key and ${VAR} an environment variable.
+// It also returns the names of headers whose values came from a reference, so tools can keep them off the command line.
+func (r *Resolver) resolveHeaders(headers map[string]string, key *Key) (map[string]string, map[string]bool, error) {
+	if len(headers) == 0 {
+		return nil, nil, nil
+	}
+
+	resolved := make(map[string]string, len(headers))
+	var secret map[string]bool
+	for _, name := range sortedHeaderNames(headers) {
+		var err error
+		value := headerRefPattern.ReplaceAllStringFunc(headers[name], func(ref string) string {
+			m := headerRefPattern.FindStringSubmatch(ref)
+			switch {
+			case m[1] != "":
+				v, ok := os.LookupEnv(m[1])
+				if !ok && err == nil {
+					err = fmt.Errorf("header '%s' references unset environment variable '%s'", name, m[1])
+				}
+				return v
+			case m[2] != "":
+				other, ok := r.config.GetKey(m[2])
+				if !ok && err == nil {
+					err = fmt.Errorf("header '%s' references unknown key '%s'", name, m[2])
+				}
+				if !ok {
+					return ""
+				}
+				return other.Key
+			}
+			return key.Key
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if value != headers[name] {
+			if secret == nil {
+				secret = make(map[string]bool)
+			}
+			secret[name] = true
+		}
+		resolved[name] = value
+	}
+	return resolved, secret, nil
+}
+
+// sortedHeaderNames returns header names in stable order
+func sortedHeaderNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
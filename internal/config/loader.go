@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/fakecore/aim/configs"
 	"github.com/fakecore/aim/internal/provider"
@@ -50,8 +52,18 @@ func (l *Loader) GetLocalPath() string {
 	return l.localPath
 }
 
+// GetProvidersDir returns the providers.d directory next to the global configuration file
+func (l *Loader) GetProvidersDir() string {
+	return filepath.Join(filepath.Dir(l.globalPath), "providers.d")
+}
+
 // Load loads and merges configuration from all sources
 func (l *Loader) Load() (*Config, error) {
+	// 0. Merge external provider definitions over the builtin registry
+	if err := provider.LoadDefinitions(l.GetProvidersDir()); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: ignoring invalid provider definitions: %v\n", err)
+	}
+
 	// 1. Load global configuration (this should be the main config file)
 	global, err := l.loadGlobal()
 	if err != nil {
@@ -66,6 +78,7 @@ func (l *Loader) Load() (*Config, error) {
 	// 2. Load local/project configuration (if exists)
 	local, err := l.loadLocal()
 	if err == nil {
+		l.warnLiteralKeys(local)
 		cfg = l.mergeConfigs(cfg, local)
 	} else if !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to load local config: %w", err)
@@ -101,6 +114,40 @@ func (l *Loader) loadLocal() (*Config, error) {
 	return l.loadFile(path)
 }
 
+// FindLocalConfig returns the path of the nearest .aim.yaml, or os.ErrNotExist
+func (l *Loader) FindLocalConfig() (string, error) {
+	return l.findLocalConfig()
+}
+
+// warnLiteralKeys warns when a project config embeds API keys instead of env references
+// Project configs tend to get committed, so literal keys there are likely to leak
+func (l *Loader) warnLiteralKeys(local *Config) {
+	names := LiteralKeyNames(local)
+	if len(names) == 0 {
+		return
+	}
+
+	path, _ := l.findLocalConfig()
+	fmt.Fprintf(os.Stderr, "⚠️  Warning: project config %s contains literal API keys: %s\n", path, strings.Join(names, ", "))
+	fmt.Fprintf(os.Stderr, "   Use ${ENV_VAR} references instead, and run 'aim keys scan --history' to check for leaks\n")
+}
+
+// LiteralKeyNames returns the names of keys whose values are literal secrets
+// (not empty and not an environment variable reference)
+func LiteralKeyNames(cfg *Config) []string {
+	var names []string
+	for name, key := range cfg.Keys {
+		if key == nil {
+			continue
+		}
+		if (key.Key != "" && !strings.Contains(key.Key, "$")) || (key.Credentials != nil && key.Credentials.hasLiteralSecret()) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // findLocalConfig searches for .aim.yaml in current and parent directories
 func (l *Loader) findLocalConfig() (string, error) {
 	dir, err := os.Getwd()
@@ -261,6 +308,12 @@ func (l *Loader) mergeConfigs(base, override *Config) *Config {
 	if override.Settings.Language != "" {
 		result.Settings.Language = override.Settings.Language
 	}
+	if override.Settings.LowBalanceThreshold > 0 {
+		result.Settings.LowBalanceThreshold = override.Settings.LowBalanceThreshold
+	}
+	if override.Settings.Region != "" {
+		result.Settings.Region = override.Settings.Region
+	}
 
 	// Merge keys
 	if result.Keys == nil {
@@ -327,6 +380,9 @@ func (l *Loader) expandEnvVars(cfg *Config) *Config {
 		for name, key := range result.Keys {
 			if key != nil {
 				key.Key = os.ExpandEnv(key.Key)
+				if key.Credentials != nil {
+					key.Credentials.expandEnv()
+				}
 				result.Keys[name] = key
 			}
 		}
@@ -390,7 +446,8 @@ func (l *Loader) addBuiltinProviders(cfg *Config) *Config {
 	// Add built-in providers with OpenAI-compatible configurations
 	builtinProviders := provider.GetBuiltinProviders()
 	for providerName, providerInfo := range builtinProviders {
-		if len(providerInfo.Endpoints) == 0 {
+		// Local servers and cloud-credential providers are opt-in via 'aim provider add --builtin'
+		if len(providerInfo.Endpoints) == 0 || providerInfo.Local || providerInfo.Credentials != "" {
 			continue
 		}
 
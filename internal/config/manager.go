@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"gopkg.in/yaml.v3"
 )
 
 // ConfigManager manages global configuration state
@@ -223,6 +225,110 @@ func (cm *ConfigManager) forceSaveUnsafe() error {
 	return nil
 }
 
+// FileWrite is an extra file written together with config and state in a transaction
+type FileWrite struct {
+	Path string
+	Data []byte
+}
+
+// fileSnapshot records a file's content and mode before a transaction touches it
+type fileSnapshot struct {
+	path    string
+	data    []byte
+	mode    os.FileMode
+	existed bool
+}
+
+// Transaction applies update to config and state, then writes config, state and
+// any extra files. If a write fails, files already written are restored.
+func (cm *ConfigManager) Transaction(update func(*Config, *State), extra []FileWrite) error {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	if !cm.initialized {
+		return fmt.Errorf("configuration not initialized")
+	}
+
+	paths := []string{cm.loader.globalPath, cm.stateMgr.statePath}
+	for _, write := range extra {
+		paths = append(paths, write.Path)
+	}
+
+	snapshots := make([]fileSnapshot, 0, len(paths))
+	for _, path := range paths {
+		snap := fileSnapshot{path: path, mode: 0600}
+		if info, err := os.Stat(path); err == nil {
+			snap.mode = info.Mode().Perm()
+		}
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		snap.data, snap.existed = data, err == nil
+		snapshots = append(snapshots, snap)
+	}
+
+	// In-memory copies to restore if a write fails
+	savedConfig, err := cloneYAML(cm.config)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot config: %w", err)
+	}
+	savedState, err := cloneYAML(cm.state)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot state: %w", err)
+	}
+
+	update(cm.config, cm.state)
+
+	written := 0
+	rollback := func(cause error) error {
+		for _, snap := range snapshots[:written] {
+			if snap.existed {
+				os.WriteFile(snap.path, snap.data, snap.mode)
+				os.Chmod(snap.path, snap.mode)
+			} else {
+				os.Remove(snap.path)
+			}
+		}
+		cm.config, cm.state = savedConfig, savedState
+		return fmt.Errorf("transaction rolled back: %w", cause)
+	}
+
+	if err := cm.loader.SaveGlobal(cm.config); err != nil {
+		return rollback(fmt.Errorf("failed to save config: %w", err))
+	}
+	written++
+
+	if err := cm.stateMgr.Save(cm.state); err != nil {
+		return rollback(fmt.Errorf("failed to save state: %w", err))
+	}
+	written++
+
+	// Managed tool configs can hold keys, so they keep their mode (new ones are owner-only)
+	for i, write := range extra {
+		if err := os.WriteFile(write.Path, write.Data, snapshots[2+i].mode); err != nil {
+			return rollback(fmt.Errorf("failed to write %s: %w", write.Path, err))
+		}
+		written++
+	}
+
+	cm.modified = false
+	return nil
+}
+
+// cloneYAML deep-copies a config or state through its YAML form
+func cloneYAML[T any](v *T) (*T, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var clone T
+	if err := yaml.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
 // GetConfigPath returns the global configuration file path
 func (cm *ConfigManager) GetConfigPath() string {
 	return cm.loader.GetGlobalPath()
@@ -233,6 +339,11 @@ func (cm *ConfigManager) GetLocalConfigPath() string {
 	return cm.loader.GetLocalPath()
 }
 
+// GetProvidersDir returns the directory holding external provider definitions
+func (cm *ConfigManager) GetProvidersDir() string {
+	return cm.loader.GetProvidersDir()
+}
+
 // InitGlobal initializes the global configuration file
 func (cm *ConfigManager) InitGlobal() error {
 	return cm.loader.InitGlobal()
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// GetAIMHome returns the AIM data directory (~/.aim by default)
+// AIM_HOME overrides the location (used by test environments)
+func GetAIMHome() string {
+	if aimHome := os.Getenv("AIM_HOME"); aimHome != "" {
+		return aimHome
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".aim")
+}
@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestKeyRegion(t *testing.T) {
+	cfg := &Config{
+		Keys: map[string]*Key{
+			"kimi-cn":   {Provider: "kimi", Key: "sk-cn"},
+			"kimi-intl": {Provider: "kimi-intl", Key: "sk-intl"},
+			"custom":    {Provider: "proxy", Key: "sk-proxy"},
+		},
+		Tools: map[string]*ToolConfig{
+			"codex": {
+				Command: "codex",
+				Profiles: map[string]*ToolProfile{
+					"kimi":      {Provider: "kimi", BaseURL: "https://api.moonshot.cn/v1", Model: "kimi-k2-turbo-preview"},
+					"kimi-intl": {Provider: "kimi-intl", BaseURL: "https://api.moonshot.ai/v1", Model: "kimi-k2-turbo-preview"},
+					"proxy":     {Provider: "proxy", BaseURL: "https://proxy.example.com/v1", Model: "m"},
+				},
+			},
+		},
+	}
+	resolver := NewResolver(cfg)
+
+	tests := map[string]string{"kimi-cn": "cn", "kimi-intl": "intl", "custom": ""}
+	for key, want := range tests {
+		if got := resolver.KeyRegion(key); got != want {
+			t.Errorf("KeyRegion(%s) = %q, want %q", key, got, want)
+		}
+	}
+
+	// settings.region moves builtin hosts, so it decides for keys on the default host
+	cfg.Settings.Region = "intl"
+	if got := resolver.KeyRegion("kimi-cn"); got != "intl" {
+		t.Errorf("KeyRegion with settings.region intl = %q, want intl", got)
+	}
+}
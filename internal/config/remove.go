@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemoveOptions selects what happens to entries that still reference a removed entity
+type RemoveOptions struct {
+	Cascade  bool   // Remove dependent keys, profiles and headers; clear settings and state
+	Reassign string // Point every reference at this entity instead
+}
+
+// RemovalPlan is the full set of changes made by removing one entity
+// References with an empty New value are removed or cleared.
+type RemovalPlan struct {
+	Kind       string // key, provider or tool
+	Name       string
+	References []Reference
+	Keys       []string // Keys removed, including cascaded ones
+}
+
+// Apply makes every change in the given config and state
+func (p *RemovalPlan) Apply(cfg *Config, state *State) {
+	for _, ref := range p.References {
+		ref.apply(cfg, state)
+	}
+}
+
+// ReferencedError is returned when removing an entity that is still referenced without --cascade or --reassign
+type ReferencedError struct {
+	Entity     Entity
+	References []Edge
+}
+
+func (e *ReferencedError) Error() string {
+	return fmt.Sprintf("%s '%s' is still referenced by %d entry(s); use --cascade to remove them or --reassign <name> to point them elsewhere",
+		e.Entity.Kind, e.Entity.Name, len(e.References))
+}
+
+// PlanKeyRemoval plans removing a key; references are settings, state and header {keys.<name>} values
+func PlanKeyRemoval(cfg *Config, state *State, name string, opts RemoveOptions) (*RemovalPlan, error) {
+	if _, ok := cfg.Keys[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	if opts.Reassign != "" {
+		if _, ok := cfg.Keys[opts.Reassign]; !ok {
+			return nil, fmt.Errorf("cannot reassign to key '%s': %w", opts.Reassign, ErrKeyNotFound)
+		}
+	}
+	return planRemoval(cfg, state, Entity{EntityKey, name}, opts, func(cfg *Config, state *State) {
+		delete(cfg.Keys, name)
+	})
+}
+
+// PlanProviderRemoval plans removing a global provider; references are keys, profiles, settings and state
+// Cascading removes the provider's keys (and their references) and the profiles that use it.
+func PlanProviderRemoval(cfg *Config, state *State, name string, opts RemoveOptions) (*RemovalPlan, error) {
+	if _, ok := cfg.Providers[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+	if opts.Reassign != "" && !cfg.knownProvider(opts.Reassign) {
+		return nil, fmt.Errorf("cannot reassign to provider '%s': %w", opts.Reassign, ErrProviderNotFound)
+	}
+	return planRemoval(cfg, state, Entity{EntityProvider, name}, opts, func(cfg *Config, state *State) {
+		delete(cfg.Providers, name)
+	})
+}
+
+// PlanToolRemoval plans removing a tool with its profiles and state; references are settings and state
+func PlanToolRemoval(cfg *Config, state *State, name string, opts RemoveOptions) (*RemovalPlan, error) {
+	if _, ok := cfg.Tools[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+	if opts.Reassign != "" {
+		if _, ok := cfg.Tools[opts.Reassign]; !ok {
+			return nil, fmt.Errorf("cannot reassign to tool '%s': %w", opts.Reassign, ErrToolNotFound)
+		}
+	}
+	plan, err := planRemoval(cfg, state, Entity{EntityTool, name}, opts, func(cfg *Config, state *State) {
+		delete(cfg.Tools, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Tools[name] != nil {
+		plan.References = append(plan.References, Reference{Location: "state.tools." + name, Old: name, apply: func(cfg *Config, state *State) {
+			delete(state.Tools, name)
+		}})
+	}
+	return plan, nil
+}
+
+// planRemoval removes an entity and resolves every reference to it according to opts
+func planRemoval(cfg *Config, state *State, target Entity, opts RemoveOptions, remove func(cfg *Config, state *State)) (*RemovalPlan, error) {
+	if opts.Cascade && opts.Reassign != "" {
+		return nil, fmt.Errorf("--cascade and --reassign cannot be used together")
+	}
+	if opts.Reassign == target.Name {
+		return nil, fmt.Errorf("cannot reassign %s '%s' to itself", target.Kind, target.Name)
+	}
+
+	var refs []Edge
+	for _, edge := range BuildGraph(cfg, state).ReferencesTo(target) {
+		if edge.drop != nil {
+			refs = append(refs, edge)
+		}
+	}
+	if len(refs) > 0 && !opts.Cascade && opts.Reassign == "" {
+		return nil, &ReferencedError{Entity: target, References: refs}
+	}
+
+	plan := &RemovalPlan{Kind: target.Kind, Name: target.Name}
+	plan.References = append(plan.References, Reference{Location: removalLocation(target), Old: target.Name, apply: remove})
+	if target.Kind == EntityKey {
+		plan.Keys = append(plan.Keys, target.Name)
+	}
+
+	for _, edge := range refs {
+		edge := edge
+		switch {
+		case opts.Reassign != "" && edge.From == (Entity{target.Kind, opts.Reassign}):
+			// The reassign target would end up referencing itself, e.g. a tool taking profiles from itself
+			plan.References = append(plan.References, Reference{Location: edge.Location, Old: target.Name, apply: edge.drop})
+		case opts.Reassign != "":
+			plan.References = append(plan.References, Reference{Location: edge.Location, Old: target.Name, New: opts.Reassign,
+				apply: func(cfg *Config, state *State) { edge.repoint(cfg, state, opts.Reassign) }})
+		case edge.From.Kind == EntityKey:
+			// A removed key takes its own references with it
+			keyPlan, err := PlanKeyRemoval(cfg, state, edge.From.Name, RemoveOptions{Cascade: true})
+			if err != nil {
+				return nil, err
+			}
+			plan.References = append(plan.References, keyPlan.References...)
+			plan.Keys = append(plan.Keys, keyPlan.Keys...)
+		case edge.From.Kind == EntityProfile && edge.To.Kind == EntityProvider:
+			// A profile without its provider is removed whole
+			plan.References = append(plan.References, Reference{Location: removalLocation(edge.From), Old: edge.From.Name, apply: edge.drop})
+		default:
+			plan.References = append(plan.References, Reference{Location: edge.Location, Old: target.Name, apply: edge.drop})
+		}
+	}
+
+	return plan, nil
+}
+
+// removalLocation returns the config path of an entity
+func removalLocation(e Entity) string {
+	switch e.Kind {
+	case EntityKey:
+		return "keys." + e.Name
+	case EntityProvider:
+		return "providers." + e.Name
+	case EntityTool:
+		return "tools." + e.Name
+	case EntityProfile:
+		tool, profile, _ := strings.Cut(e.Name, "/")
+		return fmt.Sprintf("tools.%s.profiles.%s", tool, profile)
+	}
+	return e.String()
+}
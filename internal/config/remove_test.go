@@ -0,0 +1,270 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newRemovalFixture returns a config and state where every kind of reference is present:
+// keys -> providers, profiles -> providers, headers -> keys, profiles_from -> tools, settings and state
+func newRemovalFixture() (*Config, *State) {
+	cfg := &Config{
+		Settings: Settings{DefaultKey: "work", DefaultTool: "codex", DefaultProvider: "proxy"},
+		Keys: map[string]*Key{
+			"work":  {Provider: "proxy", Key: "sk-work"},
+			"home":  {Provider: "glm", Key: "sk-home"},
+			"spare": {Provider: "glm", Key: "sk-spare"},
+		},
+		Providers: map[string]*Provider{
+			"proxy": {BaseURL: "https://proxy.example.com", ProviderWiring: ProviderWiring{Headers: map[string]string{"X-Team": "{keys.home}"}}},
+			"glm":   {BaseURL: "https://glm.example.com"},
+		},
+		Tools: map[string]*ToolConfig{
+			"codex": {Command: "codex", Profiles: map[string]*ToolProfile{
+				"proxy": {Provider: "proxy"},
+				"glm":   {Provider: "glm"},
+			}},
+			"claude": {Command: "claude", ProfilesFrom: "codex", Profiles: map[string]*ToolProfile{
+				"glm": {Provider: "glm"},
+			}},
+		},
+	}
+	state := &State{
+		Current: CurrentState{Tool: "codex", Key: "work", Provider: "proxy"},
+		Tools:   ToolStates{"codex": {Key: "home", Provider: "glm"}},
+	}
+	return cfg, state
+}
+
+func TestPlanRemoval(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     func(cfg *Config, state *State) (*RemovalPlan, error)
+		wantErr  string // Substring of the error; "referenced" expects a ReferencedError
+		wantRefs int    // References reported by a ReferencedError
+		wantKeys []string
+		check    func(t *testing.T, cfg *Config, state *State)
+	}{
+		{
+			name: "key refused",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "home", RemoveOptions{})
+			},
+			wantErr:  "referenced",
+			wantRefs: 2, // providers.proxy.headers.X-Team, state.tools.codex.key
+		},
+		{
+			name: "key unreferenced",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "spare", RemoveOptions{})
+			},
+			wantKeys: []string{"spare"},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if _, ok := cfg.Keys["spare"]; ok || len(cfg.Keys) != 2 {
+					t.Errorf("keys = %v", cfg.Keys)
+				}
+			},
+		},
+		{
+			name: "key cascade",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "home", RemoveOptions{Cascade: true})
+			},
+			wantKeys: []string{"home"},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if _, ok := cfg.Keys["home"]; ok {
+					t.Error("key home was not removed")
+				}
+				if headers := cfg.Providers["proxy"].Headers; len(headers) != 0 {
+					t.Errorf("proxy headers = %v, want the {keys.home} header dropped", headers)
+				}
+				if state.Tools["codex"].Key != "" || state.Tools["codex"].Provider != "glm" {
+					t.Errorf("state.tools.codex = %+v", state.Tools["codex"])
+				}
+			},
+		},
+		{
+			name: "key reassign",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "home", RemoveOptions{Reassign: "spare"})
+			},
+			wantKeys: []string{"home"},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if got := cfg.Providers["proxy"].Headers["X-Team"]; got != "{keys.spare}" {
+					t.Errorf("X-Team = %q", got)
+				}
+				if state.Tools["codex"].Key != "spare" {
+					t.Errorf("state.tools.codex.key = %q", state.Tools["codex"].Key)
+				}
+			},
+		},
+		{
+			name: "key reassign to itself",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "home", RemoveOptions{Reassign: "home"})
+			},
+			wantErr: "to itself",
+		},
+		{
+			name: "key reassign to missing",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "home", RemoveOptions{Reassign: "nope"})
+			},
+			wantErr: "cannot reassign",
+		},
+		{
+			name: "cascade and reassign",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanKeyRemoval(cfg, state, "home", RemoveOptions{Cascade: true, Reassign: "spare"})
+			},
+			wantErr: "cannot be used together",
+		},
+		{
+			name: "provider refused",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanProviderRemoval(cfg, state, "proxy", RemoveOptions{})
+			},
+			wantErr:  "referenced",
+			wantRefs: 4, // keys.work.provider, tools.codex.profiles.proxy.provider, settings.default_provider, state.current.provider
+		},
+		{
+			name: "provider cascade",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanProviderRemoval(cfg, state, "proxy", RemoveOptions{Cascade: true})
+			},
+			wantKeys: []string{"work"},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if _, ok := cfg.Providers["proxy"]; ok {
+					t.Error("provider proxy was not removed")
+				}
+				// The key using the provider goes, and takes its own references with it
+				if _, ok := cfg.Keys["work"]; ok {
+					t.Error("key work was not cascaded")
+				}
+				if cfg.Settings.DefaultKey != "" || state.Current.Key != "" {
+					t.Errorf("default_key = %q, state.current.key = %q", cfg.Settings.DefaultKey, state.Current.Key)
+				}
+				if _, ok := cfg.Tools["codex"].Profiles["proxy"]; ok {
+					t.Error("profile codex/proxy was not removed")
+				}
+				if _, ok := cfg.Tools["codex"].Profiles["glm"]; !ok {
+					t.Error("profile codex/glm was removed")
+				}
+				if cfg.Settings.DefaultProvider != "" || state.Current.Provider != "" {
+					t.Errorf("default_provider = %q, state.current.provider = %q", cfg.Settings.DefaultProvider, state.Current.Provider)
+				}
+				if _, ok := cfg.Keys["home"]; !ok || len(cfg.Providers["glm"].Headers) != 0 {
+					t.Error("unrelated entries changed")
+				}
+			},
+		},
+		{
+			name: "provider reassign",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanProviderRemoval(cfg, state, "proxy", RemoveOptions{Reassign: "glm"})
+			},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if _, ok := cfg.Providers["proxy"]; ok {
+					t.Error("provider proxy was not removed")
+				}
+				if cfg.Keys["work"].Provider != "glm" || cfg.Tools["codex"].Profiles["proxy"].Provider != "glm" {
+					t.Errorf("key work -> %q, profile codex/proxy -> %q", cfg.Keys["work"].Provider, cfg.Tools["codex"].Profiles["proxy"].Provider)
+				}
+				if cfg.Settings.DefaultProvider != "glm" || state.Current.Provider != "glm" {
+					t.Errorf("default_provider = %q, state.current.provider = %q", cfg.Settings.DefaultProvider, state.Current.Provider)
+				}
+				if cfg.Settings.DefaultKey != "work" {
+					t.Errorf("default_key = %q, want it kept", cfg.Settings.DefaultKey)
+				}
+			},
+		},
+		{
+			name: "tool refused",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanToolRemoval(cfg, state, "codex", RemoveOptions{})
+			},
+			wantErr:  "referenced",
+			wantRefs: 3, // tools.claude.profiles_from, settings.default_tool, state.current.tool
+		},
+		{
+			name: "tool cascade",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanToolRemoval(cfg, state, "codex", RemoveOptions{Cascade: true})
+			},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if _, ok := cfg.Tools["codex"]; ok {
+					t.Error("tool codex was not removed")
+				}
+				if cfg.Tools["claude"].ProfilesFrom != "" {
+					t.Errorf("claude profiles_from = %q", cfg.Tools["claude"].ProfilesFrom)
+				}
+				if cfg.Settings.DefaultTool != "" || state.Current.Tool != "" {
+					t.Errorf("default_tool = %q, state.current.tool = %q", cfg.Settings.DefaultTool, state.Current.Tool)
+				}
+				if _, ok := state.Tools["codex"]; ok {
+					t.Error("state.tools.codex was not removed")
+				}
+				if len(cfg.Keys) != 3 || len(cfg.Providers) != 2 {
+					t.Error("a tool removal changed keys or providers")
+				}
+			},
+		},
+		{
+			name: "tool reassign",
+			plan: func(cfg *Config, state *State) (*RemovalPlan, error) {
+				return PlanToolRemoval(cfg, state, "codex", RemoveOptions{Reassign: "claude"})
+			},
+			check: func(t *testing.T, cfg *Config, state *State) {
+				if cfg.Settings.DefaultTool != "claude" || state.Current.Tool != "claude" {
+					t.Errorf("default_tool = %q, state.current.tool = %q", cfg.Settings.DefaultTool, state.Current.Tool)
+				}
+				// claude is the target, so its profiles_from is cleared rather than pointed at itself
+				if cfg.Tools["claude"].ProfilesFrom != "" {
+					t.Errorf("claude profiles_from = %q", cfg.Tools["claude"].ProfilesFrom)
+				}
+				if _, ok := state.Tools["codex"]; ok {
+					t.Error("state.tools.codex was not removed")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, state := newRemovalFixture()
+			plan, err := tt.plan(cfg, state)
+
+			switch {
+			case tt.wantErr == "referenced":
+				var refErr *ReferencedError
+				if !errors.As(err, &refErr) {
+					t.Fatalf("err = %v, want a ReferencedError", err)
+				}
+				if len(refErr.References) != tt.wantRefs {
+					t.Errorf("references = %v, want %d", refErr.References, tt.wantRefs)
+				}
+				return
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if tt.wantKeys != nil && !reflect.DeepEqual(plan.Keys, tt.wantKeys) {
+				t.Errorf("plan.Keys = %v, want %v", plan.Keys, tt.wantKeys)
+			}
+			plan.Apply(cfg, state)
+			if tt.check != nil {
+				tt.check(t, cfg, state)
+			}
+			if dangling := BuildGraph(cfg, state).Dangling(cfg); len(dangling) != 0 {
+				t.Errorf("dangling references after removal: %v", dangling)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Reference describes a single place in config or state that is rewritten by a rename
+type Reference struct {
+	Location string // Dotted path, e.g. "settings.default_key" or "state.tools.codex.key"
+	Old      string
+	New      string
+	apply    func(cfg *Config, state *State)
+}
+
+// RenamePlan is the full set of references touched by renaming one entity
+type RenamePlan struct {
+	Kind       string // key, provider or profile
+	Old        string
+	New        string
+	References []Reference
+	Blockers   []Reference // References the rename cannot rewrite; the plan must not be applied while any remain
+}
+
+// Apply rewrites every reference in the given config and state
+func (p *RenamePlan) Apply(cfg *Config, state *State) {
+	for _, ref := range p.References {
+		ref.apply(cfg, state)
+	}
+}
+
+// add appends a reference to the plan
+func (p *RenamePlan) add(location, oldValue, newValue string, apply func(cfg *Config, state *State)) {
+	p.References = append(p.References, Reference{Location: location, Old: oldValue, New: newValue, apply: apply})
+}
+
+// PlanKeyRename plans renaming a key and every setting, state entry and header that refers to it
+func PlanKeyRename(cfg *Config, state *State, oldName, newName string) (*RenamePlan, error) {
+	if err := validateRename("key", oldName, newName); err != nil {
+		return nil, err
+	}
+	if _, ok := cfg.Keys[oldName]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, oldName)
+	}
+	if _, ok := cfg.Keys[newName]; ok {
+		return nil, fmt.Errorf("key '%s' already exists", newName)
+	}
+
+	plan := &RenamePlan{Kind: "key", Old: oldName, New: newName}
+
+	plan.add("keys."+oldName, oldName, newName, func(cfg *Config, state *State) {
+		cfg.Keys[newName] = cfg.Keys[oldName]
+		delete(cfg.Keys, oldName)
+	})
+
+	// Settings, state and header {keys.<name>} values all point at the key through the reference graph
+	seen := make(map[string]bool)
+	for _, edge := range BuildGraph(cfg, state).ReferencesTo(Entity{EntityKey, oldName}) {
+		if edge.repoint == nil || seen[edge.Location] {
+			continue
+		}
+		seen[edge.Location] = true
+		repoint := edge.repoint
+		plan.add(edge.Location, oldName, newName, func(cfg *Config, state *State) {
+			repoint(cfg, state, newName)
+		})
+	}
+
+	return plan, nil
+}
+
+// PlanProviderRename plans renaming a provider and every key, profile, setting and state entry that refers to it
+// Profiles named after the provider are renamed too, since keys select their profile by provider name
+func PlanProviderRename(cfg *Config, state *State, oldName, newName string) (*RenamePlan, error) {
+	if err := validateRename("provider", oldName, newName); err != nil {
+		return nil, err
+	}
+
+	known := false
+	if _, ok := cfg.Providers[oldName]; ok {
+		known = true
+	}
+	for _, tool := range cfg.Tools {
+		for profileName, profile := range tool.Profiles {
+			if profileName == oldName || (profile != nil && profile.Provider == oldName) {
+				known = true
+			}
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, oldName)
+	}
+	if _, ok := cfg.Providers[newName]; ok {
+		return nil, fmt.Errorf("provider '%s' already exists", newName)
+	}
+
+	plan := &RenamePlan{Kind: "provider", Old: oldName, New: newName}
+
+	if _, ok := cfg.Providers[oldName]; ok {
+		plan.add("providers."+oldName, oldName, newName, func(cfg *Config, state *State) {
+			cfg.Providers[newName] = cfg.Providers[oldName]
+			delete(cfg.Providers, oldName)
+		})
+	}
+
+	for _, keyName := range sortedKeys(cfg.Keys) {
+		if cfg.Keys[keyName] != nil && cfg.Keys[keyName].Provider == oldName {
+			plan.add(fmt.Sprintf("keys.%s.provider", keyName), oldName, newName, func(cfg *Config, state *State) {
+				cfg.Keys[keyName].Provider = newName
+			})
+		}
+	}
+
+	for _, toolName := range sortedTools(cfg.Tools) {
+		tool := cfg.Tools[toolName]
+		for _, profileName := range sortedProfiles(tool.Profiles) {
+			profile := tool.Profiles[profileName]
+
+			if profile != nil && profile.Provider == oldName {
+				plan.add(fmt.Sprintf("tools.%s.profiles.%s.provider", toolName, profileName), oldName, newName, func(cfg *Config, state *State) {
+					cfg.Tools[toolName].Profiles[profileName].Provider = newName
+				})
+			}
+
+			if profileName == oldName {
+				if _, exists := tool.Profiles[newName]; exists {
+					return nil, fmt.Errorf("profile '%s' already exists in tool '%s'", newName, toolName)
+				}
+				plan.References = append(plan.References, profileRenameRefs(tool, toolName, oldName, newName)...)
+			}
+		}
+	}
+
+	if cfg.Settings.DefaultProvider == oldName {
+		plan.add("settings.default_provider", oldName, newName, func(cfg *Config, state *State) {
+			cfg.Settings.DefaultProvider = newName
+		})
+	}
+
+	if state != nil {
+		if state.Current.Provider == oldName {
+			plan.add("state.current.provider", oldName, newName, func(cfg *Config, state *State) {
+				state.Current.Provider = newName
+			})
+		}
+		for _, toolName := range sortedToolStates(state) {
+			if state.Tools[toolName].Provider == oldName {
+				plan.add(fmt.Sprintf("state.tools.%s.provider", toolName), oldName, newName, func(cfg *Config, state *State) {
+					state.Tools[toolName].Provider = newName
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// PlanProfileRename plans renaming a tool profile and the field mappings that name it explicitly
+// Keys, settings.default_provider and state select profiles by provider name across all tools,
+// so references to the old name block the rename instead of being rewritten for one tool.
+func PlanProfileRename(cfg *Config, state *State, toolName, oldName, newName string) (*RenamePlan, error) {
+	if err := validateRename("profile", oldName, newName); err != nil {
+		return nil, err
+	}
+
+	tool, ok := cfg.GetTool(toolName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, toolName)
+	}
+	if _, ok := tool.Profiles[oldName]; !ok {
+		return nil, fmt.Errorf("profile '%s' not found in tool '%s'", oldName, toolName)
+	}
+	if _, ok := tool.Profiles[newName]; ok {
+		return nil, fmt.Errorf("profile '%s' already exists in tool '%s'", newName, toolName)
+	}
+
+	plan := &RenamePlan{Kind: "profile", Old: oldName, New: newName}
+	plan.References = profileRenameRefs(tool, toolName, oldName, newName)
+
+	block := func(location string) {
+		plan.Blockers = append(plan.Blockers, Reference{Location: location, Old: oldName})
+	}
+	for _, keyName := range sortedKeys(cfg.Keys) {
+		if cfg.Keys[keyName] != nil && cfg.Keys[keyName].Provider == oldName {
+			block(fmt.Sprintf("keys.%s.provider", keyName))
+		}
+	}
+	if cfg.Settings.DefaultProvider == oldName {
+		block("settings.default_provider")
+	}
+	if state != nil {
+		if state.Current.Provider == oldName {
+			block("state.current.provider")
+		}
+		for _, stateTool := range sortedToolStates(state) {
+			if state.Tools[stateTool].Provider == oldName {
+				block(fmt.Sprintf("state.tools.%s.provider", stateTool))
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// profileRenameRefs returns references for moving a profile and rewriting explicit profiles.<name>.* field paths
+func profileRenameRefs(tool *ToolConfig, toolName, oldName, newName string) []Reference {
+	var refs []Reference
+
+	refs = append(refs, Reference{
+		Location: fmt.Sprintf("tools.%s.profiles.%s", toolName, oldName),
+		Old:      oldName,
+		New:      newName,
+		apply: func(cfg *Config, state *State) {
+			profiles := cfg.Tools[toolName].Profiles
+			profiles[newName] = profiles[oldName]
+			delete(profiles, oldName)
+		},
+	})
+
+	oldPrefix := "profiles." + oldName + "."
+	newPrefix := "profiles." + newName + "."
+
+	mappingRefs := func(location string, mapping map[string]string, target func(cfg *Config) map[string]string) {
+		for _, envKey := range sortedStrings(mapping) {
+			fieldPath := mapping[envKey]
+			if !strings.HasPrefix(fieldPath, oldPrefix) {
+				continue
+			}
+			newPath := newPrefix + strings.TrimPrefix(fieldPath, oldPrefix)
+			refs = append(refs, Reference{
+				Location: fmt.Sprintf("%s.%s", location, envKey),
+				Old:      fieldPath,
+				New:      newPath,
+				apply: func(cfg *Config, state *State) {
+					target(cfg)[envKey] = newPath
+				},
+			})
+		}
+	}
+
+	mappingRefs(fmt.Sprintf("tools.%s.field_mapping", toolName), tool.FieldMapping, func(cfg *Config) map[string]string {
+		return cfg.Tools[toolName].FieldMapping
+	})
+
+	// Field mappings of the renamed profile move with it, so address them by their new location
+	if profile := tool.Profiles[oldName]; profile != nil {
+		mappingRefs(fmt.Sprintf("tools.%s.profiles.%s.field_mapping", toolName, newName), profile.FieldMapping, func(cfg *Config) map[string]string {
+			return cfg.Tools[toolName].Profiles[newName].FieldMapping
+		})
+	}
+
+	return refs
+}
+
+// validateRename checks that a rename has distinct, non-empty names
+func validateRename(kind, oldName, newName string) error {
+	if oldName == "" || newName == "" {
+		return fmt.Errorf("%s name cannot be empty", kind)
+	}
+	if oldName == newName {
+		return fmt.Errorf("%s '%s' already has that name", kind, oldName)
+	}
+	return nil
+}
+
+// sortedKeys returns key names in stable order
+func sortedKeys(keys map[string]*Key) []string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedTools returns tool names in stable order
+func sortedTools(tools map[string]*ToolConfig) []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedProfiles returns profile names in stable order
+func sortedProfiles(profiles map[string]*ToolProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedToolStates returns tool names with state in stable order
+func sortedToolStates(state *State) []string {
+	names := make([]string, 0, len(state.Tools))
+	for name, toolState := range state.Tools {
+		if toolState != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedStrings returns map keys in stable order
+func sortedStrings(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
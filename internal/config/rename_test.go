@@ -0,0 +1,120 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanProfileRenameBlockers(t *testing.T) {
+	cfg := &Config{
+		Settings: Settings{DefaultProvider: "glm"},
+		Keys: map[string]*Key{
+			"work": {Provider: "glm", Key: "sk-work"},
+			"home": {Provider: "kimi", Key: "sk-home"},
+		},
+		Tools: map[string]*ToolConfig{
+			"codex": {
+				Command: "codex",
+				Profiles: map[string]*ToolProfile{
+					"glm":  {Provider: "glm"},
+					"kimi": {Provider: "kimi"},
+				},
+			},
+		},
+	}
+	state := &State{Current: CurrentState{Provider: "glm"}}
+
+	plan, err := PlanProfileRename(cfg, state, "codex", "glm", "zhipu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blockers []string
+	for _, ref := range plan.Blockers {
+		blockers = append(blockers, ref.Location)
+	}
+	want := []string{"keys.work.provider", "settings.default_provider", "state.current.provider"}
+	if !reflect.DeepEqual(blockers, want) {
+		t.Errorf("Blockers = %v, want %v", blockers, want)
+	}
+
+	plan, err = PlanProfileRename(cfg, state, "codex", "kimi", "moonshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Blockers) != 1 || plan.Blockers[0].Location != "keys.home.provider" {
+		t.Errorf("Blockers = %v, want keys.home.provider", plan.Blockers)
+	}
+
+	cfg.Keys["home"].Provider = "glm"
+	plan, err = PlanProfileRename(cfg, state, "codex", "kimi", "moonshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Blockers) != 0 {
+		t.Errorf("Blockers = %v, want none", plan.Blockers)
+	}
+	plan.Apply(cfg, state)
+	if _, ok := cfg.Tools["codex"].Profiles["moonshot"]; !ok {
+		t.Error("profile was not renamed")
+	}
+}
+
+func TestPlanKeyRenameHeaders(t *testing.T) {
+	cfg := &Config{
+		Settings: Settings{DefaultKey: "a"},
+		Keys: map[string]*Key{
+			"a":  {Provider: "glm", Key: "sk-a"},
+			"ab": {Provider: "glm", Key: "sk-ab"},
+		},
+		Providers: map[string]*Provider{
+			"gateway": {ProviderWiring: ProviderWiring{Headers: map[string]string{"X-Token": "{keys.a}", "X-Other": "{keys.ab}"}}},
+		},
+		Tools: map[string]*ToolConfig{
+			"codex": {
+				Command: "codex",
+				Profiles: map[string]*ToolProfile{
+					"glm": {Provider: "glm", ProviderWiring: ProviderWiring{Headers: map[string]string{"X-Pair": "{keys.a}:{keys.a}"}}},
+				},
+			},
+		},
+	}
+	state := &State{Current: CurrentState{Key: "a"}, Tools: map[string]*ToolState{"codex": {Key: "a"}}}
+
+	plan, err := PlanKeyRename(cfg, state, "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var locations []string
+	for _, ref := range plan.References {
+		locations = append(locations, ref.Location)
+	}
+	want := []string{
+		"keys.a",
+		"providers.gateway.headers.X-Token",
+		"tools.codex.profiles.glm.headers.X-Pair",
+		"settings.default_key",
+		"state.current.key",
+		"state.tools.codex.key",
+	}
+	if !reflect.DeepEqual(locations, want) {
+		t.Errorf("References = %v, want %v", locations, want)
+	}
+
+	plan.Apply(cfg, state)
+	if _, ok := cfg.Keys["b"]; !ok || cfg.Keys["a"] != nil {
+		t.Errorf("keys = %v", cfg.Keys)
+	}
+	headers := cfg.Providers["gateway"].Headers
+	if headers["X-Token"] != "{keys.b}" || headers["X-Other"] != "{keys.ab}" {
+		t.Errorf("provider headers = %v", headers)
+	}
+	if got := cfg.Tools["codex"].Profiles["glm"].Headers["X-Pair"]; got != "{keys.b}:{keys.b}" {
+		t.Errorf("profile header = %q", got)
+	}
+	if cfg.Settings.DefaultKey != "b" || state.Current.Key != "b" || state.Tools["codex"].Key != "b" {
+		t.Errorf("settings/state not repointed: %q %q %q", cfg.Settings.DefaultKey, state.Current.Key, state.Tools["codex"].Key)
+	}
+	if dangling := BuildGraph(cfg, state).Dangling(cfg); len(dangling) != 0 {
+		t.Errorf("dangling references after rename: %v", dangling)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -68,11 +69,19 @@ func (r *Resolver) Resolve(toolName, keyName, profileName string) (*RuntimeConfi
 	}
 
 	// 8. Resolve model with inheritance
-	model, err := r.resolveModel(toolName, finalProfile, actualProvider)
-	if err != nil {
-		return nil, err
+	// Azure OpenAI keys name the deployment, which codex sends as the model
+	model := key.Deployment
+	if model == "" || r.config.ProviderKind(actualProvider) != ProviderKindAzure {
+		model, err = r.resolveModel(toolName, finalProfile, actualProvider)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	// Profiles may name a tier or alias (e.g. "balanced") instead of a model ID
+	modelAliases := r.resolveModelAliases(toolName, toolProfile, actualProvider)
+	model = lookupModel(modelAliases, model)
+
 	// 9. Resolve timeout with inheritance
 	timeout, err := r.resolveTimeout(toolName, finalProfile, actualProvider)
 	if err != nil {
@@ -82,7 +91,7 @@ func (r *Resolver) Resolve(toolName, keyName, profileName string) (*RuntimeConfi
 	// 10. Build environment variables
 	envVars := r.buildEnvVars(toolName, tool, toolProfile, key, baseURL, model, timeout, finalProfile)
 
-	return &RuntimeConfig{
+	runtime := &RuntimeConfig{
 		Tool:     toolName,
 		Key:      keyName,
 		Profile:  finalProfile,
@@ -92,7 +101,13 @@ func (r *Resolver) Resolve(toolName, keyName, profileName string) (*RuntimeConfi
 		Model:    model,
 		Timeout:  timeout,
 		EnvVars:  envVars,
-	}, nil
+		Models:   modelAliases,
+	}
+	if err := r.applyWiring(runtime, tool, toolProfile, key); err != nil {
+		return nil, fmt.Errorf("profile '%s': %w", finalProfile, err)
+	}
+
+	return runtime, nil
 }
 
 // resolveBaseURL resolves the base URL using four-tier priority:
@@ -202,9 +217,15 @@ func (r *Resolver) resolveTimeout(toolName, profileName, providerName string) (t
 func (r *Resolver) buildEnvVars(toolName string, tool *ToolConfig, toolProfile *ToolProfile, key *Key, baseURL, model string, timeout time.Duration, profileName string) map[string]string {
 	envVars := make(map[string]string)
 
+	// auth_scheme none keeps the key out of the environment entirely
+	sendKey := toolProfile == nil || r.resolveWiring(toolName, toolProfile, toolProfile.Provider).AuthScheme != AuthSchemeNone
+
 	// 1. Apply profile-specific field mapping first (highest priority)
 	if toolProfile != nil && toolProfile.FieldMapping != nil {
 		for envKey, fieldPath := range toolProfile.FieldMapping {
+			if !sendKey && fieldPath == keyFieldPath {
+				continue
+			}
 			// Resolve field path to actual value
 			value := r.resolveFieldPath(toolName, fieldPath, key, toolProfile, baseURL, model, timeout, profileName)
 			if value != "" {
@@ -220,6 +241,9 @@ func (r *Resolver) buildEnvVars(toolName string, tool *ToolConfig, toolProfile *
 			if _, exists := envVars[envKey]; exists {
 				continue
 			}
+			if !sendKey && fieldPath == keyFieldPath {
+				continue
+			}
 
 			// Resolve field path to actual value
 			value := r.resolveFieldPath(toolName, fieldPath, key, toolProfile, baseURL, model, timeout, profileName)
@@ -239,7 +263,7 @@ func (r *Resolver) buildEnvVars(toolName string, tool *ToolConfig, toolProfile *
 
 	// 3. Apply provider-specific API key environment variable (for tools like codex)
 	// This handles cases where different providers need different API key env var names
-	providerAPIKeyName := r.getProviderAPIKeyName(toolName, toolProfile.Provider)
+	providerAPIKeyName := r.resolveEnvKeyName(toolName, tool, toolProfile, key)
 	if providerAPIKeyName != "" {
 		// Skip if already set by field mapping
 		if _, exists := envVars[providerAPIKeyName]; !exists {
@@ -247,6 +271,13 @@ func (r *Resolver) buildEnvVars(toolName string, tool *ToolConfig, toolProfile *
 		}
 	}
 
+	// 3b. Apply structured cloud credentials (AWS for Bedrock, GCP for Vertex)
+	if key.Credentials != nil {
+		for envKey, value := range key.Credentials.Env() {
+			envVars[envKey] = value
+		}
+	}
+
 	// 4. Apply tool defaults environment variables
 	if tool.Defaults != nil && tool.Defaults.Env != nil {
 		for envKey, value := range tool.Defaults.Env {
@@ -308,7 +339,7 @@ func (r *Resolver) resolveFieldPath(toolName, fieldPath string, key *Key, toolPr
 				// Fallback to builtin provider default endpoint timeout
 				if defaultEndpoint, err := provider.GetDefaultEndpoint(key.Provider); err == nil {
 					// Try to get timeout from the tool being used
-					if toolCfg, ok := defaultEndpoint.Tools[toolName]; ok && toolCfg.Timeout > 0 {
+					if toolCfg, ok := defaultEndpoint.Tools[r.presetToolName(toolName)]; ok && toolCfg.Timeout > 0 {
 						return fmt.Sprintf("%d", toolCfg.Timeout)
 					}
 				}
@@ -320,26 +351,6 @@ func (r *Resolver) resolveFieldPath(toolName, fieldPath string, key *Key, toolPr
 	return ""
 }
 
-// getProviderAPIKeyName returns the provider-specific API key environment variable name
-func (r *Resolver) getProviderAPIKeyName(toolName, providerName string) string {
-	// Get provider info to check all endpoints
-	providerInfo, exists := provider.GetBuiltinProvider(providerName)
-	if !exists {
-		return ""
-	}
-
-	// Search through all endpoints for this tool
-	for _, endpoint := range providerInfo.Endpoints {
-		if toolCfg, ok := endpoint.Tools[toolName]; ok {
-			if toolCfg.EnvKeyName != "" {
-				return toolCfg.EnvKeyName
-			}
-		}
-	}
-
-	return ""
-}
-
 // getBuiltinBaseURL returns built-in base URL for a provider
 func (r *Resolver) getBuiltinBaseURL(toolName, providerName string) (string, error) {
 	// Get default endpoint for the provider
@@ -349,7 +360,7 @@ func (r *Resolver) getBuiltinBaseURL(toolName, providerName string) (string, err
 	}
 
 	// Try to get base URL from the current tool
-	if toolCfg, ok := defaultEndpoint.Tools[toolName]; ok {
+	if toolCfg, ok := defaultEndpoint.Tools[r.presetToolName(toolName)]; ok {
 		return toolCfg.BaseURL, nil
 	}
 
@@ -370,7 +381,7 @@ func (r *Resolver) getBuiltinModel(toolName, providerName string) (string, error
 	}
 
 	// Try to get model from the current tool
-	if toolCfg, ok := defaultEndpoint.Tools[toolName]; ok {
+	if toolCfg, ok := defaultEndpoint.Tools[r.presetToolName(toolName)]; ok {
 		return toolCfg.Model, nil
 	}
 
@@ -432,14 +443,21 @@ func (r *Resolver) ValidateKey(keyName string) error {
 		return fmt.Errorf("key '%s' not found", keyName)
 	}
 
-	if key.Key == "" {
-		return fmt.Errorf("key '%s' has empty value", keyName)
-	}
-
 	if key.Provider == "" {
 		return fmt.Errorf("key '%s' has no provider specified", keyName)
 	}
 
+	if key.Credentials != nil {
+		if err := key.Credentials.Validate(); err != nil {
+			return fmt.Errorf("key '%s': %w", keyName, err)
+		}
+		return nil
+	}
+
+	if key.Key == "" && r.config.ProviderRequiresKey(key.Provider) {
+		return fmt.Errorf("key '%s' has empty value", keyName)
+	}
+
 	return nil
 }
 
@@ -471,6 +489,59 @@ func (r *Resolver) UpdateRuntimeEnvVars(runtime *RuntimeConfig) error {
 	return nil
 }
 
+// ApplyRegion points a runtime at the given region's host of its builtin provider
+// An empty region uses settings.region. Custom base URLs and single-region
+// providers are left unchanged. Returns true when the base URL changed.
+// Call UpdateRuntimeEnvVars afterwards to refresh the environment.
+func (r *Resolver) ApplyRegion(runtime *RuntimeConfig, region string) (bool, error) {
+	if region == "" {
+		region = r.config.Settings.Region
+	}
+	if region == "" {
+		return false, nil
+	}
+	if !provider.IsKnownRegion(region) {
+		return false, fmt.Errorf("unknown region '%s' (supported: %s)", region, strings.Join(provider.KnownRegions, ", "))
+	}
+
+	current, urls, ok := provider.RegionalBaseURLs(runtime.Provider, r.presetToolName(r.config.ResolveAlias(runtime.Tool)), runtime.BaseURL)
+	if !ok || current == region {
+		return false, nil
+	}
+	baseURL, ok := urls[region]
+	if !ok {
+		return false, fmt.Errorf("provider '%s' has no '%s' endpoint", runtime.Provider, region)
+	}
+
+	runtime.BaseURL = baseURL
+	return true, nil
+}
+
+// KeyRegion returns the region of the builtin host a key is used against, after settings.region
+// The first tool whose profile resolves for the key decides. It is empty for custom
+// base URLs and single-region providers.
+func (r *Resolver) KeyRegion(keyName string) string {
+	toolNames := make([]string, 0, len(r.config.Tools))
+	for name := range r.config.Tools {
+		toolNames = append(toolNames, name)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		runtime, err := r.Resolve(toolName, keyName, "")
+		if err != nil {
+			continue
+		}
+		if _, err := r.ApplyRegion(runtime, ""); err != nil {
+			continue
+		}
+		if current, _, ok := provider.RegionalBaseURLs(runtime.Provider, r.presetToolName(toolName), runtime.BaseURL); ok {
+			return current
+		}
+	}
+	return ""
+}
+
 // GetConfig returns the configuration associated with this resolver
 func (r *Resolver) GetConfig() *Config {
 	return r.config
@@ -490,13 +561,17 @@ func (r *Resolver) ValidateTool(toolName string) error {
 	}
 
 	// If profiles is empty, try to load from builtin defaults
-	if len(tool.Profiles) == 0 {
+	// Tools declaring profiles_from borrow their source tool's profiles instead
+	if len(tool.Profiles) == 0 && tool.ProfilesFrom == "" {
 		// Try to load default tool configuration
 		defaultTools, err := r.loadDefaultToolConfig()
 		if err == nil {
 			if defaultTool, hasDefault := defaultTools[toolName]; hasDefault && len(defaultTool.Profiles) > 0 {
 				// Use builtin profiles as fallback
 				tool.Profiles = defaultTool.Profiles
+			} else if hasDefault {
+				// Shipped without profiles, e.g. gemini-cli, which no builtin provider serves
+				return fmt.Errorf("tool '%s' has no profiles configured. Add a profile under tools.%s.profiles for a provider it supports", toolName, toolName)
 			} else {
 				return fmt.Errorf("tool '%s' has no profiles configured. Run 'aim config init' to create default configuration", toolName)
 			}
@@ -42,16 +42,8 @@ type StateManager struct {
 
 // NewStateManager creates a new state manager
 func NewStateManager() *StateManager {
-	homeDir, _ := os.UserHomeDir()
-	statePath := filepath.Join(homeDir, ".aim", "config", "state.yaml")
-
-	// Check if AIM_HOME is set (for testing environments)
-	if aimHome := os.Getenv("AIM_HOME"); aimHome != "" {
-		statePath = filepath.Join(aimHome, "config", "state.yaml")
-	}
-
 	return &StateManager{
-		statePath: statePath,
+		statePath: filepath.Join(GetAIMHome(), "config", "state.yaml"),
 	}
 }
 
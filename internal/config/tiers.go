@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// resolveModelAliases merges model tiers and aliases from lowest to highest priority:
+// builtin endpoint preset, global provider, tool profile. Keys are canonical aliases.
+func (r *Resolver) resolveModelAliases(toolName string, toolProfile *ToolProfile, providerName string) map[string]string {
+	aliases := make(map[string]string)
+	add := func(models map[string]string) {
+		for alias, model := range models {
+			if model != "" {
+				aliases[provider.CanonicalModelAlias(alias)] = model
+			}
+		}
+	}
+
+	if preset, ok := provider.ResolveBuiltinEndpoint(providerName); ok {
+		if toolCfg, ok := preset.Tools[r.presetToolName(toolName)]; ok {
+			add(toolCfg.Models)
+		}
+	}
+	if globalProvider, ok := r.config.GetProvider(providerName); ok && globalProvider != nil {
+		add(globalProvider.Models)
+	}
+	if toolProfile != nil {
+		add(toolProfile.Models)
+	}
+
+	if len(aliases) == 0 {
+		return nil
+	}
+	return aliases
+}
+
+// ModelAliases returns the resolved model tiers and aliases of a tool profile
+func (r *Resolver) ModelAliases(toolName, profileName string) (map[string]string, error) {
+	toolProfile, ok := r.config.GetToolProfile(toolName, profileName)
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' not configured for tool '%s'", profileName, toolName)
+	}
+	return r.resolveModelAliases(toolName, toolProfile, toolProfile.Provider), nil
+}
+
+// lookupModel translates a tier or alias to a model ID; other names are returned unchanged
+func lookupModel(aliases map[string]string, name string) string {
+	if model, ok := aliases[provider.CanonicalModelAlias(name)]; ok {
+		return model
+	}
+	return name
+}
+
+// LookupModel translates a tier (fast, balanced, strong, or haiku, sonnet, opus) or alias to the runtime's model ID
+func (rc *RuntimeConfig) LookupModel(name string) string {
+	return lookupModel(rc.Models, name)
+}
+
+// ModelAliasNames returns alias names with the tiers first, in tier order
+func ModelAliasNames(aliases map[string]string) []string {
+	var names, others []string
+	for _, tier := range provider.ModelTiers {
+		if _, ok := aliases[tier]; ok {
+			names = append(names, tier)
+		}
+	}
+	for alias := range aliases {
+		if !provider.IsModelTier(alias) {
+			others = append(others, alias)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
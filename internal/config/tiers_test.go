@@ -0,0 +1,77 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModelAliases(t *testing.T) {
+	cfg := &Config{
+		Keys: map[string]*Key{"work": {Provider: "glm", Key: "sk-work"}},
+		Providers: map[string]*Provider{
+			"glm": {BaseURL: "https://open.bigmodel.cn/api/paas/v4", Model: "glm-4.6", Models: map[string]string{
+				"balanced": "glm-provider",
+				"Think":    "glm-think",
+			}},
+		},
+		Tools: map[string]*ToolConfig{
+			"codex": {Command: "codex", Profiles: map[string]*ToolProfile{
+				"glm":   {Provider: "glm", Models: map[string]string{"opus": "glm-profile", "think": ""}},
+				"plain": {Provider: "proxy", BaseURL: "https://proxy.example.com/v1", Model: "m"},
+			}},
+		},
+	}
+	resolver := NewResolver(cfg)
+
+	// Builtin preset < global provider < tool profile; synonyms and case fold to one alias, empty values don't override
+	want := map[string]string{
+		"fast":     "glm-4.5-air",
+		"balanced": "glm-provider",
+		"strong":   "glm-profile",
+		"think":    "glm-think",
+	}
+	got, err := resolver.ModelAliases("codex", "glm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ModelAliases() = %v, want %v", got, want)
+	}
+
+	runtime, err := resolver.Resolve("codex", "work", "glm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(runtime.Models, want) {
+		t.Errorf("runtime.Models = %v, want %v", runtime.Models, want)
+	}
+
+	lookups := []struct {
+		name string
+		want string
+	}{
+		{"fast", "glm-4.5-air"},
+		{"haiku", "glm-4.5-air"},
+		{"Sonnet", "glm-provider"},
+		{" OPUS ", "glm-profile"},
+		{"think", "glm-think"},
+		{"glm-4.5v", "glm-4.5v"}, // Model IDs pass through unchanged
+	}
+	for _, tt := range lookups {
+		if got := runtime.LookupModel(tt.name); got != tt.want {
+			t.Errorf("LookupModel(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := ModelAliasNames(want); !reflect.DeepEqual(got, []string{"fast", "balanced", "strong", "think"}) {
+		t.Errorf("ModelAliasNames() = %v, want tiers first", got)
+	}
+
+	// Providers without any tiers resolve to no aliases
+	if got, err := resolver.ModelAliases("codex", "plain"); err != nil || got != nil {
+		t.Errorf("ModelAliases(plain) = %v, %v, want none", got, err)
+	}
+	if _, err := resolver.ModelAliases("codex", "missing"); err == nil {
+		t.Error("ModelAliases(missing) succeeded, want an error")
+	}
+}
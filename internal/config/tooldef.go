@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Install file formats for declared tools
+const (
+	InstallFormatJSON = "json"
+	InstallFormatYAML = "yaml"
+	InstallFormatTOML = "toml"
+)
+
+// ToolInstall describes the config file 'aim setup install' writes for a declared tool
+type ToolInstall struct {
+	Path   string            `yaml:"path"`             // File to write, ~ expands to the home directory
+	Format string            `yaml:"format,omitempty"` // json, yaml or toml (default: from the file extension)
+	Values map[string]string `yaml:"values"`           // Dotted keys to value templates, e.g. model: "{model}"
+}
+
+// ToolPreparer names an external preparer executable
+// aim sends it the resolved runtime as JSON on stdin and reads args, env, files and cleanup from stdout.
+type ToolPreparer struct {
+	Command string   `yaml:"command"`           // Executable, looked up in PATH
+	Args    []string `yaml:"args,omitempty"`    // Extra arguments for the executable
+	Timeout int      `yaml:"timeout,omitempty"` // Milliseconds (default: 10000)
+}
+
+// InstallFormat returns the declared format, falling back to the path's extension
+func (t *ToolInstall) InstallFormat() string {
+	if t.Format != "" {
+		return t.Format
+	}
+	switch {
+	case strings.HasSuffix(t.Path, ".json"):
+		return InstallFormatJSON
+	case strings.HasSuffix(t.Path, ".toml"):
+		return InstallFormatTOML
+	}
+	return InstallFormatYAML
+}
+
+// validateDefinition checks the declarative fields of a tool
+func (t *ToolConfig) validateDefinition(name string, cfg *Config) error {
+	switch t.Protocol {
+	case "", "anthropic", "openai":
+	default:
+		return fmt.Errorf("tool '%s': unknown protocol '%s' (supported: anthropic, openai)", name, t.Protocol)
+	}
+	if t.ProfilesFrom != "" {
+		if _, ok := cfg.Tools[t.ProfilesFrom]; !ok {
+			return fmt.Errorf("tool '%s': profiles_from names unknown tool '%s'", name, t.ProfilesFrom)
+		}
+	}
+	for _, alias := range t.Aliases {
+		if other, ok := cfg.Tools[alias]; ok && other != t {
+			return fmt.Errorf("tool '%s': alias '%s' is already a tool name", name, alias)
+		}
+	}
+	for _, arg := range t.Args {
+		if strings.Contains(arg, "{key}") {
+			return fmt.Errorf("tool '%s': argument '%s' uses {key}; command lines are visible to other users, pass the key through an env variable instead", name, arg)
+		}
+	}
+	if t.Preparer != nil {
+		if t.Preparer.Command == "" {
+			return fmt.Errorf("tool '%s': preparer.command is required", name)
+		}
+		if t.Preparer.Timeout < 0 {
+			return fmt.Errorf("tool '%s': preparer.timeout must not be negative", name)
+		}
+	}
+	if t.Install != nil {
+		if t.Install.Path == "" {
+			return fmt.Errorf("tool '%s': install.path is required", name)
+		}
+		switch t.Install.InstallFormat() {
+		case InstallFormatJSON, InstallFormatYAML, InstallFormatTOML:
+		default:
+			return fmt.Errorf("tool '%s': unknown install.format '%s' (supported: json, yaml, toml)", name, t.Install.Format)
+		}
+	}
+	return nil
+}
+
+// presetToolName returns the tool whose builtin provider presets apply to toolName:
+// its profiles_from source when set, so declared tools reuse codex or claude-code endpoints
+func (r *Resolver) presetToolName(toolName string) string {
+	if tool, ok := r.config.GetTool(toolName); ok && tool.ProfilesFrom != "" {
+		return tool.ProfilesFrom
+	}
+	return toolName
+}
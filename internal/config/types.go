@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/fakecore/aim/configs"
+	"github.com/fakecore/aim/internal/provider"
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,6 +26,10 @@ type Settings struct {
 	DefaultKey      string `yaml:"default_key,omitempty"`
 	Timeout         int    `yaml:"timeout,omitempty"`
 	Language        string `yaml:"language,omitempty"`
+	// LowBalanceThreshold makes 'aim run' warn when the key's remaining credit is below it (0 disables)
+	LowBalanceThreshold float64 `yaml:"low_balance_threshold,omitempty"`
+	// Region selects the host for builtin providers with mainland China and international endpoints (cn, intl)
+	Region string `yaml:"region,omitempty"`
 }
 
 // Key represents an API key configuration
@@ -31,14 +37,33 @@ type Key struct {
 	Provider    string `yaml:"provider"`
 	Key         string `yaml:"key"`
 	Description string `yaml:"description,omitempty"`
+	// Credentials replaces Key for cloud providers (Bedrock, Vertex) that need several fields
+	Credentials *Credentials `yaml:"credentials,omitempty"`
+	// Azure OpenAI: the deployment used as the model, and the api-version query parameter
+	Deployment string `yaml:"deployment,omitempty"`
+	APIVersion string `yaml:"api_version,omitempty"`
 }
 
 // Provider represents a global provider configuration
 type Provider struct {
+	Kind    string            `yaml:"kind,omitempty"` // Empty for OpenAI/Anthropic-compatible endpoints, or "azure"
 	BaseURL string            `yaml:"base_url,omitempty"`
 	Model   string            `yaml:"model,omitempty"`
 	Timeout int               `yaml:"timeout,omitempty"`
-	Models  map[string]string `yaml:"models,omitempty"`
+	Models  map[string]string `yaml:"models,omitempty"` // Model tiers (fast, balanced, strong) and aliases -> model IDs
+
+	ProviderWiring `yaml:",inline"`
+}
+
+// ProviderWiring describes how tools like codex talk to a provider beyond base URL and model
+// Profiles override the global provider, which overrides the builtin endpoint preset.
+type ProviderWiring struct {
+	DisplayName string            `yaml:"display_name,omitempty"` // Name shown by the tool, defaults to the provider name
+	EnvKey      string            `yaml:"env_key,omitempty"`      // Environment variable that carries the API key
+	WireAPI     string            `yaml:"wire_api,omitempty"`     // Codex wire protocol: chat or responses
+	QueryParams map[string]string `yaml:"query_params,omitempty"` // Extra query parameters on every request
+	Headers     map[string]string `yaml:"headers,omitempty"`      // Extra HTTP headers on every request, values may reference {key}, {keys.<name>} or ${VAR}
+	AuthScheme  string            `yaml:"auth_scheme,omitempty"`  // How the key is sent: bearer (default), x-api-key or none
 }
 
 // ToolProfile represents a tool-specific provider configuration
@@ -49,6 +74,9 @@ type ToolProfile struct {
 	Timeout      int               `yaml:"timeout,omitempty"`
 	Env          map[string]string `yaml:"env,omitempty"`
 	FieldMapping map[string]string `yaml:"field_mapping,omitempty"`
+	Models       map[string]string `yaml:"models,omitempty"` // Overrides the provider's model tiers and aliases
+
+	ProviderWiring `yaml:",inline"`
 }
 
 // ToolDefaults represents tool-level default configuration
@@ -61,6 +89,12 @@ type ToolDefaults struct {
 type ToolConfig struct {
 	Command      string                  `yaml:"command"`
 	Enabled      bool                    `yaml:"enabled,omitempty"`
+	Aliases      []string                `yaml:"aliases,omitempty"`       // Other names the tool runs under
+	Protocol     string                  `yaml:"protocol,omitempty"`      // API the tool speaks: anthropic or openai
+	ProfilesFrom string                  `yaml:"profiles_from,omitempty"` // Tool whose profiles and provider presets fill in missing profiles
+	Args         []string                `yaml:"args,omitempty"`          // Argument templates, e.g. "--model {model}"
+	Install      *ToolInstall            `yaml:"install,omitempty"`       // Config file written by 'aim setup install'
+	Preparer     *ToolPreparer           `yaml:"preparer,omitempty"`      // External preparer run before the tool
 	Defaults     *ToolDefaults           `yaml:"defaults,omitempty"`
 	FieldMapping map[string]string       `yaml:"field_mapping,omitempty"`
 	Profiles     map[string]*ToolProfile `yaml:"profiles"`
@@ -80,6 +114,18 @@ type RuntimeConfig struct {
 	ModelOverride bool
 	Timeout       time.Duration
 	EnvVars       map[string]string
+	// Provider wiring beyond base URL and key, see ProviderWiring
+	ProviderKind   string
+	DisplayName    string
+	EnvKeyName     string            // Environment variable carrying the API key, empty for keyless providers
+	WireAPI        string            // Codex wire protocol: "chat" or "responses"
+	QueryParams    map[string]string // Extra query parameters on every request
+	AuthScheme     string            // How the key is sent: bearer, x-api-key or none
+	Headers        map[string]string // Extra HTTP headers on every request, with references resolved
+	SecretHeaders  map[string]bool   // Headers whose values came from a key or environment reference
+	EnvHTTPHeaders map[string]string // HTTP header name -> environment variable holding its value
+	// Models maps tiers and aliases to model IDs, keyed by canonical alias
+	Models map[string]string
 }
 
 // DefaultConfig returns the default v1.0 configuration
@@ -113,13 +159,52 @@ func (c *Config) Validate() error {
 	// For v1.0, we don't require default settings to be set
 	// as they can be provided via command line arguments
 
+	for name, p := range c.Providers {
+		if p == nil {
+			continue
+		}
+		if err := ValidateProviderKind(p.Kind); err != nil {
+			return fmt.Errorf("provider '%s': %w", name, err)
+		}
+		if err := p.ProviderWiring.validate(); err != nil {
+			return fmt.Errorf("provider '%s': %w", name, err)
+		}
+	}
+	for toolName, tool := range c.Tools {
+		if tool == nil {
+			continue
+		}
+		if err := tool.validateDefinition(toolName, c); err != nil {
+			return err
+		}
+		for profileName, profile := range tool.Profiles {
+			if profile == nil {
+				continue
+			}
+			if err := profile.ProviderWiring.validate(); err != nil {
+				return fmt.Errorf("tool '%s' profile '%s': %w", toolName, profileName, err)
+			}
+		}
+	}
+
 	return nil
 }
 
-// ResolveAlias resolves a tool alias to its actual name
-// TODO: Re-enable alias functionality when needed
+// ResolveAlias resolves a tool alias declared in a tool's aliases to the tool name
 func (c *Config) ResolveAlias(name string) string {
-	// Temporarily disable alias functionality - return name as-is
+	if _, ok := c.Tools[name]; ok {
+		return name
+	}
+	for toolName, tool := range c.Tools {
+		if tool == nil {
+			continue
+		}
+		for _, alias := range tool.Aliases {
+			if alias == name {
+				return toolName
+			}
+		}
+	}
 	return name
 }
 
@@ -141,6 +226,18 @@ func (c *Config) GetProvider(name string) (*Provider, bool) {
 	return provider, ok
 }
 
+// ProviderRequiresKey reports whether keys for a provider need a value
+// Builtin local servers and providers whose base URL points at this machine accept empty keys.
+func (c *Config) ProviderRequiresKey(name string) bool {
+	if provider.IsLocalProvider(name) {
+		return false
+	}
+	if p, ok := c.GetProvider(name); ok && p != nil && provider.IsLoopbackURL(p.BaseURL) {
+		return false
+	}
+	return true
+}
+
 // GetToolProfile retrieves profile-specific configuration for a tool
 func (c *Config) GetToolProfile(toolName, profileName string) (*ToolProfile, bool) {
 	tool, ok := c.GetTool(toolName)
@@ -149,6 +246,11 @@ func (c *Config) GetToolProfile(toolName, profileName string) (*ToolProfile, boo
 	}
 
 	profile, ok := tool.Profiles[profileName]
+	if !ok && tool.ProfilesFrom != "" && tool.ProfilesFrom != toolName {
+		if source, exists := c.GetTool(tool.ProfilesFrom); exists {
+			profile, ok = source.Profiles[profileName]
+		}
+	}
 	return profile, ok
 }
 
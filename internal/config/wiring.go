@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// Codex wire protocols
+const (
+	WireAPIChat      = "chat"
+	WireAPIResponses = "responses"
+)
+
+// ValidateWireAPI checks a wire_api value
+func ValidateWireAPI(wireAPI string) error {
+	switch wireAPI {
+	case "", WireAPIChat, WireAPIResponses:
+		return nil
+	}
+	return fmt.Errorf("unknown wire_api '%s' (supported: %s, %s)", wireAPI, WireAPIChat, WireAPIResponses)
+}
+
+// overlay returns w with the non-empty fields of top applied; maps are merged per entry
+func (w ProviderWiring) overlay(top ProviderWiring) ProviderWiring {
+	if top.DisplayName != "" {
+		w.DisplayName = top.DisplayName
+	}
+	if top.EnvKey != "" {
+		w.EnvKey = top.EnvKey
+	}
+	if top.WireAPI != "" {
+		w.WireAPI = top.WireAPI
+	}
+	if top.AuthScheme != "" {
+		w.AuthScheme = top.AuthScheme
+	}
+	w.QueryParams = overlayMap(w.QueryParams, top.QueryParams)
+	w.Headers = overlayMap(w.Headers, top.Headers)
+	return w
+}
+
+// validate checks the enumerated wiring fields
+func (w ProviderWiring) validate() error {
+	if err := ValidateWireAPI(w.WireAPI); err != nil {
+		return err
+	}
+	return ValidateAuthScheme(w.AuthScheme)
+}
+
+// overlayMap returns a new map with top's entries over base's
+func overlayMap(base, top map[string]string) map[string]string {
+	if len(base) == 0 && len(top) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(top))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range top {
+		merged[k] = v
+	}
+	return merged
+}
+
+// resolveWiring merges provider wiring from lowest to highest priority:
+// builtin endpoint preset, provider kind defaults, global provider, tool profile
+func (r *Resolver) resolveWiring(toolName string, toolProfile *ToolProfile, providerName string) ProviderWiring {
+	var w ProviderWiring
+
+	if preset, ok := provider.ResolveBuiltinEndpoint(providerName); ok {
+		if toolCfg, ok := preset.Tools[r.presetToolName(toolName)]; ok {
+			w = w.overlay(ProviderWiring{
+				DisplayName: toolCfg.DisplayName,
+				EnvKey:      toolCfg.EnvKeyName,
+				WireAPI:     toolCfg.WireAPI,
+				QueryParams: toolCfg.QueryParams,
+				Headers:     toolCfg.Headers,
+			})
+		}
+	}
+
+	w = w.overlay(kindWiring(r.config.ProviderKind(providerName)))
+
+	if globalProvider, ok := r.config.GetProvider(providerName); ok && globalProvider != nil {
+		w = w.overlay(globalProvider.ProviderWiring)
+	}
+	if toolProfile != nil {
+		w = w.overlay(toolProfile.ProviderWiring)
+	}
+
+	return w
+}
+
+// resolveEnvKeyName returns the environment variable that carries the key for tools without a tool-level key mapping
+// Explicit env_key settings win, then a profile field mapping of the key, then <PROVIDER>_API_KEY.
+// Keyless keys (local servers), auth_scheme none and tools that already map the key get none.
+func (r *Resolver) resolveEnvKeyName(toolName string, tool *ToolConfig, toolProfile *ToolProfile, key *Key) string {
+	if key.Key == "" {
+		return ""
+	}
+	w := r.resolveWiring(toolName, toolProfile, toolProfile.Provider)
+	if w.AuthScheme == AuthSchemeNone {
+		return ""
+	}
+	if name := w.EnvKey; name != "" {
+		return name
+	}
+	for envKey, fieldPath := range toolProfile.FieldMapping {
+		if fieldPath == keyFieldPath {
+			return envKey
+		}
+	}
+	for _, fieldPath := range tool.FieldMapping {
+		if fieldPath == keyFieldPath {
+			return ""
+		}
+	}
+	return DefaultEnvKeyName(toolProfile.Provider)
+}
+
+// DefaultEnvKeyName derives the API key variable for a provider, e.g. "my-proxy" -> "MY_PROXY_API_KEY"
+func DefaultEnvKeyName(providerName string) string {
+	return envName(providerName) + "_API_KEY"
+}
+
+// HeaderEnvName derives the variable that carries a secret header value, e.g. "Helicone-Auth" -> "AIM_HEADER_HELICONE_AUTH"
+func HeaderEnvName(header string) string {
+	return "AIM_HEADER_" + envName(header)
+}
+
+// KeyEnvName scopes a variable to one key, so several keys can be exported side by side,
+// e.g. ("work", "DEEPSEEK_API_KEY") -> "AIM_WORK_DEEPSEEK_API_KEY"
+func KeyEnvName(keyName, name string) string {
+	return "AIM_" + envName(keyName) + "_" + strings.TrimPrefix(name, "AIM_")
+}
+
+// envName uppercases s and replaces everything but letters and digits with underscores
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// applyWiring sets the resolved provider wiring on a runtime
+// Keys may pin the Azure OpenAI api-version, and Azure sends the key in an api-key header.
+func (r *Resolver) applyWiring(runtime *RuntimeConfig, tool *ToolConfig, toolProfile *ToolProfile, key *Key) error {
+	w := r.resolveWiring(runtime.Tool, toolProfile, runtime.Provider)
+
+	runtime.ProviderKind = r.config.ProviderKind(runtime.Provider)
+	runtime.DisplayName = w.DisplayName
+	if runtime.DisplayName == "" {
+		runtime.DisplayName = runtime.Provider
+	}
+	runtime.EnvKeyName = r.resolveEnvKeyName(runtime.Tool, tool, toolProfile, key)
+	runtime.WireAPI = w.WireAPI
+	runtime.QueryParams = w.QueryParams
+	runtime.AuthScheme = w.AuthScheme
+	if runtime.AuthScheme == "" {
+		runtime.AuthScheme = AuthSchemeBearer
+	}
+
+	headers, secretHeaders, err := r.resolveHeaders(w.Headers, key)
+	if err != nil {
+		return err
+	}
+	runtime.Headers = headers
+	runtime.SecretHeaders = secretHeaders
+
+	if runtime.ProviderKind == ProviderKindAzure {
+		if key.APIVersion != "" {
+			runtime.QueryParams = overlayMap(runtime.QueryParams, map[string]string{"api-version": key.APIVersion})
+		}
+		if runtime.EnvKeyName != "" {
+			runtime.EnvHTTPHeaders = map[string]string{azureKeyHeader: runtime.EnvKeyName}
+		}
+	} else if runtime.AuthScheme == AuthSchemeXAPIKey && runtime.EnvKeyName != "" {
+		runtime.EnvHTTPHeaders = map[string]string{"x-api-key": runtime.EnvKeyName}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import "time"
 const (
 	ConfigFileMode = 0644
 	ConfigDirMode  = 0755
+	SecretFileMode = 0600 // Installed config files that hold an API key
 )
 
 // Timeout constants
@@ -13,10 +14,23 @@ const (
 	DefaultTimeout    = 60 * time.Second
 	GLMTimeout        = 50 * time.Minute // 3,000,000ms
 	GLMCodingTimeout  = 5 * time.Minute  // 300,000ms
+	LocalTimeout      = 10 * time.Minute // 600,000ms, local models are slow on laptops
 )
 
 // Timeout in milliseconds for configuration
 const (
 	DefaultTimeoutMS = 60000
 	GLMTimeoutMS     = 300000
-)
\ No newline at end of file
+)
+
+// Audit log limits
+const (
+	AuditLogMaxSize = 5 << 20 // Rotate the audit log once it exceeds 5 MiB
+	AuditFileMode   = 0600
+)
+
+// Model discovery cache
+const (
+	ModelsCacheTTL  = 24 * time.Hour // Refetch provider model lists after a day
+	BalanceCacheTTL = time.Hour      // Requery key balances for the 'aim run' low-balance warning after an hour
+)
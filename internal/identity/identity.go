@@ -0,0 +1,144 @@
+package identity
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+const (
+	// PublicKeyPrefix prefixes every encoded recipient public key
+	PublicKeyPrefix = "aim1"
+
+	// SecretKeyPrefix prefixes every encoded identity secret key
+	SecretKeyPrefix = "AIM-SECRET-KEY-1"
+
+	// identityFileMode restricts the identity file to the owner
+	identityFileMode = 0600
+)
+
+// encoding is lowercase-friendly base32 without padding (age-style keys)
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Identity represents a user's X25519 keypair
+type Identity struct {
+	privateKey *ecdh.PrivateKey
+}
+
+// Generate creates a new random identity
+func Generate() (*Identity, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate X25519 key: %w", err)
+	}
+
+	return &Identity{privateKey: privateKey}, nil
+}
+
+// ParseIdentity parses an encoded secret key (AIM-SECRET-KEY-1...)
+func ParseIdentity(encoded string) (*Identity, error) {
+	encoded = strings.TrimSpace(encoded)
+	if !strings.HasPrefix(encoded, SecretKeyPrefix) {
+		return nil, fmt.Errorf("invalid identity: missing %s prefix", SecretKeyPrefix)
+	}
+
+	raw, err := encoding.DecodeString(strings.ToUpper(strings.TrimPrefix(encoded, SecretKeyPrefix)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity encoding: %w", err)
+	}
+
+	privateKey, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity: %w", err)
+	}
+
+	return &Identity{privateKey: privateKey}, nil
+}
+
+// ParseRecipient parses an encoded recipient public key (aim1...)
+func ParseRecipient(encoded string) (*ecdh.PublicKey, error) {
+	encoded = strings.TrimSpace(encoded)
+	if !strings.HasPrefix(encoded, PublicKeyPrefix) {
+		return nil, fmt.Errorf("invalid recipient: missing %s prefix", PublicKeyPrefix)
+	}
+
+	raw, err := encoding.DecodeString(strings.ToUpper(strings.TrimPrefix(encoded, PublicKeyPrefix)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient encoding: %w", err)
+	}
+
+	publicKey, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// EncodePublicKey encodes a public key as a shareable recipient string
+func EncodePublicKey(publicKey *ecdh.PublicKey) string {
+	return PublicKeyPrefix + strings.ToLower(encoding.EncodeToString(publicKey.Bytes()))
+}
+
+// PublicKey returns the identity's public key
+func (id *Identity) PublicKey() *ecdh.PublicKey {
+	return id.privateKey.PublicKey()
+}
+
+// Recipient returns the encoded public key to hand out to teammates
+func (id *Identity) Recipient() string {
+	return EncodePublicKey(id.PublicKey())
+}
+
+// String returns the encoded secret key
+func (id *Identity) String() string {
+	return SecretKeyPrefix + encoding.EncodeToString(id.privateKey.Bytes())
+}
+
+// DefaultPath returns the default identity file path (~/.aim/identity)
+func DefaultPath() string {
+	return filepath.Join(config.GetAIMHome(), "identity")
+}
+
+// Load reads an identity from a file
+func Load(path string) (*Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip comment lines so the file can carry the public key for reference
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return ParseIdentity(line)
+	}
+
+	return nil, fmt.Errorf("no identity found in %s", path)
+}
+
+// Save writes an identity to a file readable only by the owner
+func Save(path string, id *Identity) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create identity directory: %w", err)
+	}
+
+	content := fmt.Sprintf("# public key: %s\n%s\n", id.Recipient(), id.String())
+	if err := os.WriteFile(path, []byte(content), identityFileMode); err != nil {
+		return fmt.Errorf("failed to write identity file: %w", err)
+	}
+	// WriteFile keeps the mode of a file it overwrites, e.g. with 'identity init --force'
+	if err := os.Chmod(path, identityFileMode); err != nil {
+		return fmt.Errorf("failed to restrict identity file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package identity
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	id, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := &SharedKey{Name: "work", Provider: "deepseek", Key: "sk-secret", Description: "team key"}
+
+	env, err := Seal(payload, id.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := env.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseEnvelope(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Open(parsed, id)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if *got != *payload {
+		t.Errorf("Open = %+v, want %+v", got, payload)
+	}
+}
+
+func TestSealOpenCredentials(t *testing.T) {
+	id, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := &SharedKey{Name: "bedrock", Provider: "bedrock", Credentials: &config.Credentials{
+		Type:            config.CredentialTypeAWS,
+		Region:          "us-east-1",
+		AccessKeyID:     "AKIA",
+		SecretAccessKey: "secret",
+	}}
+
+	env, err := Seal(payload, id.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Open(env, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, payload) {
+		t.Errorf("Open = %+v, want %+v", got, payload)
+	}
+}
+
+func TestOpenWrongRecipient(t *testing.T) {
+	alice, _ := Generate()
+	bob, _ := Generate()
+
+	env, err := Seal(&SharedKey{Name: "work", Key: "sk-secret"}, alice.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(env, bob); !errors.Is(err, ErrNotRecipient) {
+		t.Errorf("Open with another identity: err = %v, want ErrNotRecipient", err)
+	}
+
+	// Relabelling the envelope for bob must not let him decrypt it
+	env.Recipient = bob.Recipient()
+	if _, err := Open(env, bob); err == nil {
+		t.Error("Open of an envelope relabelled for another identity succeeded")
+	}
+}
+
+func TestOpenTamperedEnvelope(t *testing.T) {
+	id, _ := Generate()
+	other, _ := Generate()
+
+	tests := []struct {
+		name   string
+		tamper func(env *Envelope)
+	}{
+		{"ciphertext", func(env *Envelope) { env.Ciphertext[0] ^= 1 }},
+		{"nonce", func(env *Envelope) { env.Nonce[0] ^= 1 }},
+		{"short nonce", func(env *Envelope) { env.Nonce = env.Nonce[:4] }},
+		{"ephemeral", func(env *Envelope) { env.Ephemeral = other.PublicKey().Bytes() }},
+		{"version", func(env *Envelope) { env.Version = EnvelopeVersion + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := Seal(&SharedKey{Name: "work", Key: "sk-secret"}, id.PublicKey())
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(env)
+			if _, err := Open(env, id); err == nil {
+				t.Error("Open of a tampered envelope succeeded")
+			}
+		})
+	}
+}
+
+func TestParseIdentityRoundTrip(t *testing.T) {
+	id, _ := Generate()
+
+	parsed, err := ParseIdentity(id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Recipient() != id.Recipient() {
+		t.Errorf("Recipient = %s, want %s", parsed.Recipient(), id.Recipient())
+	}
+	if _, err := ParseRecipient(id.Recipient()); err != nil {
+		t.Errorf("ParseRecipient: %v", err)
+	}
+	if _, err := ParseIdentity(id.Recipient()); err == nil {
+		t.Error("ParseIdentity accepted a public key")
+	}
+}
+
+func TestSaveRestrictsExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identity")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	id, _ := Generate()
+	if err := Save(path, id); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != identityFileMode {
+		t.Errorf("mode = %o, want %o", mode, identityFileMode)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Recipient() != id.Recipient() {
+		t.Error("Load returned a different identity")
+	}
+}
@@ -0,0 +1,164 @@
+package identity
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+// EnvelopeVersion is the current shared key envelope format version
+const EnvelopeVersion = 1
+
+// hkdfInfo binds derived keys to this envelope format
+const hkdfInfo = "aim/keyshare/v1"
+
+// ErrNotRecipient is returned when an envelope was sealed for someone else
+var ErrNotRecipient = errors.New("envelope is not addressed to this identity")
+
+// SharedKey is the plaintext payload carried inside an envelope
+type SharedKey struct {
+	Name        string `json:"name"`
+	Provider    string `json:"provider"`
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	// Cloud keys (Bedrock, Vertex) carry structured credentials instead of Key
+	Credentials *config.Credentials `json:"credentials,omitempty"`
+	// Azure OpenAI deployment and api-version
+	Deployment string `json:"deployment,omitempty"`
+	APIVersion string `json:"api_version,omitempty"`
+}
+
+// Envelope is an encrypted shared key addressed to a single recipient
+// Sealing follows the age X25519 recipient scheme: an ephemeral X25519 share,
+// HKDF-SHA256 over (ephemeral || recipient) and an AEAD over the payload
+type Envelope struct {
+	Version    int    `json:"version"`
+	Recipient  string `json:"recipient"`
+	Ephemeral  []byte `json:"ephemeral"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts a shared key for the given recipient public key
+func Seal(payload *SharedKey, recipient *ecdh.PublicKey) (*Envelope, error) {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement failed: %w", err)
+	}
+
+	aead, err := newAEAD(shared, ephemeral.PublicKey().Bytes(), recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	recipientStr := EncodePublicKey(recipient)
+	return &Envelope{
+		Version:    EnvelopeVersion,
+		Recipient:  recipientStr,
+		Ephemeral:  ephemeral.PublicKey().Bytes(),
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(recipientStr)),
+	}, nil
+}
+
+// Open decrypts an envelope with the given identity
+func Open(env *Envelope, id *Identity) (*SharedKey, error) {
+	if env.Version != EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version: %d", env.Version)
+	}
+
+	if env.Recipient != id.Recipient() {
+		return nil, fmt.Errorf("%w (sealed for %s)", ErrNotRecipient, env.Recipient)
+	}
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(env.Ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key: %w", err)
+	}
+
+	shared, err := id.privateKey.ECDH(ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement failed: %w", err)
+	}
+
+	aead, err := newAEAD(shared, env.Ephemeral, id.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(env.Nonce))
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, []byte(env.Recipient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt envelope: %w", err)
+	}
+
+	var payload SharedKey
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// Marshal encodes an envelope for writing to a file
+func (env *Envelope) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseEnvelope decodes an envelope read from a file
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid envelope: %w", err)
+	}
+	return &env, nil
+}
+
+// newAEAD derives the envelope's AES-256-GCM key from the X25519 shared secret
+func newAEAD(shared, ephemeral, recipient []byte) (cipher.AEAD, error) {
+	salt := make([]byte, 0, len(ephemeral)+len(recipient))
+	salt = append(salt, ephemeral...)
+	salt = append(salt, recipient...)
+
+	key, err := hkdf.Key(sha256.New, shared, salt, hkdfInfo, 32)
+	if err != nil {
+		return nil, fmt.Errorf("key derivation failed: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+)
+
+// BalanceEntry is the cached balance of one key
+// Failed queries are cached too, so an unreachable endpoint does not delay every 'aim run'.
+type BalanceEntry struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"` // Identifies the API key and endpoint the balance belongs to
+	FetchedAt   time.Time `json:"fetched_at"`
+	Available   float64   `json:"available"`
+	Currency    string    `json:"currency,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// BalanceCache stores key balances as JSON files with a TTL
+type BalanceCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewBalanceCache creates a cache at the default location (~/.aim/cache/balance)
+func NewBalanceCache() *BalanceCache {
+	return NewBalanceCacheWithDir(filepath.Join(config.GetAIMHome(), "cache", "balance"), constants.BalanceCacheTTL)
+}
+
+// NewBalanceCacheWithDir creates a cache in a custom directory with a custom TTL
+func NewBalanceCacheWithDir(dir string, ttl time.Duration) *BalanceCache {
+	return &BalanceCache{dir: dir, ttl: ttl}
+}
+
+// BalanceFingerprint identifies an API key without storing it
+// scope tells apart endpoints the same key is queried against, e.g. the provider name.
+func BalanceFingerprint(apiKey, scope string) string {
+	sum := sha256.Sum256([]byte(scope + "\x00" + apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// path returns the cache file for a key
+func (c *BalanceCache) path(keyName string) string {
+	return filepath.Join(c.dir, keyName+".json")
+}
+
+// Load returns the cached entry for a key when it is fresh and matches the fingerprint
+func (c *BalanceCache) Load(keyName, fingerprint string) (*BalanceEntry, bool) {
+	data, err := os.ReadFile(c.path(keyName))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry BalanceEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Fingerprint != fingerprint || time.Since(entry.FetchedAt) >= c.ttl {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Save stores a key's balance, stamping it with the current time
+func (c *BalanceCache) Save(entry *BalanceEntry) error {
+	entry.FetchedAt = time.Now()
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal balance cache: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, constants.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create balance cache directory: %w", err)
+	}
+
+	if err := os.WriteFile(c.path(entry.Key), data, constants.ConfigFileMode); err != nil {
+		return fmt.Errorf("failed to write balance cache: %w", err)
+	}
+
+	return nil
+}
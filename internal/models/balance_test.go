@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBalanceCache(t *testing.T) {
+	cache := NewBalanceCacheWithDir(t.TempDir(), time.Hour)
+	fingerprint := BalanceFingerprint("sk-test", "deepseek")
+
+	if _, ok := cache.Load("work", fingerprint); ok {
+		t.Fatal("empty cache returned an entry")
+	}
+
+	if err := cache.Save(&BalanceEntry{Key: "work", Fingerprint: fingerprint, Available: 12.5, Currency: "CNY"}); err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := cache.Load("work", fingerprint)
+	if !ok || entry.Available != 12.5 || entry.Currency != "CNY" {
+		t.Fatalf("Load = %+v, %v", entry, ok)
+	}
+
+	// A replaced key or another endpoint does not reuse the balance
+	if _, ok := cache.Load("work", BalanceFingerprint("sk-other", "deepseek")); ok {
+		t.Error("entry matched a different key")
+	}
+
+	expired := NewBalanceCacheWithDir(cache.dir, 0)
+	if _, ok := expired.Load("work", fingerprint); ok {
+		t.Error("expired entry was returned")
+	}
+}
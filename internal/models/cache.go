@@ -0,0 +1,113 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+)
+
+// CacheEntry is the cached model list of one provider endpoint
+type CacheEntry struct {
+	Provider  string    `json:"provider"`
+	BaseURL   string    `json:"base_url"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Models    []string  `json:"models"`
+}
+
+// Age returns how long ago the entry was fetched
+func (e *CacheEntry) Age() time.Duration {
+	return time.Since(e.FetchedAt)
+}
+
+// Contains reports whether the model ID is in the cached list
+func (e *CacheEntry) Contains(model string) bool {
+	for _, m := range e.Models {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// Cache stores provider model lists as JSON files with a TTL
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewCache creates a cache at the default location (~/.aim/cache/models)
+func NewCache() *Cache {
+	return NewCacheWithDir(filepath.Join(config.GetAIMHome(), "cache", "models"), constants.ModelsCacheTTL)
+}
+
+// NewCacheWithDir creates a cache in a custom directory with a custom TTL
+func NewCacheWithDir(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// path returns the cache file for a provider's endpoint
+// Entries are keyed by base URL too, so a region or base URL change does not serve another endpoint's list.
+func (c *Cache) path(provider, baseURL string) (string, error) {
+	if !filepath.IsLocal(provider) || strings.ContainsAny(provider, `/\`) {
+		return "", fmt.Errorf("invalid provider name for model cache: %q", provider)
+	}
+	sum := sha256.Sum256([]byte(baseURL))
+	return filepath.Join(c.dir, provider+"-"+hex.EncodeToString(sum[:4])+".json"), nil
+}
+
+// Load returns the cached entry for a provider's endpoint, whether it exists, and whether it is still fresh
+func (c *Cache) Load(provider, baseURL string) (*CacheEntry, bool, bool) {
+	path, err := c.path(provider, baseURL)
+	if err != nil {
+		return nil, false, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Provider != provider || entry.BaseURL != baseURL {
+		return nil, false, false
+	}
+
+	return &entry, true, entry.Age() < c.ttl
+}
+
+// Save stores the model list for a provider's endpoint
+func (c *Cache) Save(provider, baseURL string, models []string) (*CacheEntry, error) {
+	path, err := c.path(provider, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &CacheEntry{
+		Provider:  provider,
+		BaseURL:   baseURL,
+		FetchedAt: time.Now(),
+		Models:    models,
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal model cache: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, constants.ConfigDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create model cache directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, constants.ConfigFileMode); err != nil {
+		return nil, fmt.Errorf("failed to write model cache: %w", err)
+	}
+
+	return entry, nil
+}
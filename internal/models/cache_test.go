@@ -0,0 +1,49 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheKeyedByBaseURL(t *testing.T) {
+	cache := NewCacheWithDir(t.TempDir(), time.Hour)
+
+	if _, err := cache.Save("kimi", "https://api.moonshot.cn/v1", []string{"kimi-k2"}); err != nil {
+		t.Fatal(err)
+	}
+	entry, exists, fresh := cache.Load("kimi", "https://api.moonshot.cn/v1")
+	if !exists || !fresh || !entry.Contains("kimi-k2") {
+		t.Fatalf("Load = %+v, %v, %v", entry, exists, fresh)
+	}
+
+	// After a region switch the other endpoint's list is not served
+	if _, exists, _ := cache.Load("kimi", "https://api.moonshot.ai/v1"); exists {
+		t.Error("entry of another base URL was returned")
+	}
+
+	expired := NewCacheWithDir(cache.dir, 0)
+	if _, exists, fresh := expired.Load("kimi", "https://api.moonshot.cn/v1"); !exists || fresh {
+		t.Errorf("expired entry: exists = %v, fresh = %v", exists, fresh)
+	}
+}
+
+func TestCacheRejectsPathNames(t *testing.T) {
+	root := t.TempDir()
+	cache := NewCacheWithDir(filepath.Join(root, "models"), time.Hour)
+
+	for _, name := range []string{"../x", "a/b", "/etc/passwd", ""} {
+		if _, err := cache.Save(name, "http://localhost", []string{"m"}); err == nil {
+			t.Errorf("Save(%q) succeeded", name)
+		}
+		if _, exists, _ := cache.Load(name, "http://localhost"); exists {
+			t.Errorf("Load(%q) returned an entry", name)
+		}
+	}
+
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 0 {
+		t.Errorf("files written outside the cache dir: %v", entries)
+	}
+}
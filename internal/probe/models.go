@@ -0,0 +1,94 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/fakecore/aim/internal/tool"
+)
+
+// maxModelsBody caps the size of a /models response
+const maxModelsBody = 4 * 1024 * 1024
+
+// Error is a classified failure talking to a provider endpoint
+type Error struct {
+	StatusCode int
+	Class      ErrorClass
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: %s (%d)", e.Class, e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s", e.Class, e.Message)
+}
+
+// ListModels fetches model IDs from the target's /models endpoint
+// OpenAI-compatible endpoints are queried at {base}/models, Anthropic-compatible ones at {base}/v1/models
+func (p *Prober) ListModels(ctx context.Context, target Target) ([]string, error) {
+	if target.BaseURL == "" {
+		return nil, &Error{Class: ClassConfig, Message: "base URL is not configured"}
+	}
+	base := strings.TrimRight(target.BaseURL, "/")
+	var endpoint string
+
+	switch target.Protocol {
+	case tool.ProtocolAnthropic:
+		endpoint = base + "/v1/models?limit=1000"
+	case tool.ProtocolOpenAI:
+		endpoint = base + "/models"
+		if target.AzureAPIVersion != "" {
+			endpoint += "?api-version=" + url.QueryEscape(target.AzureAPIVersion)
+		}
+	default:
+		return nil, &Error{Class: ClassConfig, Message: fmt.Sprintf("unsupported protocol '%s'", target.Protocol)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, &Error{Class: ClassConfig, Message: fmt.Sprintf("invalid endpoint URL: %v", err)}
+	}
+	if target.Protocol == tool.ProtocolAnthropic {
+		req.Header.Set("anthropic-version", anthropicVersion)
+	}
+	setAuth(req.Header, target)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, &Error{Class: ClassNetwork, Message: networkMessage(err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxModelsBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		class, message := classifyBody(resp.StatusCode, body)
+		return nil, &Error{StatusCode: resp.StatusCode, Class: class, Message: message}
+	}
+
+	// Both APIs return {"data": [{"id": "..."}]}
+	var parsed struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, &Error{StatusCode: resp.StatusCode, Class: ClassHTTP, Message: "unexpected /models response"}
+	}
+
+	models := make([]string, 0, len(parsed.Data))
+	for _, m := range parsed.Data {
+		if m.ID != "" {
+			models = append(models, m.ID)
+		}
+	}
+	sort.Strings(models)
+
+	return models, nil
+}
@@ -0,0 +1,307 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/tool"
+)
+
+// ErrorClass categorizes why a probe failed
+type ErrorClass string
+
+const (
+	ClassNone          ErrorClass = ""                // Probe succeeded
+	ClassConfig        ErrorClass = "config"          // Missing base URL, model or key
+	ClassAuth          ErrorClass = "auth"            // Key rejected (401/403)
+	ClassModelNotFound ErrorClass = "model_not_found" // Endpoint does not serve the model
+	ClassRateLimit     ErrorClass = "rate_limit"      // Throttled or out of quota (429)
+	ClassNetwork       ErrorClass = "network"         // DNS, TLS, connection or timeout failure
+	ClassServer        ErrorClass = "server"          // Provider-side failure (5xx)
+	ClassHTTP          ErrorClass = "http"            // Any other unexpected HTTP status
+)
+
+// anthropicVersion is the API version header sent to Anthropic-compatible endpoints
+const anthropicVersion = "2023-06-01"
+
+// minResponsesOutputTokens is the smallest max_output_tokens the Responses API accepts
+const minResponsesOutputTokens = 16
+
+// maxErrorBody caps how much of an error response is read
+const maxErrorBody = 64 * 1024
+
+// Target is one endpoint to probe
+type Target struct {
+	Protocol tool.Protocol
+	BaseURL  string
+	APIKey   string // Empty sends no credentials, for local servers
+	Model    string
+	// AzureAPIVersion selects the Azure OpenAI URL shape: Model is the deployment,
+	// the version goes in the api-version query parameter and the key in an api-key header
+	AzureAPIVersion string
+	KeyHeader       string            // Sends the key as-is in this header instead of the protocol default, e.g. x-api-key
+	Headers         map[string]string // Extra headers sent with every request
+	// WireAPI sends OpenAI requests the way codex does: to {base}/responses or {base}/chat/completions
+	// with the model in the body and QueryParams on the URL. Empty uses the plain chat shape.
+	WireAPI     string
+	QueryParams map[string]string
+}
+
+// missingField describes the first required target field that is empty
+func (t *Target) missingField() string {
+	switch {
+	case t.BaseURL == "":
+		return "base URL is not configured"
+	case t.Model == "":
+		return "model is not configured"
+	}
+	return ""
+}
+
+// Result is the outcome of a single probe
+type Result struct {
+	StatusCode int
+	Latency    time.Duration
+	Class      ErrorClass
+	Message    string
+}
+
+// OK reports whether the endpoint accepted the request
+func (r *Result) OK() bool {
+	return r.Class == ClassNone
+}
+
+// Prober sends minimal real requests to provider endpoints
+type Prober struct {
+	Client *http.Client
+}
+
+// NewProber creates a prober using the given HTTP client (http.DefaultClient when nil)
+func NewProber(client *http.Client) *Prober {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Prober{Client: client}
+}
+
+// Probe sends a one-token completion request to the target and classifies the response
+func (p *Prober) Probe(ctx context.Context, target Target) Result {
+	if msg := target.missingField(); msg != "" {
+		return Result{Class: ClassConfig, Message: msg}
+	}
+
+	req, err := NewRequest(ctx, target)
+	if err != nil {
+		return Result{Class: ClassConfig, Message: err.Error()}
+	}
+
+	start := time.Now()
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return Result{Latency: time.Since(start), Class: ClassNetwork, Message: networkMessage(err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	result := Result{StatusCode: resp.StatusCode, Latency: time.Since(start)}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return result
+	}
+
+	result.Class, result.Message = classifyBody(resp.StatusCode, body)
+	return result
+}
+
+// classifyBody classifies an error response and extracts its message
+func classifyBody(statusCode int, body []byte) (ErrorClass, string) {
+	message := errorMessage(body)
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return Classify(statusCode, message), message
+}
+
+// NewRequest builds the probe request for the target's protocol
+func NewRequest(ctx context.Context, target Target) (*http.Request, error) {
+	return newRequest(ctx, target, "ping", 1, false)
+}
+
+// newRequest builds a completion request with the given prompt, output budget and streaming mode
+func newRequest(ctx context.Context, target Target, prompt string, maxTokens int, stream bool) (*http.Request, error) {
+	base := strings.TrimRight(target.BaseURL, "/")
+	payload := map[string]interface{}{
+		"model":      target.Model,
+		"max_tokens": maxTokens,
+		"messages":   []map[string]string{{"role": "user", "content": prompt}},
+	}
+	if stream {
+		payload["stream"] = true
+	}
+
+	var endpoint string
+	header := http.Header{}
+
+	switch target.Protocol {
+	case tool.ProtocolAnthropic:
+		endpoint = base + "/v1/messages"
+		header.Set("anthropic-version", anthropicVersion)
+	case tool.ProtocolOpenAI:
+		switch {
+		case target.WireAPI == config.WireAPIResponses:
+			endpoint = base + "/responses"
+			payload = map[string]interface{}{
+				"model":             target.Model,
+				"input":             prompt,
+				"max_output_tokens": max(maxTokens, minResponsesOutputTokens),
+			}
+			if stream {
+				payload["stream"] = true
+			}
+		case target.WireAPI != "":
+			endpoint = base + "/chat/completions"
+		case target.AzureAPIVersion != "":
+			endpoint = fmt.Sprintf("%s/deployments/%s/chat/completions?api-version=%s",
+				base, url.PathEscape(target.Model), url.QueryEscape(target.AzureAPIVersion))
+		default:
+			endpoint = base + "/chat/completions"
+		}
+		if target.WireAPI != "" && len(target.QueryParams) > 0 {
+			query := url.Values{}
+			for name, value := range target.QueryParams {
+				query.Set(name, value)
+			}
+			endpoint += "?" + query.Encode()
+		}
+		if stream && target.WireAPI != config.WireAPIResponses {
+			// Ask for a final usage chunk so token counts are exact
+			payload["stream_options"] = map[string]bool{"include_usage": true}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported protocol '%s'", target.Protocol)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode probe request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+	setAuth(header, target)
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	return req, nil
+}
+
+// setAuth sets the key header and the target's extra headers
+// Anthropic-compatible gateways accept either key header by default; Claude Code sends the bearer token
+func setAuth(header http.Header, target Target) {
+	switch {
+	case target.APIKey == "":
+	case target.KeyHeader != "":
+		header.Set(target.KeyHeader, target.APIKey)
+	case target.AzureAPIVersion != "":
+		header.Set("api-key", target.APIKey)
+	case target.Protocol == tool.ProtocolAnthropic:
+		header.Set("x-api-key", target.APIKey)
+		header.Set("Authorization", "Bearer "+target.APIKey)
+	default:
+		header.Set("Authorization", "Bearer "+target.APIKey)
+	}
+	for name, value := range target.Headers {
+		header.Set(name, value)
+	}
+}
+
+// Classify maps an HTTP error status and message to an error class
+func Classify(statusCode int, message string) ErrorClass {
+	lower := strings.ToLower(message)
+	mentionsModel := strings.Contains(lower, "model")
+	modelMissing := mentionsModel && (strings.Contains(lower, "not found") ||
+		strings.Contains(lower, "not exist") ||
+		strings.Contains(lower, "does not exist") ||
+		strings.Contains(lower, "invalid model") ||
+		strings.Contains(lower, "unknown model") ||
+		strings.Contains(lower, "not supported"))
+
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ClassAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ClassRateLimit
+	case statusCode == http.StatusNotFound && mentionsModel:
+		return ClassModelNotFound
+	case statusCode == http.StatusBadRequest && modelMissing:
+		return ClassModelNotFound
+	case statusCode >= 500:
+		return ClassServer
+	default:
+		return ClassHTTP
+	}
+}
+
+// errorMessage extracts a provider error message from an OpenAI or Anthropic error body
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return truncate(strings.TrimSpace(string(body)))
+	}
+
+	if len(parsed.Error) > 0 {
+		var nested struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(parsed.Error, &nested) == nil && nested.Message != "" {
+			return truncate(nested.Message)
+		}
+		var flat string
+		if json.Unmarshal(parsed.Error, &flat) == nil && flat != "" {
+			return truncate(flat)
+		}
+	}
+
+	return truncate(parsed.Message)
+}
+
+// networkMessage describes a transport error without the full request URL noise
+func networkMessage(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "request timed out"
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if urlErr.Timeout() {
+			return "request timed out"
+		}
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// truncate shortens long messages for single-line output
+func truncate(s string) string {
+	const limit = 200
+	if len(s) > limit {
+		return s[:limit] + "..."
+	}
+	return s
+}
@@ -0,0 +1,240 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fakecore/aim/internal/tool"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		message string
+		want    ErrorClass
+	}{
+		{"unauthorized", http.StatusUnauthorized, "invalid api key", ClassAuth},
+		{"forbidden", http.StatusForbidden, "forbidden", ClassAuth},
+		{"rate limit", http.StatusTooManyRequests, "slow down", ClassRateLimit},
+		{"model not found", http.StatusNotFound, "The model `gpt-x` does not exist", ClassModelNotFound},
+		{"plain not found", http.StatusNotFound, "Not Found", ClassHTTP},
+		{"bad request model", http.StatusBadRequest, "Invalid model: foo", ClassModelNotFound},
+		{"bad request other", http.StatusBadRequest, "max_tokens too large", ClassHTTP},
+		{"server", http.StatusBadGateway, "upstream failed", ClassServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.status, tt.message); got != tt.want {
+				t.Errorf("Classify(%d, %q) = %q, want %q", tt.status, tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantClass   ErrorClass
+		wantMessage string
+	}{
+		{"ok", http.StatusOK, `{"id":"x"}`, ClassNone, ""},
+		{"auth", http.StatusUnauthorized, `{"error":{"message":"bad key"}}`, ClassAuth, "bad key"},
+		{"model", http.StatusNotFound, `{"error":{"message":"model not found"}}`, ClassModelNotFound, "model not found"},
+		{"rate limit", http.StatusTooManyRequests, `{"message":"quota exceeded"}`, ClassRateLimit, "quota exceeded"},
+		{"server", http.StatusServiceUnavailable, `overloaded`, ClassServer, "overloaded"},
+		{"empty body", http.StatusInternalServerError, ``, ClassServer, "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/chat/completions" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			result := NewProber(server.Client()).Probe(context.Background(), Target{
+				Protocol: tool.ProtocolOpenAI,
+				BaseURL:  server.URL + "/v1",
+				APIKey:   "sk-test",
+				Model:    "test-model",
+			})
+
+			if result.Class != tt.wantClass {
+				t.Errorf("class = %q, want %q (message %q)", result.Class, tt.wantClass, result.Message)
+			}
+			if result.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", result.Message, tt.wantMessage)
+			}
+			if result.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", result.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestProbeAnthropicHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "sk-ant" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing anthropic headers: %v", r.Header)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	result := NewProber(server.Client()).Probe(context.Background(), Target{
+		Protocol: tool.ProtocolAnthropic,
+		BaseURL:  server.URL,
+		APIKey:   "sk-ant",
+		Model:    "claude",
+	})
+	if !result.OK() {
+		t.Fatalf("probe failed: %s: %s", result.Class, result.Message)
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	result := NewProber(server.Client()).Probe(ctx, Target{
+		Protocol: tool.ProtocolOpenAI,
+		BaseURL:  server.URL,
+		Model:    "test-model",
+	})
+	if result.Class != ClassNetwork {
+		t.Fatalf("class = %q, want %q", result.Class, ClassNetwork)
+	}
+	if result.Message != "request timed out" {
+		t.Errorf("message = %q", result.Message)
+	}
+}
+
+func TestProbeMissingConfig(t *testing.T) {
+	result := NewProber(nil).Probe(context.Background(), Target{Protocol: tool.ProtocolOpenAI, BaseURL: "http://localhost"})
+	if result.Class != ClassConfig {
+		t.Fatalf("class = %q, want %q", result.Class, ClassConfig)
+	}
+}
+
+func TestProbeWireAPI(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    Target
+		wantPath  string
+		wantQuery string
+		wantField string // Payload field carrying the output budget
+	}{
+		{
+			name:      "azure responses",
+			target:    Target{WireAPI: "responses", AzureAPIVersion: "2025-04-01-preview", QueryParams: map[string]string{"api-version": "2025-04-01-preview"}},
+			wantPath:  "/openai/responses",
+			wantQuery: "api-version=2025-04-01-preview",
+			wantField: "max_output_tokens",
+		},
+		{
+			name:      "azure chat",
+			target:    Target{WireAPI: "chat", AzureAPIVersion: "2025-04-01-preview", QueryParams: map[string]string{"api-version": "2025-04-01-preview"}},
+			wantPath:  "/openai/chat/completions",
+			wantQuery: "api-version=2025-04-01-preview",
+			wantField: "max_tokens",
+		},
+		{
+			name:      "azure deployment",
+			target:    Target{AzureAPIVersion: "2025-04-01-preview"},
+			wantPath:  "/openai/deployments/gpt-dep/chat/completions",
+			wantQuery: "api-version=2025-04-01-preview",
+			wantField: "max_tokens",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath || r.URL.RawQuery != tt.wantQuery {
+					t.Errorf("request = %s?%s, want %s?%s", r.URL.Path, r.URL.RawQuery, tt.wantPath, tt.wantQuery)
+				}
+				if r.Header.Get("api-key") != "az-key" {
+					t.Errorf("api-key header = %q", r.Header.Get("api-key"))
+				}
+				var payload map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Error(err)
+				}
+				if _, ok := payload[tt.wantField]; !ok {
+					t.Errorf("payload %v has no %s", payload, tt.wantField)
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			target := tt.target
+			target.Protocol = tool.ProtocolOpenAI
+			target.BaseURL = server.URL + "/openai"
+			target.APIKey = "az-key"
+			target.Model = "gpt-dep"
+
+			if result := NewProber(server.Client()).Probe(context.Background(), target); !result.OK() {
+				t.Fatalf("probe failed: %s: %s", result.Class, result.Message)
+			}
+		})
+	}
+}
+
+func TestStreamResponsesEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []string{
+			`{"type":"response.created","response":{"id":"r1"}}`,
+			`{"type":"response.output_text.delta","delta":"Hel"}`,
+			`{"type":"response.output_text.delta","delta":"lo"}`,
+			`{"type":"response.completed","response":{"usage":{"input_tokens":5,"output_tokens":7}}}`,
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "event: x\ndata: %s\n\n", event)
+		}
+	}))
+	defer server.Close()
+
+	result := NewProber(server.Client()).Stream(context.Background(), Target{
+		Protocol: tool.ProtocolOpenAI,
+		BaseURL:  server.URL,
+		Model:    "gpt-dep",
+		WireAPI:  "responses",
+	}, "hi", 32)
+
+	if !result.OK() {
+		t.Fatalf("stream failed: %s: %s", result.Class, result.Message)
+	}
+	if result.OutputTokens != 7 {
+		t.Errorf("OutputTokens = %d, want 7", result.OutputTokens)
+	}
+}
@@ -0,0 +1,185 @@
+package probe
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// StreamResult is the outcome of one streaming completion
+type StreamResult struct {
+	StatusCode   int
+	TTFT         time.Duration // Time to first content token
+	Latency      time.Duration // Time until the stream ended
+	OutputTokens int           // Reported by the provider, or counted from content chunks
+	Class        ErrorClass
+	Message      string
+}
+
+// OK reports whether the stream completed successfully
+func (r *StreamResult) OK() bool {
+	return r.Class == ClassNone
+}
+
+// TokensPerSecond returns output throughput after the first token
+func (r *StreamResult) TokensPerSecond() float64 {
+	generation := r.Latency - r.TTFT
+	if r.OutputTokens == 0 || generation <= 0 {
+		return 0
+	}
+	return float64(r.OutputTokens) / generation.Seconds()
+}
+
+// streamUsage is the token usage reported at the end of a stream
+type streamUsage struct {
+	OutputTokens     int `json:"output_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// streamEvent covers the fields aim reads from Anthropic and OpenAI stream events
+type streamEvent struct {
+	// Anthropic, and OpenAI Responses events which carry the text delta as a string
+	Type  string          `json:"type"`
+	Delta json.RawMessage `json:"delta"`
+	Usage *streamUsage    `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+
+	// OpenAI Chat Completions
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"delta"`
+	} `json:"choices"`
+
+	// OpenAI Responses: usage arrives on the final response.completed event
+	Response *struct {
+		Usage *streamUsage `json:"usage"`
+	} `json:"response"`
+}
+
+// content returns the text the event adds to the output
+func (e *streamEvent) content() string {
+	var text string
+	if len(e.Delta) > 0 {
+		if json.Unmarshal(e.Delta, &text) != nil {
+			var delta struct {
+				Text string `json:"text"`
+			}
+			json.Unmarshal(e.Delta, &delta)
+			text = delta.Text
+		}
+	}
+	for _, choice := range e.Choices {
+		text += choice.Delta.Content + choice.Delta.ReasoningContent
+	}
+	return text
+}
+
+// usage returns the token usage the event reports, if any
+func (e *streamEvent) usage() *streamUsage {
+	if e.Usage != nil {
+		return e.Usage
+	}
+	if e.Response != nil {
+		return e.Response.Usage
+	}
+	return nil
+}
+
+// Stream sends a streaming completion and measures time to first token, latency and output tokens
+func (p *Prober) Stream(ctx context.Context, target Target, prompt string, maxTokens int) StreamResult {
+	if msg := target.missingField(); msg != "" {
+		return StreamResult{Class: ClassConfig, Message: msg}
+	}
+
+	req, err := newRequest(ctx, target, prompt, maxTokens, true)
+	if err != nil {
+		return StreamResult{Class: ClassConfig, Message: err.Error()}
+	}
+
+	start := time.Now()
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return StreamResult{Latency: time.Since(start), Class: ClassNetwork, Message: networkMessage(err)}
+	}
+	defer resp.Body.Close()
+
+	result := StreamResult{StatusCode: resp.StatusCode}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		result.Latency = time.Since(start)
+		result.Class, result.Message = classifyBody(resp.StatusCode, body)
+		return result
+	}
+
+	counted := 0
+	reported := 0
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var event streamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			continue
+		}
+
+		if event.Error != nil {
+			result.Latency = time.Since(start)
+			result.Class = ClassServer
+			result.Message = truncate(event.Error.Message)
+			return result
+		}
+
+		if event.content() != "" {
+			if result.TTFT == 0 {
+				result.TTFT = time.Since(start)
+			}
+			counted++
+		}
+
+		if usage := event.usage(); usage != nil {
+			if usage.OutputTokens > 0 {
+				reported = usage.OutputTokens
+			}
+			if usage.CompletionTokens > 0 {
+				reported = usage.CompletionTokens
+			}
+		}
+	}
+	result.Latency = time.Since(start)
+
+	if err := scanner.Err(); err != nil {
+		result.Class = ClassNetwork
+		result.Message = networkMessage(err)
+		return result
+	}
+	if result.TTFT == 0 {
+		result.Class = ClassHTTP
+		result.Message = "stream ended without any content"
+		return result
+	}
+
+	// Prefer provider-reported usage; fall back to one token per content chunk
+	result.OutputTokens = reported
+	if result.OutputTokens == 0 {
+		result.OutputTokens = counted
+	}
+
+	return result
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrBalanceUnsupported is returned for providers without a balance endpoint
+var ErrBalanceUnsupported = errors.New("balance query not supported")
+
+// Balance is the remaining credit of an account
+type Balance struct {
+	Available float64            // Total spendable credit
+	Currency  string             // ISO currency code, e.g. CNY or USD
+	Details   map[string]float64 // Provider-specific breakdown, e.g. granted vs topped-up
+}
+
+// BalanceAdapter queries the remaining credit for an API key
+type BalanceAdapter interface {
+	// Balance fetches the account balance for the key
+	Balance(ctx context.Context, client *http.Client, apiKey string) (*Balance, error)
+}
+
+// moonshotCNBalance queries the mainland China host, which bills in CNY
+var moonshotCNBalance = &MoonshotBalance{BaseURL: "https://api.moonshot.cn", Currency: "CNY"}
+
+// balanceAdapters maps builtin providers to adapters using their production endpoints, by region
+// The "" entry serves single-region providers and keys whose region is unknown.
+var balanceAdapters = map[string]map[string]BalanceAdapter{
+	"deepseek": {"": &DeepSeekBalance{BaseURL: "https://api.deepseek.com"}},
+	"kimi": {
+		"":         moonshotCNBalance,
+		RegionCN:   moonshotCNBalance,
+		RegionIntl: &MoonshotBalance{BaseURL: "https://api.moonshot.ai", Currency: "USD"},
+	},
+}
+
+// GetBalanceAdapter returns the balance adapter for a provider (handles endpoint-suffixed names)
+// region selects the host for providers with separate cn and intl accounts; empty uses the base region.
+func GetBalanceAdapter(providerName, region string) (BalanceAdapter, error) {
+	info, ok := ResolveBuiltinProvider(providerName)
+	if !ok {
+		return nil, fmt.Errorf("%w for provider '%s'", ErrBalanceUnsupported, providerName)
+	}
+	adapters, ok := balanceAdapters[info.Name]
+	if !ok {
+		return nil, fmt.Errorf("%w for provider '%s'", ErrBalanceUnsupported, providerName)
+	}
+	if adapter, ok := adapters[region]; ok {
+		return adapter, nil
+	}
+	return adapters[""], nil
+}
+
+// DeepSeekBalance queries GET {BaseURL}/user/balance
+type DeepSeekBalance struct {
+	BaseURL string
+}
+
+// Balance implements BalanceAdapter
+func (d *DeepSeekBalance) Balance(ctx context.Context, client *http.Client, apiKey string) (*Balance, error) {
+	var resp struct {
+		IsAvailable  bool `json:"is_available"`
+		BalanceInfos []struct {
+			Currency        string `json:"currency"`
+			TotalBalance    string `json:"total_balance"`
+			GrantedBalance  string `json:"granted_balance"`
+			ToppedUpBalance string `json:"topped_up_balance"`
+		} `json:"balance_infos"`
+	}
+	if err := getBalanceJSON(ctx, client, d.BaseURL+"/user/balance", apiKey, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.BalanceInfos) == 0 {
+		return nil, fmt.Errorf("balance response has no balance_infos")
+	}
+
+	// Accounts hold one entry per currency; report the first and keep the rest as details
+	info := resp.BalanceInfos[0]
+	balance := &Balance{
+		Available: parseAmount(info.TotalBalance),
+		Currency:  info.Currency,
+		Details: map[string]float64{
+			"granted":   parseAmount(info.GrantedBalance),
+			"topped_up": parseAmount(info.ToppedUpBalance),
+		},
+	}
+	for _, other := range resp.BalanceInfos[1:] {
+		balance.Details["total_"+strings.ToLower(other.Currency)] = parseAmount(other.TotalBalance)
+	}
+
+	return balance, nil
+}
+
+// MoonshotBalance queries GET {BaseURL}/v1/users/me/balance
+type MoonshotBalance struct {
+	BaseURL  string
+	Currency string // The API does not report a currency; CNY for .cn, USD for .ai
+}
+
+// Balance implements BalanceAdapter
+func (m *MoonshotBalance) Balance(ctx context.Context, client *http.Client, apiKey string) (*Balance, error) {
+	var resp struct {
+		Code int `json:"code"`
+		Data struct {
+			AvailableBalance float64 `json:"available_balance"`
+			VoucherBalance   float64 `json:"voucher_balance"`
+			CashBalance      float64 `json:"cash_balance"`
+		} `json:"data"`
+	}
+	if err := getBalanceJSON(ctx, client, m.BaseURL+"/v1/users/me/balance", apiKey, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("balance query failed with code %d", resp.Code)
+	}
+
+	return &Balance{
+		Available: resp.Data.AvailableBalance,
+		Currency:  m.Currency,
+		Details: map[string]float64{
+			"voucher": resp.Data.VoucherBalance,
+			"cash":    resp.Data.CashBalance,
+		},
+	}, nil
+}
+
+// getBalanceJSON performs an authenticated GET and decodes the JSON response
+func getBalanceJSON(ctx context.Context, client *http.Client, url, apiKey string, out interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid balance URL: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("balance request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read balance response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("key rejected by provider (%d)", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("balance request returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse balance response: %w", err)
+	}
+	return nil
+}
+
+// parseAmount parses a decimal string amount, treating malformed values as zero
+func parseAmount(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newBalanceServer serves body at path for requests carrying the key sk-test, 401 otherwise
+func newBalanceServer(t *testing.T, path, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer sk-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDeepSeekBalance(t *testing.T) {
+	server := newBalanceServer(t, "/user/balance", `{
+		"is_available": true,
+		"balance_infos": [
+			{"currency": "CNY", "total_balance": "110.00", "granted_balance": "10.00", "topped_up_balance": "100.00"},
+			{"currency": "USD", "total_balance": "5.50", "granted_balance": "0.00", "topped_up_balance": "5.50"}
+		]
+	}`)
+	adapter := &DeepSeekBalance{BaseURL: server.URL}
+
+	balance, err := adapter.Balance(context.Background(), server.Client(), "sk-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Available != 110 || balance.Currency != "CNY" {
+		t.Errorf("balance = %v %s, want 110 CNY", balance.Available, balance.Currency)
+	}
+	want := map[string]float64{"granted": 10, "topped_up": 100, "total_usd": 5.5}
+	for name, value := range want {
+		if balance.Details[name] != value {
+			t.Errorf("Details[%s] = %v, want %v", name, balance.Details[name], value)
+		}
+	}
+
+	if _, err := adapter.Balance(context.Background(), server.Client(), "sk-wrong"); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("wrong key error = %v, want key rejected", err)
+	}
+}
+
+func TestDeepSeekBalanceEmpty(t *testing.T) {
+	server := newBalanceServer(t, "/user/balance", `{"is_available": false, "balance_infos": []}`)
+	adapter := &DeepSeekBalance{BaseURL: server.URL}
+
+	if _, err := adapter.Balance(context.Background(), server.Client(), "sk-test"); err == nil {
+		t.Error("expected an error for a response without balance_infos")
+	}
+}
+
+func TestMoonshotBalance(t *testing.T) {
+	server := newBalanceServer(t, "/v1/users/me/balance",
+		`{"code": 0, "data": {"available_balance": 49.5, "voucher_balance": 20, "cash_balance": 29.5}, "status": true}`)
+	adapter := &MoonshotBalance{BaseURL: server.URL, Currency: "USD"}
+
+	balance, err := adapter.Balance(context.Background(), server.Client(), "sk-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Available != 49.5 || balance.Currency != "USD" {
+		t.Errorf("balance = %v %s, want 49.5 USD", balance.Available, balance.Currency)
+	}
+	if balance.Details["voucher"] != 20 || balance.Details["cash"] != 29.5 {
+		t.Errorf("Details = %v", balance.Details)
+	}
+}
+
+func TestMoonshotBalanceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"error code", `{"code": 1001, "data": {}}`},
+		{"invalid json", `not json`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newBalanceServer(t, "/v1/users/me/balance", tt.body)
+			adapter := &MoonshotBalance{BaseURL: server.URL, Currency: "CNY"}
+			if _, err := adapter.Balance(context.Background(), server.Client(), "sk-test"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"110.00", 110},
+		{"0.5", 0.5},
+		{"-3.25", -3.25},
+		{"", 0},
+		{"n/a", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseAmount(tt.in); got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetBalanceAdapterRegion(t *testing.T) {
+	tests := []struct {
+		provider string
+		region   string
+		baseURL  string
+		currency string
+	}{
+		{"kimi", "", "https://api.moonshot.cn", "CNY"},
+		{"kimi", RegionCN, "https://api.moonshot.cn", "CNY"},
+		{"kimi", RegionIntl, "https://api.moonshot.ai", "USD"},
+		{"deepseek", RegionIntl, "https://api.deepseek.com", ""},
+	}
+
+	for _, tt := range tests {
+		adapter, err := GetBalanceAdapter(tt.provider, tt.region)
+		if err != nil {
+			t.Fatalf("GetBalanceAdapter(%s, %s): %v", tt.provider, tt.region, err)
+		}
+		switch a := adapter.(type) {
+		case *MoonshotBalance:
+			if a.BaseURL != tt.baseURL || a.Currency != tt.currency {
+				t.Errorf("%s/%s = %s %s, want %s %s", tt.provider, tt.region, a.BaseURL, a.Currency, tt.baseURL, tt.currency)
+			}
+		case *DeepSeekBalance:
+			if a.BaseURL != tt.baseURL {
+				t.Errorf("%s/%s = %s, want %s", tt.provider, tt.region, a.BaseURL, tt.baseURL)
+			}
+		}
+	}
+
+	if _, err := GetBalanceAdapter("glm", ""); !errors.Is(err, ErrBalanceUnsupported) {
+		t.Errorf("glm error = %v, want ErrBalanceUnsupported", err)
+	}
+}
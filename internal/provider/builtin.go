@@ -9,31 +9,53 @@ import (
 
 // BuiltinProviderInfo represents builtin provider information with multiple endpoints
 type BuiltinProviderInfo struct {
-	Name        string           // Provider identifier
-	DisplayName string           // User-friendly display name
-	Description string           // Brief description
-	Website     string           // Official website (optional)
-	Endpoints   []EndpointPreset // Multiple endpoint presets
+	Name        string           `yaml:"name"`                   // Provider identifier
+	DisplayName string           `yaml:"display_name,omitempty"` // User-friendly display name
+	Description string           `yaml:"description,omitempty"`  // Brief description
+	Website     string           `yaml:"website,omitempty"`      // Official website (optional)
+	KeyPattern  string           `yaml:"key_pattern,omitempty"`  // Regular expression matching the provider's API key shape (optional)
+	Endpoints   []EndpointPreset `yaml:"endpoints,omitempty"`    // Multiple endpoint presets
+	Models      []ModelInfo      `yaml:"models,omitempty"`       // Model catalog (capabilities, limits and pricing)
+	Local       bool             `yaml:"local,omitempty"`        // Served on this machine; no API key required
+	Credentials string           `yaml:"credentials,omitempty"`  // Structured credential type used instead of an API key: aws, gcp
 }
 
 // EndpointPreset represents a specific endpoint configuration for a provider
 type EndpointPreset struct {
-	Name        string                // Endpoint name: general, coding, premium, etc.
-	Suffix      string                // Suggested config name suffix (e.g., "-coding")
-	Description string                // Endpoint description
-	Plan        string                // Billing plan: pay-per-use, subscription, etc. (optional)
-	Tools       map[string]ToolConfig // Configuration for each tool
+	Name        string                `yaml:"name"`                  // Endpoint name: general, coding, premium, etc.
+	Suffix      string                `yaml:"suffix,omitempty"`      // Suggested config name suffix (e.g., "-coding")
+	Description string                `yaml:"description,omitempty"` // Endpoint description
+	Plan        string                `yaml:"plan,omitempty"`        // Billing plan: pay-per-use, subscription, etc. (optional)
+	Tools       map[string]ToolConfig `yaml:"tools,omitempty"`       // Configuration for each tool
+	Region      string                `yaml:"region,omitempty"`      // Region served by Tools (e.g. "cn"); empty for single-region providers
+	// Regions holds per-region tool overrides merged over Tools, e.g. intl -> codex -> {base_url: ...}
+	Regions map[string]map[string]ToolConfig `yaml:"regions,omitempty"`
 }
 
 // ToolConfig represents tool-specific configuration
 type ToolConfig struct {
-	BaseURL    string            // API endpoint URL
-	Model      string            // Default model
-	Timeout    int               // Timeout in milliseconds
-	EnvKeyName string            // Provider-specific environment variable name for API key (e.g., "GLM_API_KEY")
-	Env        map[string]string // Environment variables (user-visible and editable)
+	BaseURL    string            `yaml:"base_url,omitempty"`     // API endpoint URL
+	Model      string            `yaml:"model,omitempty"`        // Default model
+	Timeout    int               `yaml:"timeout,omitempty"`      // Timeout in milliseconds
+	EnvKeyName string            `yaml:"env_key_name,omitempty"` // Provider-specific environment variable name for API key (e.g., "GLM_API_KEY")
+	Env        map[string]string `yaml:"env,omitempty"`          // Environment variables (user-visible and editable)
+
+	Models map[string]string `yaml:"models,omitempty"` // Model tiers (fast, balanced, strong) and aliases -> model IDs
+
+	// Codex model provider wiring
+	DisplayName string            `yaml:"display_name,omitempty"` // Name codex shows for the provider
+	WireAPI     string            `yaml:"wire_api,omitempty"`     // Wire protocol: chat or responses
+	QueryParams map[string]string `yaml:"query_params,omitempty"` // Extra query parameters on every request
+	Headers     map[string]string `yaml:"headers,omitempty"`      // Extra HTTP headers on every request
 }
 
+// Model tiers shared by a provider's tools
+var (
+	deepseekModelTiers = map[string]string{TierFast: "deepseek-chat", TierBalanced: "deepseek-chat", TierStrong: "deepseek-reasoner"}
+	glmModelTiers      = map[string]string{TierFast: "glm-4.5-air", TierBalanced: "glm-4.6", TierStrong: "glm-4.6"}
+	qwenModelTiers     = map[string]string{TierFast: "qwen3-coder-plus", TierBalanced: "qwen3-coder-plus", TierStrong: "qwen3-max"}
+)
+
 // builtinProviders contains all builtin provider information with multi-endpoint support
 var builtinProviders = map[string]BuiltinProviderInfo{
 	"deepseek": {
@@ -41,6 +63,13 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 		DisplayName: "DeepSeek AI",
 		Description: "DeepSeek AI - High-performance large language model",
 		Website:     "https://www.deepseek.com",
+		KeyPattern:  `sk-[0-9a-f]{32}`,
+		Models: []ModelInfo{
+			{ID: "deepseek-chat", ContextWindow: 128000, MaxOutputTokens: 8192, Tools: true, InputPrice: 0.28, OutputPrice: 0.42},
+			{ID: "deepseek-reasoner", ContextWindow: 128000, MaxOutputTokens: 64000, Tools: true, InputPrice: 0.28, OutputPrice: 0.42},
+			// Merged into deepseek-chat with DeepSeek-V2.5; the ID is kept as an alias of it
+			{ID: "deepseek-coder", ContextWindow: 128000, MaxOutputTokens: 8192, Tools: true, InputPrice: 0.28, OutputPrice: 0.42, Deprecated: "2024-09-05", Replacement: "deepseek-chat"},
+		},
 		Endpoints: []EndpointPreset{
 			{
 				Name:        "default",
@@ -54,12 +83,15 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 						Env: map[string]string{
 							"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC": "1",
 						},
+						Models: deepseekModelTiers,
 					},
 					"codex": {
-						BaseURL:    "https://api.deepseek.com/v1",
-						Model:      "deepseek-chat",
-						Timeout:    60000,
-						EnvKeyName: "DEEPSEEK_API_KEY",
+						BaseURL:     "https://api.deepseek.com/v1",
+						Model:       "deepseek-chat",
+						Timeout:     60000,
+						DisplayName: "DeepSeek",
+						EnvKeyName:  "DEEPSEEK_API_KEY",
+						Models:      deepseekModelTiers,
 					},
 				},
 			},
@@ -70,6 +102,13 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 		DisplayName: "Moonshot AI KIMI",
 		Description: "Moonshot AI KIMI - Intelligent conversation assistant",
 		Website:     "https://www.moonshot.cn",
+		KeyPattern:  `sk-[A-Za-z0-9]{48}`,
+		Models: []ModelInfo{
+			{ID: "kimi-k2-turbo-preview", ContextWindow: 262144, MaxOutputTokens: 32768, Tools: true, InputPrice: 1.15, OutputPrice: 8.00},
+			{ID: "kimi-k2-0905-preview", ContextWindow: 262144, MaxOutputTokens: 32768, Tools: true, InputPrice: 0.60, OutputPrice: 2.50},
+			{ID: "kimi-k2-0711-preview", ContextWindow: 131072, MaxOutputTokens: 32768, Tools: true, InputPrice: 0.60, OutputPrice: 2.50},
+			{ID: "moonshot-v1-128k-vision-preview", ContextWindow: 131072, MaxOutputTokens: 32768, Tools: true, Vision: true, InputPrice: 1.40, OutputPrice: 4.20},
+		},
 		Endpoints: []EndpointPreset{
 			{
 				Name:        "default",
@@ -85,10 +124,18 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 						},
 					},
 					"codex": {
-						BaseURL:    "https://api.moonshot.cn/v1",
-						Model:      "kimi-k2-turbo-preview",
-						Timeout:    60000,
-						EnvKeyName: "KIMI_API_KEY",
+						BaseURL:     "https://api.moonshot.cn/v1",
+						Model:       "kimi-k2-turbo-preview",
+						Timeout:     60000,
+						DisplayName: "Kimi",
+						EnvKeyName:  "KIMI_API_KEY",
+					},
+				},
+				Region: RegionCN,
+				Regions: map[string]map[string]ToolConfig{
+					RegionIntl: {
+						"claude-code": {BaseURL: "https://api.moonshot.ai/anthropic"},
+						"codex":       {BaseURL: "https://api.moonshot.ai/v1"},
 					},
 				},
 			},
@@ -99,6 +146,13 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 		DisplayName: "Zhipu GLM",
 		Description: "Zhipu GLM - Chinese large language model",
 		Website:     "https://www.bigmodel.cn",
+		KeyPattern:  `[0-9a-f]{32}\.[A-Za-z0-9]{16}`,
+		Models: []ModelInfo{
+			{ID: "glm-4.6", ContextWindow: 200000, MaxOutputTokens: 128000, Tools: true, InputPrice: 0.60, OutputPrice: 2.20},
+			{ID: "glm-4.5", ContextWindow: 128000, MaxOutputTokens: 96000, Tools: true, InputPrice: 0.60, OutputPrice: 2.20},
+			{ID: "glm-4.5-air", ContextWindow: 128000, MaxOutputTokens: 96000, Tools: true, InputPrice: 0.20, OutputPrice: 1.10},
+			{ID: "glm-4.5v", ContextWindow: 64000, MaxOutputTokens: 16384, Tools: true, Vision: true, InputPrice: 0.60, OutputPrice: 1.80},
+		},
 		Endpoints: []EndpointPreset{
 			{
 				Name:        "general",
@@ -111,17 +165,24 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 						Model:   "glm-4.6",
 						Timeout: int(constants.GLMTimeout.Milliseconds()),
 						Env: map[string]string{
-							"ANTHROPIC_DEFAULT_HAIKU_MODEL":            "glm-4.5-air",
-							"ANTHROPIC_DEFAULT_SONNET_MODEL":           "glm-4.6",
-							"ANTHROPIC_DEFAULT_OPUS_MODEL":             "glm-4.6",
 							"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC": "1",
 						},
+						Models: glmModelTiers,
 					},
 					"codex": {
-						BaseURL:    "https://open.bigmodel.cn/api/paas/v4",
-						Model:      "glm-4.6",
-						Timeout:    3000000,
-						EnvKeyName: "GLM_API_KEY",
+						BaseURL:     "https://open.bigmodel.cn/api/paas/v4",
+						Model:       "glm-4.6",
+						Timeout:     3000000,
+						DisplayName: "GLM",
+						EnvKeyName:  "GLM_API_KEY",
+						Models:      glmModelTiers,
+					},
+				},
+				Region: RegionCN,
+				Regions: map[string]map[string]ToolConfig{
+					RegionIntl: {
+						"claude-code": {BaseURL: "https://api.z.ai/api/anthropic"},
+						"codex":       {BaseURL: "https://api.z.ai/api/paas/v4"},
 					},
 				},
 			},
@@ -136,17 +197,24 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 						Model:   "glm-4.6",
 						Timeout: int(constants.GLMTimeout.Milliseconds()),
 						Env: map[string]string{
-							"ANTHROPIC_DEFAULT_HAIKU_MODEL":            "glm-4.5-air",
-							"ANTHROPIC_DEFAULT_SONNET_MODEL":           "glm-4.6",
-							"ANTHROPIC_DEFAULT_OPUS_MODEL":             "glm-4.6",
 							"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC": "1",
 						},
+						Models: glmModelTiers,
 					},
 					"codex": {
-						BaseURL:    "https://open.bigmodel.cn/api/coding/paas/v4",
-						Model:      "glm-4.6",
-						Timeout:    int(constants.GLMCodingTimeout.Milliseconds()),
-						EnvKeyName: "GLM_API_KEY",
+						BaseURL:     "https://open.bigmodel.cn/api/coding/paas/v4",
+						Model:       "glm-4.6",
+						Timeout:     int(constants.GLMCodingTimeout.Milliseconds()),
+						DisplayName: "GLM",
+						EnvKeyName:  "GLM_API_KEY",
+						Models:      glmModelTiers,
+					},
+				},
+				Region: RegionCN,
+				Regions: map[string]map[string]ToolConfig{
+					RegionIntl: {
+						"claude-code": {BaseURL: "https://api.z.ai/api/anthropic"},
+						"codex":       {BaseURL: "https://api.z.ai/api/coding/paas/v4"},
 					},
 				},
 			},
@@ -157,6 +225,12 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 		DisplayName: "Alibaba Cloud Qwen",
 		Description: "Alibaba Cloud Qwen - Enterprise-level large language model",
 		Website:     "https://www.aliyun.com/product/dashscope",
+		KeyPattern:  `sk-[0-9a-f]{32}`,
+		Models: []ModelInfo{
+			{ID: "qwen3-max", ContextWindow: 262144, MaxOutputTokens: 65536, Tools: true, InputPrice: 1.20, OutputPrice: 6.00},
+			{ID: "qwen3-coder-plus", ContextWindow: 1000000, MaxOutputTokens: 65536, Tools: true, InputPrice: 1.00, OutputPrice: 5.00},
+			{ID: "qwen3-vl-plus", ContextWindow: 262144, MaxOutputTokens: 32768, Tools: true, Vision: true, InputPrice: 0.20, OutputPrice: 1.60},
+		},
 		Endpoints: []EndpointPreset{
 			{
 				Name:        "default",
@@ -170,12 +244,141 @@ var builtinProviders = map[string]BuiltinProviderInfo{
 						Env: map[string]string{
 							"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC": "1",
 						},
+						Models: qwenModelTiers,
+					},
+					"codex": {
+						BaseURL:     "https://dashscope.aliyuncs.com/compatible-mode/v1",
+						Model:       "qwen3-max",
+						Timeout:     60000,
+						DisplayName: "Qwen",
+						EnvKeyName:  "QWEN_API_KEY",
+						Models:      qwenModelTiers,
+					},
+				},
+				Region: RegionCN,
+				Regions: map[string]map[string]ToolConfig{
+					RegionIntl: {
+						"claude-code": {BaseURL: "https://dashscope-intl.aliyuncs.com/api/v2/apps/claude-code-proxy"},
+						"codex":       {BaseURL: "https://dashscope-intl.aliyuncs.com/compatible-mode/v1"},
+					},
+				},
+			},
+		},
+	},
+	"ollama": {
+		Name:        "ollama",
+		DisplayName: "Ollama",
+		Description: "Ollama - Local models served on this machine",
+		Website:     "https://ollama.com",
+		Local:       true,
+		Endpoints: []EndpointPreset{
+			{
+				Name:        "default",
+				Suffix:      "",
+				Description: "Local server on the default port",
+				Tools: map[string]ToolConfig{
+					"codex": {
+						BaseURL: "http://localhost:11434/v1",
+						Model:   "gpt-oss:20b",
+						Timeout: int(constants.LocalTimeout.Milliseconds()),
 					},
+				},
+			},
+		},
+	},
+	"lmstudio": {
+		Name:        "lmstudio",
+		DisplayName: "LM Studio",
+		Description: "LM Studio - Local models served on this machine",
+		Website:     "https://lmstudio.ai",
+		Local:       true,
+		Endpoints: []EndpointPreset{
+			{
+				Name:        "default",
+				Suffix:      "",
+				Description: "Local server on the default port",
+				Tools: map[string]ToolConfig{
 					"codex": {
-						BaseURL:    "https://dashscope.aliyuncs.com/compatible-mode/v1",
-						Model:      "qwen3-max",
-						Timeout:    60000,
-						EnvKeyName: "QWEN_API_KEY",
+						BaseURL: "http://localhost:1234/v1",
+						Model:   "openai/gpt-oss-20b",
+						Timeout: int(constants.LocalTimeout.Milliseconds()),
+					},
+				},
+			},
+		},
+	},
+	"llamacpp": {
+		Name:        "llamacpp",
+		DisplayName: "llama.cpp server",
+		Description: "llama.cpp server - Local models served on this machine",
+		Website:     "https://github.com/ggml-org/llama.cpp",
+		Local:       true,
+		Endpoints: []EndpointPreset{
+			{
+				Name:        "default",
+				Suffix:      "",
+				Description: "Local server on the default port (serves the loaded model under any name)",
+				Tools: map[string]ToolConfig{
+					"codex": {
+						BaseURL: "http://localhost:8080/v1",
+						Model:   "default",
+						Timeout: int(constants.LocalTimeout.Milliseconds()),
+					},
+				},
+			},
+		},
+	},
+	"bedrock": {
+		Name:        "bedrock",
+		DisplayName: "AWS Bedrock",
+		Description: "Claude on AWS Bedrock - Authenticates with AWS credentials",
+		Website:     "https://aws.amazon.com/bedrock/claude",
+		Credentials: "aws",
+		Endpoints: []EndpointPreset{
+			{
+				Name:        "default",
+				Suffix:      "",
+				Description: "Claude Code through Bedrock (cross-region inference profile)",
+				Tools: map[string]ToolConfig{
+					"claude-code": {
+						Model:   "us.anthropic.claude-sonnet-4-5-20250929-v1:0",
+						Timeout: int(constants.DefaultTimeoutMS),
+						Env: map[string]string{
+							"CLAUDE_CODE_USE_BEDROCK": "1",
+						},
+						Models: map[string]string{
+							TierFast:     "us.anthropic.claude-haiku-4-5-20251001-v1:0",
+							TierBalanced: "us.anthropic.claude-sonnet-4-5-20250929-v1:0",
+							TierStrong:   "us.anthropic.claude-opus-4-1-20250805-v1:0",
+						},
+					},
+				},
+			},
+		},
+	},
+	"vertex": {
+		Name:        "vertex",
+		DisplayName: "Google Vertex AI",
+		Description: "Claude on Google Vertex AI - Authenticates with a GCP project",
+		Website:     "https://cloud.google.com/vertex-ai/generative-ai/docs/partner-models/use-claude",
+		Credentials: "gcp",
+		Endpoints: []EndpointPreset{
+			{
+				Name:        "default",
+				Suffix:      "",
+				Description: "Claude Code through Vertex AI",
+				Tools: map[string]ToolConfig{
+					"claude-code": {
+						Model:   "claude-sonnet-4-5@20250929",
+						Timeout: int(constants.DefaultTimeoutMS),
+						Env: map[string]string{
+							"CLAUDE_CODE_USE_VERTEX": "1",
+						},
+						Models: map[string]string{
+							TierFast:     "claude-haiku-4-5@20251001",
+							TierBalanced: "claude-sonnet-4-5@20250929",
+							TierStrong:   "claude-opus-4-1@20250805",
+						},
 					},
 				},
 			},
@@ -230,6 +433,25 @@ func GetDefaultEndpoint(providerName string) (*EndpointPreset, error) {
 	return &provider.Endpoints[0], nil
 }
 
+// ResolveBuiltinEndpoint finds the endpoint preset for a builtin provider name
+// A plain name selects the default endpoint, a suffixed name (e.g. "glm-coding") its endpoint.
+func ResolveBuiltinEndpoint(name string) (*EndpointPreset, bool) {
+	if info, ok := builtinProviders[name]; ok {
+		if len(info.Endpoints) == 0 {
+			return nil, false
+		}
+		return &info.Endpoints[0], true
+	}
+	for _, info := range builtinProviders {
+		for i := range info.Endpoints {
+			if ep := &info.Endpoints[i]; ep.Suffix != "" && name == info.Name+ep.Suffix {
+				return ep, true
+			}
+		}
+	}
+	return nil, false
+}
+
 // SuggestProviderName suggests a config name for a provider endpoint
 func SuggestProviderName(providerName, endpointName string, existingNames map[string]bool) (string, error) {
 	endpoint, err := GetProviderEndpoint(providerName, endpointName)
@@ -315,8 +537,29 @@ func FormatProviderInfo(providerName string) (string, error) {
 			sb.WriteString(fmt.Sprintf("  ├─ %s\n", toolName))
 			sb.WriteString(fmt.Sprintf("  │  ├─ URL: %s\n", toolCfg.BaseURL))
 			sb.WriteString(fmt.Sprintf("  │  ├─ Model: %s\n", toolCfg.Model))
+			if len(toolCfg.Models) > 0 {
+				var tiers []string
+				for _, tier := range ModelTiers {
+					if model, ok := toolCfg.Models[tier]; ok {
+						tiers = append(tiers, tier+"="+model)
+					}
+				}
+				sb.WriteString(fmt.Sprintf("  │  ├─ Tiers: %s\n", strings.Join(tiers, ", ")))
+			}
 			sb.WriteString(fmt.Sprintf("  │  └─ Timeout: %dms\n", toolCfg.Timeout))
 		}
+
+		for _, region := range ep.RegionNames() {
+			if region == ep.Region {
+				sb.WriteString(fmt.Sprintf("  Region %s: URLs above\n", region))
+				continue
+			}
+			for toolName, toolCfg := range ep.Regions[region] {
+				if toolCfg.BaseURL != "" {
+					sb.WriteString(fmt.Sprintf("  Region %s: %s URL: %s\n", region, toolName, toolCfg.BaseURL))
+				}
+			}
+		}
 	}
 
 	return sb.String(), nil
@@ -0,0 +1,443 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SyncedCatalogFile is the providers.d file written by 'aim provider sync'
+// It sorts first so hand-written definitions in providers.d override it.
+const SyncedCatalogFile = "00-catalog.yaml"
+
+// Catalog is a set of provider definitions from a providers.d file or a sync manifest
+type Catalog struct {
+	Version   int                   `yaml:"version,omitempty"`
+	Providers []BuiltinProviderInfo `yaml:"providers"`
+}
+
+// compiledProviders keeps the compiled-in definitions so external ones can be re-merged from scratch
+var compiledProviders = cloneProviders(builtinProviders)
+
+// ParseCatalog parses a catalog document
+// Files holding a single provider definition (top-level "name:") are accepted as well.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid provider catalog: %w", err)
+	}
+
+	if len(catalog.Providers) == 0 {
+		var single BuiltinProviderInfo
+		if err := yaml.Unmarshal(data, &single); err == nil && single.Name != "" {
+			catalog.Providers = []BuiltinProviderInfo{single}
+		}
+	}
+
+	for i, p := range catalog.Providers {
+		if p.Name == "" {
+			return nil, fmt.Errorf("provider #%d has no name", i+1)
+		}
+		for j, ep := range p.Endpoints {
+			if ep.Name == "" {
+				return nil, fmt.Errorf("provider '%s' endpoint #%d has no name", p.Name, j+1)
+			}
+		}
+		for j, m := range p.Models {
+			if m.ID == "" {
+				return nil, fmt.Errorf("provider '%s' model #%d has no id", p.Name, j+1)
+			}
+		}
+	}
+
+	return &catalog, nil
+}
+
+// ReadCatalogDir reads every *.yaml and *.yml file in dir, keyed by file name
+// Unreadable or invalid files are skipped and reported in the returned error.
+func ReadCatalogDir(dir string) (map[string]*Catalog, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	catalogs := make(map[string]*Catalog)
+	var errs []error
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		catalog, err := ParseCatalog(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		catalogs[entry.Name()] = catalog
+	}
+
+	return catalogs, errors.Join(errs...)
+}
+
+// MergeCatalogs merges catalogs over the compiled-in providers, in file name order
+func MergeCatalogs(catalogs map[string]*Catalog) map[string]BuiltinProviderInfo {
+	result := cloneProviders(compiledProviders)
+
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, def := range catalogs[name].Providers {
+			if existing, ok := result[def.Name]; ok {
+				result[def.Name] = mergeProvider(existing, def)
+			} else {
+				result[def.Name] = cloneProvider(def)
+			}
+		}
+	}
+
+	return result
+}
+
+// LoadDefinitions replaces the builtin registry with the compiled-in providers merged with dir's definitions
+func LoadDefinitions(dir string) error {
+	catalogs, err := ReadCatalogDir(dir)
+	builtinProviders = MergeCatalogs(catalogs)
+	return err
+}
+
+// mergeProvider overlays a definition on an existing provider
+// Non-empty fields override, endpoints merge by name and models by ID.
+func mergeProvider(base, def BuiltinProviderInfo) BuiltinProviderInfo {
+	result := cloneProvider(base)
+
+	if def.DisplayName != "" {
+		result.DisplayName = def.DisplayName
+	}
+	if def.Description != "" {
+		result.Description = def.Description
+	}
+	if def.Website != "" {
+		result.Website = def.Website
+	}
+	if def.KeyPattern != "" {
+		result.KeyPattern = def.KeyPattern
+	}
+	if def.Local {
+		result.Local = true
+	}
+	if def.Credentials != "" {
+		result.Credentials = def.Credentials
+	}
+
+	for _, ep := range def.Endpoints {
+		merged := false
+		for i := range result.Endpoints {
+			if result.Endpoints[i].Name == ep.Name {
+				result.Endpoints[i] = mergeEndpoint(result.Endpoints[i], ep)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			result.Endpoints = append(result.Endpoints, cloneEndpoint(ep))
+		}
+	}
+
+	for _, m := range def.Models {
+		merged := false
+		for i := range result.Models {
+			if result.Models[i].ID == m.ID {
+				result.Models[i] = m
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			result.Models = append(result.Models, m)
+		}
+	}
+
+	return result
+}
+
+// mergeEndpoint overlays an endpoint definition; tool configs merge field by field
+func mergeEndpoint(base, def EndpointPreset) EndpointPreset {
+	result := cloneEndpoint(base)
+
+	if def.Suffix != "" {
+		result.Suffix = def.Suffix
+	}
+	if def.Description != "" {
+		result.Description = def.Description
+	}
+	if def.Plan != "" {
+		result.Plan = def.Plan
+	}
+
+	if def.Region != "" {
+		result.Region = def.Region
+	}
+
+	result.Tools = mergeTools(result.Tools, def.Tools)
+	for region, tools := range def.Regions {
+		if result.Regions == nil {
+			result.Regions = make(map[string]map[string]ToolConfig)
+		}
+		result.Regions[region] = mergeTools(result.Regions[region], tools)
+	}
+
+	return result
+}
+
+// mergeTools overlays tool configs field by field; base is modified and returned
+func mergeTools(base, def map[string]ToolConfig) map[string]ToolConfig {
+	for toolName, toolDef := range def {
+		if base == nil {
+			base = make(map[string]ToolConfig)
+		}
+		base[toolName] = mergeToolConfig(base[toolName], toolDef)
+	}
+	return base
+}
+
+// mergeToolConfig overlays non-empty fields of def on base
+func mergeToolConfig(base, def ToolConfig) ToolConfig {
+	if def.BaseURL != "" {
+		base.BaseURL = def.BaseURL
+	}
+	if def.Model != "" {
+		base.Model = def.Model
+	}
+	if def.Timeout > 0 {
+		base.Timeout = def.Timeout
+	}
+	if def.EnvKeyName != "" {
+		base.EnvKeyName = def.EnvKeyName
+	}
+	if def.DisplayName != "" {
+		base.DisplayName = def.DisplayName
+	}
+	if def.WireAPI != "" {
+		base.WireAPI = def.WireAPI
+	}
+	base.Env = mergeStringMap(base.Env, def.Env)
+	base.QueryParams = mergeStringMap(base.QueryParams, def.QueryParams)
+	base.Headers = mergeStringMap(base.Headers, def.Headers)
+	base.Models = mergeStringMap(base.Models, def.Models)
+	return base
+}
+
+// mergeStringMap returns a copy of base with def's entries overlaid, or base when def is empty
+func mergeStringMap(base, def map[string]string) map[string]string {
+	if len(def) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(def))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range def {
+		merged[k] = v
+	}
+	return merged
+}
+
+// ProviderChange describes how one provider differs between two registries
+type ProviderChange struct {
+	Provider string
+	Kind     string   // added, changed or removed
+	Details  []string // e.g. "endpoint coding changed", "model glm-4.6 added"
+}
+
+// DiffProviders compares two registries, ordered by provider name
+func DiffProviders(before, after map[string]BuiltinProviderInfo) []ProviderChange {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []ProviderChange
+	for _, name := range sorted {
+		old, hadOld := before[name]
+		cur, hasNew := after[name]
+
+		switch {
+		case !hadOld:
+			changes = append(changes, ProviderChange{Provider: name, Kind: "added", Details: []string{"endpoints: " + strings.Join(endpointNames(cur), ", ")}})
+		case !hasNew:
+			changes = append(changes, ProviderChange{Provider: name, Kind: "removed"})
+		case !reflect.DeepEqual(old, cur):
+			changes = append(changes, ProviderChange{Provider: name, Kind: "changed", Details: diffProvider(old, cur)})
+		}
+	}
+
+	return changes
+}
+
+// diffProvider lists field, endpoint and model level differences
+func diffProvider(old, cur BuiltinProviderInfo) []string {
+	var details []string
+
+	if old.DisplayName != cur.DisplayName || old.Description != cur.Description || old.Website != cur.Website || old.KeyPattern != cur.KeyPattern {
+		details = append(details, "metadata changed")
+	}
+	if old.Local != cur.Local {
+		details = append(details, fmt.Sprintf("local %t -> %t", old.Local, cur.Local))
+	}
+	if old.Credentials != cur.Credentials {
+		details = append(details, fmt.Sprintf("credentials %q -> %q", old.Credentials, cur.Credentials))
+	}
+
+	oldEndpoints := make(map[string]EndpointPreset)
+	for _, ep := range old.Endpoints {
+		oldEndpoints[ep.Name] = ep
+	}
+	for _, ep := range cur.Endpoints {
+		prev, ok := oldEndpoints[ep.Name]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("endpoint %s added", ep.Name))
+		case !reflect.DeepEqual(prev, ep):
+			details = append(details, fmt.Sprintf("endpoint %s changed", ep.Name))
+		}
+		delete(oldEndpoints, ep.Name)
+	}
+	for _, name := range sortedEndpointKeys(oldEndpoints) {
+		details = append(details, fmt.Sprintf("endpoint %s removed", name))
+	}
+
+	oldModels := make(map[string]ModelInfo)
+	for _, m := range old.Models {
+		oldModels[m.ID] = m
+	}
+	for _, m := range cur.Models {
+		prev, ok := oldModels[m.ID]
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("model %s added", m.ID))
+		case prev != m:
+			details = append(details, fmt.Sprintf("model %s changed", m.ID))
+		}
+		delete(oldModels, m.ID)
+	}
+	removedModels := make([]string, 0, len(oldModels))
+	for id := range oldModels {
+		removedModels = append(removedModels, id)
+	}
+	sort.Strings(removedModels)
+	for _, id := range removedModels {
+		details = append(details, fmt.Sprintf("model %s removed", id))
+	}
+
+	return details
+}
+
+// endpointNames returns a provider's endpoint names in definition order
+func endpointNames(p BuiltinProviderInfo) []string {
+	names := make([]string, len(p.Endpoints))
+	for i, ep := range p.Endpoints {
+		names[i] = ep.Name
+	}
+	return names
+}
+
+// sortedEndpointKeys returns endpoint map keys in stable order
+func sortedEndpointKeys(m map[string]EndpointPreset) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cloneProviders deep-copies a registry
+func cloneProviders(src map[string]BuiltinProviderInfo) map[string]BuiltinProviderInfo {
+	dst := make(map[string]BuiltinProviderInfo, len(src))
+	for name, p := range src {
+		dst[name] = cloneProvider(p)
+	}
+	return dst
+}
+
+// cloneProvider deep-copies a provider definition
+func cloneProvider(p BuiltinProviderInfo) BuiltinProviderInfo {
+	result := p
+	if p.Endpoints != nil {
+		result.Endpoints = make([]EndpointPreset, len(p.Endpoints))
+		for i, ep := range p.Endpoints {
+			result.Endpoints[i] = cloneEndpoint(ep)
+		}
+	}
+	result.Models = append([]ModelInfo(nil), p.Models...)
+	return result
+}
+
+// cloneEndpoint deep-copies an endpoint preset
+func cloneEndpoint(ep EndpointPreset) EndpointPreset {
+	result := ep
+	result.Tools = cloneTools(ep.Tools)
+	if ep.Regions != nil {
+		result.Regions = make(map[string]map[string]ToolConfig, len(ep.Regions))
+		for region, tools := range ep.Regions {
+			result.Regions[region] = cloneTools(tools)
+		}
+	}
+	return result
+}
+
+// cloneTools deep-copies a tool config map
+func cloneTools(tools map[string]ToolConfig) map[string]ToolConfig {
+	if tools == nil {
+		return nil
+	}
+	result := make(map[string]ToolConfig, len(tools))
+	for name, toolCfg := range tools {
+		toolCfg.Env = cloneStringMap(toolCfg.Env)
+		toolCfg.QueryParams = cloneStringMap(toolCfg.QueryParams)
+		toolCfg.Headers = cloneStringMap(toolCfg.Headers)
+		toolCfg.Models = cloneStringMap(toolCfg.Models)
+		result[name] = toolCfg
+	}
+	return result
+}
+
+// cloneStringMap copies a string map, preserving nil
+func cloneStringMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+	return result
+}
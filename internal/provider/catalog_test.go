@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeLocalAndCredentials(t *testing.T) {
+	before := MergeCatalogs(nil)
+	after := MergeCatalogs(map[string]*Catalog{
+		"10-local.yaml": {Providers: []BuiltinProviderInfo{{Name: "deepseek", Local: true, Credentials: "aws"}}},
+	})
+
+	merged := after["deepseek"]
+	if !merged.Local || merged.Credentials != "aws" {
+		t.Errorf("merged deepseek local = %v, credentials = %q", merged.Local, merged.Credentials)
+	}
+	if len(merged.Endpoints) == 0 {
+		t.Error("merge dropped the builtin endpoints")
+	}
+
+	var details []string
+	for _, change := range DiffProviders(before, after) {
+		if change.Provider == "deepseek" {
+			details = change.Details
+		}
+	}
+	want := []string{`local false -> true`, `credentials "" -> "aws"`}
+	if !reflect.DeepEqual(details, want) {
+		t.Errorf("deepseek details = %v, want %v", details, want)
+	}
+}
+
+func TestMergeCatalogs(t *testing.T) {
+	merged := MergeCatalogs(map[string]*Catalog{
+		// Later files win, whatever order the map iterates in
+		"20-override.yaml": {Providers: []BuiltinProviderInfo{{Name: "deepseek", DisplayName: "Second"}}},
+		SyncedCatalogFile: {Providers: []BuiltinProviderInfo{
+			{
+				Name:        "deepseek",
+				DisplayName: "First",
+				Endpoints: []EndpointPreset{
+					{Name: "default", Tools: map[string]ToolConfig{"codex": {Model: "deepseek-reasoner", Headers: map[string]string{"X-A": "1"}}}},
+					{Name: "beta", Tools: map[string]ToolConfig{"codex": {BaseURL: "https://beta.deepseek.com/v1"}}},
+				},
+				Models: []ModelInfo{
+					{ID: "deepseek-chat", ContextWindow: 1000},
+					{ID: "deepseek-v4", Tools: true},
+				},
+			},
+			{Name: "newco", Endpoints: []EndpointPreset{{Name: "default"}}},
+		}},
+	})
+
+	deepseek := merged["deepseek"]
+	if deepseek.DisplayName != "Second" {
+		t.Errorf("display name = %q, want the later file to win", deepseek.DisplayName)
+	}
+	if deepseek.Description == "" || deepseek.KeyPattern == "" {
+		t.Error("empty fields in a definition cleared builtin ones")
+	}
+
+	if got := endpointNames(deepseek); !reflect.DeepEqual(got, []string{"default", "beta"}) {
+		t.Errorf("endpoints = %v, want [default beta]", got)
+	}
+	codex := deepseek.Endpoints[0].Tools["codex"]
+	if codex.Model != "deepseek-reasoner" || codex.Headers["X-A"] != "1" {
+		t.Errorf("codex = %+v, want model and headers overridden", codex)
+	}
+	if codex.BaseURL != "https://api.deepseek.com/v1" || codex.EnvKeyName != "DEEPSEEK_API_KEY" {
+		t.Errorf("codex = %+v, want unset fields kept", codex)
+	}
+	if _, ok := deepseek.Endpoints[0].Tools["claude-code"]; !ok {
+		t.Error("claude-code tool of the merged endpoint was dropped")
+	}
+
+	models := make(map[string]ModelInfo)
+	for _, m := range deepseek.Models {
+		models[m.ID] = m
+	}
+	if models["deepseek-chat"].ContextWindow != 1000 || models["deepseek-chat"].OutputPrice != 0 {
+		t.Errorf("deepseek-chat = %+v, want it replaced by ID", models["deepseek-chat"])
+	}
+	if _, ok := models["deepseek-v4"]; !ok || len(deepseek.Models) != len(compiledProviders["deepseek"].Models)+1 {
+		t.Errorf("models = %v, want deepseek-v4 appended", deepseek.Models)
+	}
+
+	if _, ok := merged["newco"]; !ok {
+		t.Error("new provider was not added")
+	}
+
+	// Merging never modifies the compiled-in definitions
+	if compiledProviders["deepseek"].DisplayName != "DeepSeek AI" || len(compiledProviders["deepseek"].Endpoints) != 1 {
+		t.Error("merge modified the compiled-in providers")
+	}
+	if compiledProviders["deepseek"].Endpoints[0].Tools["codex"].Headers != nil {
+		t.Error("merge modified compiled-in tool headers")
+	}
+}
+
+func TestDiffProviders(t *testing.T) {
+	before := map[string]BuiltinProviderInfo{
+		"same": {Name: "same", Endpoints: []EndpointPreset{{Name: "default"}}},
+		"gone": {Name: "gone"},
+		"edit": {
+			Name:      "edit",
+			Endpoints: []EndpointPreset{{Name: "default", Plan: "a"}, {Name: "old"}, {Name: "kept"}},
+			Models:    []ModelInfo{{ID: "m1"}, {ID: "m2"}, {ID: "m3"}},
+		},
+	}
+	after := map[string]BuiltinProviderInfo{
+		"same": {Name: "same", Endpoints: []EndpointPreset{{Name: "default"}}},
+		"edit": {
+			Name:        "edit",
+			DisplayName: "Edit",
+			Endpoints:   []EndpointPreset{{Name: "default", Plan: "b"}, {Name: "kept"}, {Name: "new"}},
+			Models:      []ModelInfo{{ID: "m1"}, {ID: "m2", Tools: true}, {ID: "m4"}},
+		},
+		"added": {Name: "added", Endpoints: []EndpointPreset{{Name: "default"}, {Name: "coding"}}},
+	}
+
+	want := []ProviderChange{
+		{Provider: "added", Kind: "added", Details: []string{"endpoints: default, coding"}},
+		{Provider: "edit", Kind: "changed", Details: []string{
+			"metadata changed",
+			"endpoint default changed",
+			"endpoint new added",
+			"endpoint old removed",
+			"model m2 changed",
+			"model m4 added",
+			"model m3 removed",
+		}},
+		{Provider: "gone", Kind: "removed"},
+	}
+	if got := DiffProviders(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffProviders() = %+v, want %+v", got, want)
+	}
+
+	if got := DiffProviders(before, before); len(got) != 0 {
+		t.Errorf("DiffProviders(same) = %+v, want none", got)
+	}
+}
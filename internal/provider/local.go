@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"net"
+	"net/url"
+	"sort"
+)
+
+// IsLocalProvider reports whether a provider is a builtin local server (handles endpoint-suffixed names)
+func IsLocalProvider(name string) bool {
+	info, ok := ResolveBuiltinProvider(name)
+	return ok && info.Local
+}
+
+// CredentialType returns the structured credential type a provider authenticates with, "" for API keys
+func CredentialType(name string) string {
+	info, ok := ResolveBuiltinProvider(name)
+	if !ok {
+		return ""
+	}
+	return info.Credentials
+}
+
+// LocalProviderNames returns the builtin local providers in stable order
+func LocalProviderNames() []string {
+	var names []string
+	for name, info := range builtinProviders {
+		if info.Local {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsLoopbackURL reports whether a base URL points at this machine
+func IsLoopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ModelInfo describes a model served by a builtin provider
+type ModelInfo struct {
+	ID              string  `yaml:"id"`                          // Model ID as sent to the API
+	ContextWindow   int     `yaml:"context_window,omitempty"`    // Maximum input + output tokens
+	MaxOutputTokens int     `yaml:"max_output_tokens,omitempty"` // Maximum output tokens per request
+	Tools           bool    `yaml:"tools,omitempty"`             // Supports tool use / function calling
+	Vision          bool    `yaml:"vision,omitempty"`            // Accepts image input
+	InputPrice      float64 `yaml:"input_price,omitempty"`       // USD per million input tokens (list price, may vary by tier)
+	OutputPrice     float64 `yaml:"output_price,omitempty"`      // USD per million output tokens (list price, may vary by tier)
+	Deprecated      string  `yaml:"deprecated,omitempty"`        // Deprecation date (YYYY-MM-DD), empty when current
+	Replacement     string  `yaml:"replacement,omitempty"`       // Suggested replacement for deprecated models (optional)
+}
+
+// Capability names accepted by ModelFilter
+const (
+	CapabilityTools  = "tools"
+	CapabilityVision = "vision"
+)
+
+// HasCapability reports whether the model supports a named capability
+func (m *ModelInfo) HasCapability(capability string) bool {
+	switch capability {
+	case CapabilityTools:
+		return m.Tools
+	case CapabilityVision:
+		return m.Vision
+	default:
+		return false
+	}
+}
+
+// DeprecationDate parses the deprecation date, reporting false when the model is current
+func (m *ModelInfo) DeprecationDate() (time.Time, bool) {
+	if m.Deprecated == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", m.Deprecated)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// CatalogModel is a model together with the builtin provider serving it
+type CatalogModel struct {
+	Provider string
+	ModelInfo
+}
+
+// ModelFilter selects catalog models. Zero values match everything.
+type ModelFilter struct {
+	Provider     string   // Builtin provider name, or a config name derived from an endpoint suffix
+	Capabilities []string // All listed capabilities are required
+	MinContext   int      // Minimum context window in tokens
+}
+
+// ListModels returns catalog models matching the filter, ordered by provider then model ID
+func ListModels(filter ModelFilter) ([]CatalogModel, error) {
+	providerName := ""
+	if filter.Provider != "" {
+		info, ok := ResolveBuiltinProvider(filter.Provider)
+		if !ok {
+			return nil, fmt.Errorf("unknown builtin provider: %s", filter.Provider)
+		}
+		providerName = info.Name
+	}
+
+	for _, capability := range filter.Capabilities {
+		if capability != CapabilityTools && capability != CapabilityVision {
+			return nil, fmt.Errorf("unknown capability '%s'. Valid capabilities: %s, %s", capability, CapabilityTools, CapabilityVision)
+		}
+	}
+
+	var result []CatalogModel
+	for name, info := range builtinProviders {
+		if providerName != "" && name != providerName {
+			continue
+		}
+	models:
+		for _, m := range info.Models {
+			if m.ContextWindow < filter.MinContext {
+				continue
+			}
+			for _, capability := range filter.Capabilities {
+				if !m.HasCapability(capability) {
+					continue models
+				}
+			}
+			result = append(result, CatalogModel{Provider: name, ModelInfo: m})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Provider != result[j].Provider {
+			return result[i].Provider < result[j].Provider
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// FindModel looks up a model by ID across all builtin providers
+func FindModel(id string) (CatalogModel, bool) {
+	for name, info := range builtinProviders {
+		for _, m := range info.Models {
+			if m.ID == id {
+				return CatalogModel{Provider: name, ModelInfo: m}, true
+			}
+		}
+	}
+	return CatalogModel{}, false
+}
+
+// ResolveBuiltinProvider maps a provider name to its builtin provider
+// Config names derived from endpoint suffixes (e.g. "glm-coding") resolve to their base provider
+func ResolveBuiltinProvider(name string) (BuiltinProviderInfo, bool) {
+	if info, ok := builtinProviders[name]; ok {
+		return info, true
+	}
+	for _, info := range builtinProviders {
+		for _, ep := range info.Endpoints {
+			if ep.Suffix != "" && name == info.Name+ep.Suffix {
+				return info, true
+			}
+		}
+	}
+	return BuiltinProviderInfo{}, false
+}
+
+// ParseTokenCount parses token counts like "128k", "1m" or "200000"
+func ParseTokenCount(value string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(s, "k"):
+		multiplier = 1000
+		s = strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		multiplier = 1000000
+		s = strings.TrimSuffix(s, "m")
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid token count '%s'", value)
+	}
+	return int(n * float64(multiplier)), nil
+}
+
+// FormatTokenCount renders a token count compactly (e.g. 128K, 1M)
+func FormatTokenCount(n int) string {
+	switch {
+	case n == 0:
+		return "-"
+	case n >= 1000000 && n%1000000 == 0:
+		return fmt.Sprintf("%dM", n/1000000)
+	case n >= 1000 && n%1000 == 0:
+		return fmt.Sprintf("%dK", n/1000)
+	case n >= 1024:
+		return fmt.Sprintf("%dK", n/1024)
+	default:
+		return strconv.Itoa(n)
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Regions for providers with separate mainland China and international hosts
+const (
+	RegionCN   = "cn"
+	RegionIntl = "intl"
+)
+
+// KnownRegions lists the region identifiers accepted by settings and flags
+var KnownRegions = []string{RegionCN, RegionIntl}
+
+// IsKnownRegion reports whether region is a supported region identifier
+func IsKnownRegion(region string) bool {
+	for _, r := range KnownRegions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionNames returns the regions an endpoint serves, base region first
+func (e *EndpointPreset) RegionNames() []string {
+	var names []string
+	if e.Region != "" {
+		names = append(names, e.Region)
+	}
+	others := make([]string, 0, len(e.Regions))
+	for region := range e.Regions {
+		if region != e.Region {
+			others = append(others, region)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+// ForRegion returns a copy of the endpoint with the region's tool overrides applied
+// An empty region or the endpoint's base region returns the endpoint unchanged.
+func (e *EndpointPreset) ForRegion(region string) (*EndpointPreset, error) {
+	result := cloneEndpoint(*e)
+	if region == "" || region == e.Region {
+		return &result, nil
+	}
+
+	overrides, ok := e.Regions[region]
+	if !ok {
+		available := e.RegionNames()
+		if len(available) == 0 {
+			return nil, fmt.Errorf("endpoint '%s' is not region-specific", e.Name)
+		}
+		return nil, fmt.Errorf("endpoint '%s' has no region '%s' (available: %s)", e.Name, region, strings.Join(available, ", "))
+	}
+
+	result.Tools = mergeTools(result.Tools, cloneTools(overrides))
+	result.Region = region
+	return &result, nil
+}
+
+// RegionalBaseURLs finds the builtin endpoint whose tool base URL matches baseURL in any region
+// It returns the region baseURL belongs to and the tool's base URL in every region of that endpoint.
+// The named provider is checked first; providers materialized under another name
+// (e.g. "kimi-work") are matched by URL. ok is false for custom URLs and single-region providers.
+func RegionalBaseURLs(providerName, toolName, baseURL string) (current string, urls map[string]string, ok bool) {
+	var candidates []BuiltinProviderInfo
+	if info, found := ResolveBuiltinProvider(providerName); found {
+		candidates = append(candidates, info)
+	}
+	names := GetProviderNames()
+	sort.Strings(names)
+	for _, name := range names {
+		candidates = append(candidates, builtinProviders[name])
+	}
+
+	for _, info := range candidates {
+		if current, urls, ok := endpointRegionURLs(info, toolName, baseURL); ok {
+			return current, urls, true
+		}
+	}
+	return "", nil, false
+}
+
+// endpointRegionURLs looks for baseURL among one provider's regional endpoints
+func endpointRegionURLs(info BuiltinProviderInfo, toolName, baseURL string) (string, map[string]string, bool) {
+	want := strings.TrimRight(baseURL, "/")
+	for i := range info.Endpoints {
+		ep := &info.Endpoints[i]
+		regions := ep.RegionNames()
+		if len(regions) < 2 {
+			continue
+		}
+
+		current := ""
+		urls := make(map[string]string, len(regions))
+		for _, region := range regions {
+			regional, err := ep.ForRegion(region)
+			if err != nil {
+				continue
+			}
+			if toolCfg, has := regional.Tools[toolName]; has && toolCfg.BaseURL != "" {
+				urls[region] = toolCfg.BaseURL
+				if strings.TrimRight(toolCfg.BaseURL, "/") == want && current == "" {
+					current = region
+				}
+			}
+		}
+		if current != "" {
+			return current, urls, true
+		}
+	}
+
+	return "", nil, false
+}
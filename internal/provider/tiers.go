@@ -0,0 +1,40 @@
+package provider
+
+import "strings"
+
+// Model tiers every provider can map to its own model IDs
+const (
+	TierFast     = "fast"
+	TierBalanced = "balanced"
+	TierStrong   = "strong"
+)
+
+// ModelTiers lists the tiers from cheapest to most capable
+var ModelTiers = []string{TierFast, TierBalanced, TierStrong}
+
+// tierSynonyms maps Claude model family names to tiers
+var tierSynonyms = map[string]string{
+	"haiku":  TierFast,
+	"sonnet": TierBalanced,
+	"opus":   TierStrong,
+}
+
+// CanonicalModelAlias normalizes an alias name: lowercase, with haiku/sonnet/opus mapped to their tiers
+func CanonicalModelAlias(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if tier, ok := tierSynonyms[name]; ok {
+		return tier
+	}
+	return name
+}
+
+// IsModelTier reports whether name (or a synonym) is a model tier
+func IsModelTier(name string) bool {
+	name = CanonicalModelAlias(name)
+	for _, tier := range ModelTiers {
+		if tier == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeader captures the old line count and the new side's start and count of a diff hunk
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ScanGitHistory scans every line ever added in the repository's history
+// Hits are reported as <short-commit>:<path>:<line>
+func (s *Scanner) ScanGitHistory(repoDir string) ([]Hit, error) {
+	out, err := runGit(repoDir, "log", "-p", "--all", "--no-color", "--no-ext-diff", "-U0", "--format=commit %H")
+	if err != nil {
+		return nil, err
+	}
+	return s.ScanDiff(bytes.NewReader(out))
+}
+
+// ScanStaged scans lines added in the git index (for pre-commit hooks)
+func (s *Scanner) ScanStaged(repoDir string) ([]Hit, error) {
+	out, err := runGit(repoDir, "diff", "--cached", "--no-color", "--no-ext-diff", "-U0")
+	if err != nil {
+		return nil, err
+	}
+	return s.ScanDiff(bytes.NewReader(out))
+}
+
+// ScanDiff scans added lines of unified diff output
+// Hunk headers say how many lines follow, so added lines that look like
+// "+++ " file headers are still scanned as content.
+func (s *Scanner) ScanDiff(r io.Reader) ([]Hit, error) {
+	var hits []Hit
+	var commit, path string
+	lineNo := 0
+	oldLeft, newLeft := 0, 0 // Lines remaining in the current hunk
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if path != "" && path != "/dev/null" {
+					location := path
+					if commit != "" {
+						location = commit + ":" + path
+					}
+					hits = append(hits, s.ScanLine(location, lineNo, line[1:])...)
+				}
+				lineNo++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, " "), line == "":
+				lineNo++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "commit "):
+			commit = strings.TrimPrefix(line, "commit ")
+			if len(commit) > 12 {
+				commit = commit[:12]
+			}
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@"):
+			if m := hunkHeader.FindStringSubmatch(line); m != nil {
+				oldLeft = hunkCount(m[1])
+				lineNo, _ = strconv.Atoi(m[2])
+				newLeft = hunkCount(m[3])
+			}
+		}
+	}
+
+	return hits, scanner.Err()
+}
+
+// hunkCount parses a hunk line count, which is 1 when omitted
+func hunkCount(count string) int {
+	if count == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(count)
+	return n
+}
+
+// runGit runs a git command in the given directory and returns its stdout
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,50 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanDiffAddedLineLikeHeader(t *testing.T) {
+	secret := "sk-0123456789abcdef0123456789abcdef"
+	s := NewScanner(map[string]string{"work": secret})
+	s.SetPatternsEnabled(false)
+
+	diff := strings.Join([]string{
+		"commit 0123456789abcdef0123456789abcdef01234567",
+		"diff --git a/notes.txt b/notes.txt",
+		"--- a/notes.txt",
+		"+++ b/notes.txt",
+		"@@ -1,2 +1,3 @@",
+		"--- old heading",
+		"+++ " + secret,
+		"+plain",
+		" context",
+		"diff --git a/app.env b/app.env",
+		"--- /dev/null",
+		"+++ b/app.env",
+		"@@ -0,0 +1 @@",
+		"+key: " + secret,
+	}, "\n")
+
+	hits, err := s.ScanDiff(strings.NewReader(diff))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		location string
+		line     int
+	}{
+		{"0123456789ab:notes.txt", 1},
+		{"0123456789ab:app.env", 1},
+	}
+	if len(hits) != len(want) {
+		t.Fatalf("got %d hits (%v), want %d", len(hits), hits, len(want))
+	}
+	for i, w := range want {
+		if hits[i].Location != w.location || hits[i].Line != w.line {
+			t.Errorf("hit %d = %s:%d, want %s:%d", i, hits[i].Location, hits[i].Line, w.location, w.line)
+		}
+	}
+}
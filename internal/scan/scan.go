@@ -0,0 +1,270 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// maxFileSize skips files that are unlikely to be hand-written config or source
+const maxFileSize = 2 << 20
+
+// minSecretLength is the shortest configured key value the scanner looks for
+const minSecretLength = 12
+
+// skipDirs are directory names never descended into during a tree walk
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	".venv":        true,
+	"__pycache__":  true,
+}
+
+// tokenPattern splits text into candidate secret tokens
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9_\-.+/=]{12,}`)
+
+// extraPatterns are well-known key shapes for providers aim has no builtin entry for
+var extraPatterns = []Pattern{
+	{Name: "anthropic", Regexp: regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]{32,}`)},
+	{Name: "openai", Regexp: regexp.MustCompile(`sk-proj-[A-Za-z0-9_\-]{32,}`)},
+}
+
+// HitKind distinguishes how a potential leak was detected
+type HitKind string
+
+const (
+	// HitConfiguredKey is a value matching one of the user's configured keys
+	HitConfiguredKey HitKind = "configured-key"
+	// HitPattern is a value shaped like a provider API key
+	HitPattern HitKind = "pattern"
+)
+
+// Hit is a single potential key leak. It never carries the secret itself.
+type Hit struct {
+	Location string  // File path, optionally prefixed with a commit hash
+	Line     int     // 1-based line number
+	Kind     HitKind // Detection method
+	Name     string  // Configured key name or provider name
+}
+
+// String formats the hit as file:line: description
+func (h Hit) String() string {
+	if h.Kind == HitConfiguredKey {
+		return fmt.Sprintf("%s:%d: value of configured key '%s'", h.Location, h.Line, h.Name)
+	}
+	return fmt.Sprintf("%s:%d: %s-shaped API key", h.Location, h.Line, h.Name)
+}
+
+// Pattern is a named key shape
+type Pattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// Scanner finds configured key values and provider-shaped keys in text
+type Scanner struct {
+	secrets    map[[sha256.Size]byte]string // hash -> key name(s)
+	patterns   []Pattern
+	skipPaths  map[string]bool
+	usePattern bool
+}
+
+// NewScanner creates a scanner for the given configured keys (name -> value)
+// Only hashes of the values are retained
+func NewScanner(keys map[string]string) *Scanner {
+	s := &Scanner{
+		secrets:    make(map[[sha256.Size]byte]string),
+		skipPaths:  make(map[string]bool),
+		usePattern: true,
+	}
+
+	var names []string
+	for name, value := range keys {
+		if len(value) < minSecretLength || strings.Contains(value, "$") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Keys sharing a value are reported together
+	for _, name := range names {
+		hash := sha256.Sum256([]byte(keys[name]))
+		if existing, ok := s.secrets[hash]; ok {
+			name = existing + ", " + name
+		}
+		s.secrets[hash] = name
+	}
+
+	s.patterns = append(s.patterns, BuiltinPatterns()...)
+	s.patterns = append(s.patterns, extraPatterns...)
+
+	return s
+}
+
+// BuiltinPatterns returns key patterns declared by the builtin providers
+func BuiltinPatterns() []Pattern {
+	var names []string
+	for name, info := range provider.GetBuiltinProviders() {
+		if info.KeyPattern != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// Providers sharing a key shape can't be told apart, so they share one pattern
+	var patterns []Pattern
+	index := make(map[string]int)
+	for _, name := range names {
+		info, _ := provider.GetBuiltinProvider(name)
+		if i, ok := index[info.KeyPattern]; ok {
+			patterns[i].Name += "/" + name
+			continue
+		}
+		re, err := regexp.Compile(info.KeyPattern)
+		if err != nil {
+			continue
+		}
+		index[info.KeyPattern] = len(patterns)
+		patterns = append(patterns, Pattern{Name: name, Regexp: re})
+	}
+	return patterns
+}
+
+// SetPatternsEnabled toggles provider-shaped pattern detection
+func (s *Scanner) SetPatternsEnabled(enabled bool) {
+	s.usePattern = enabled
+}
+
+// Skip excludes a path from tree walks (e.g. aim's own config file)
+func (s *Scanner) Skip(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		s.skipPaths[abs] = true
+	}
+}
+
+// ScanLine returns hits for a single line of text
+func (s *Scanner) ScanLine(location string, lineNo int, line string) []Hit {
+	var hits []Hit
+	matched := make(map[string]bool)
+
+	for _, token := range tokenPattern.FindAllString(line, -1) {
+		candidates := []string{token, strings.Trim(token, ".=/+-")}
+		// NAME=value assignments are one token
+		if _, value, ok := strings.Cut(token, "="); ok {
+			candidates = append(candidates, strings.Trim(value, ".=/+-"))
+		}
+		for _, candidate := range candidates {
+			if name, ok := s.secrets[sha256.Sum256([]byte(candidate))]; ok && !matched[name] {
+				matched[name] = true
+				hits = append(hits, Hit{Location: location, Line: lineNo, Kind: HitConfiguredKey, Name: name})
+			}
+		}
+	}
+
+	// A configured key already explains the line, don't double-report its shape
+	if !s.usePattern || len(hits) > 0 {
+		return hits
+	}
+
+	for _, p := range s.patterns {
+		if p.Regexp.MatchString(line) {
+			hits = append(hits, Hit{Location: location, Line: lineNo, Kind: HitPattern, Name: p.Name})
+			break
+		}
+	}
+
+	return hits
+}
+
+// ScanReader scans text line by line
+func (s *Scanner) ScanReader(location string, r io.Reader) ([]Hit, error) {
+	var hits []Hit
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		hits = append(hits, s.ScanLine(location, lineNo, scanner.Text())...)
+	}
+
+	return hits, scanner.Err()
+}
+
+// ScanFile scans a single file, skipping binary and oversized files
+func (s *Scanner) ScanFile(path string) ([]Hit, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() || info.Size() > maxFileSize {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBinary(data) {
+		return nil, nil
+	}
+
+	return s.ScanReader(path, bytes.NewReader(data))
+}
+
+// ScanTree walks a directory tree and scans every text file
+func (s *Scanner) ScanTree(root string) ([]Hit, error) {
+	var hits []Hit
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable entries are skipped rather than aborting the walk
+			return nil
+		}
+
+		if d.IsDir() {
+			if path != root && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if abs, err := filepath.Abs(path); err == nil && s.skipPaths[abs] {
+			return nil
+		}
+
+		fileHits, err := s.ScanFile(path)
+		if err != nil {
+			return nil
+		}
+		hits = append(hits, fileHits...)
+		return nil
+	})
+
+	return hits, err
+}
+
+// isBinary reports whether data looks like a binary file
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return bytes.IndexByte(head, 0) >= 0
+}
@@ -0,0 +1,121 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanLine(t *testing.T) {
+	secret := "sk-live-0123456789abcdef"
+	s := NewScanner(map[string]string{
+		"work":   secret,
+		"twin":   secret,
+		"env":    "${DEEPSEEK_API_KEY}",
+		"short":  "sk-1",
+		"proxy":  "$PROXY_TOKEN_VALUE",
+		"plain":  "plain-key-value-01",
+		"quoted": "quoted-key-value-02",
+	})
+
+	tests := []struct {
+		name string
+		line string
+		want []Hit
+	}{
+		{
+			name: "configured key",
+			line: "export KEY=plain-key-value-01",
+			want: []Hit{{Location: "f", Line: 1, Kind: HitConfiguredKey, Name: "plain"}},
+		},
+		{
+			name: "configured key in quotes",
+			line: `api_key: "quoted-key-value-02"`,
+			want: []Hit{{Location: "f", Line: 1, Kind: HitConfiguredKey, Name: "quoted"}},
+		},
+		{
+			name: "keys sharing a value",
+			line: secret,
+			want: []Hit{{Location: "f", Line: 1, Kind: HitConfiguredKey, Name: "twin, work"}},
+		},
+		{
+			name: "values with $ are env references, not secrets",
+			line: "key: ${DEEPSEEK_API_KEY} $PROXY_TOKEN_VALUE",
+		},
+		{
+			name: "short values are ignored",
+			line: "key: sk-1",
+		},
+		{
+			name: "provider-shaped key",
+			line: "key: sk-ant-" + "abcdefghijklmnopqrstuvwxyz0123456789",
+			want: []Hit{{Location: "f", Line: 1, Kind: HitPattern, Name: "anthropic"}},
+		},
+		{
+			name: "shared key shape lists every provider",
+			line: "key: sk-0123456789abcdef0123456789abcdef",
+			want: []Hit{{Location: "f", Line: 1, Kind: HitPattern, Name: "deepseek/qwen"}},
+		},
+		{
+			name: "configured key suppresses the pattern",
+			line: "plain-key-value-01 sk-0123456789abcdef0123456789abcdef",
+			want: []Hit{{Location: "f", Line: 1, Kind: HitConfiguredKey, Name: "plain"}},
+		},
+		{
+			name: "clean line",
+			line: "model: deepseek-chat",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.ScanLine("f", 1, tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScanLine(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	s.SetPatternsEnabled(false)
+	if got := s.ScanLine("f", 1, "key: sk-0123456789abcdef0123456789abcdef"); len(got) != 0 {
+		t.Errorf("patterns disabled, got %v", got)
+	}
+}
+
+func TestScanTree(t *testing.T) {
+	secret := "plain-key-value-01"
+	root := t.TempDir()
+	files := map[string]string{
+		"app.env":                 "A=1\nKEY=" + secret + "\n",
+		"src/main.go":             "package main\n",
+		"src/config.yaml":         "key: " + secret + "\n",
+		".git/config":             secret,
+		"node_modules/x/index.js": secret,
+		"skipped.yaml":            secret,
+		"blob.bin":                "\x00" + secret,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner(map[string]string{"work": secret})
+	s.Skip(filepath.Join(root, "skipped.yaml"))
+
+	hits, err := s.ScanTree(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Hit{
+		{Location: filepath.Join(root, "app.env"), Line: 2, Kind: HitConfiguredKey, Name: "work"},
+		{Location: filepath.Join(root, "src/config.yaml"), Line: 1, Kind: HitConfiguredKey, Name: "work"},
+	}
+	if !reflect.DeepEqual(hits, want) {
+		t.Errorf("hits = %v, want %v", hits, want)
+	}
+}
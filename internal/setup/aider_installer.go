@@ -0,0 +1,220 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/constants"
+	"github.com/fakecore/aim/internal/provider"
+	"github.com/fakecore/aim/internal/tool"
+	"gopkg.in/yaml.v3"
+)
+
+// aiderManagedMarker starts the comment aim writes at the top of .aider.conf.yml
+// aider rejects unknown keys, so the managed_by_aim record is a comment instead of a field.
+const aiderManagedMarker = "# managed_by_aim:"
+
+// aiderOwnedKeys are the settings aim writes; they are replaced on every install
+var aiderOwnedKeys = []string{"model", "weak-model", "openai-api-base", "openai-api-key"}
+
+// AiderInstaller aider installer
+type AiderInstaller struct {
+	BaseInstaller
+}
+
+func NewAiderInstaller() *AiderInstaller {
+	return &AiderInstaller{}
+}
+
+func (i *AiderInstaller) Install(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	managedByAIM, existingConfig, err := i.loadExisting(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to check existing config: %w", err)
+	}
+
+	// A file aim manages is rewritten; otherwise the user's other settings are kept
+	// and only the ones aim owns are replaced
+	newConfig := make(map[string]interface{})
+	if !managedByAIM {
+		for k, v := range existingConfig {
+			newConfig[k] = v
+		}
+		for _, k := range aiderOwnedKeys {
+			delete(newConfig, k)
+		}
+	}
+
+	aiderConfig, err := i.ConvertConfig(req)
+	if err != nil {
+		return fmt.Errorf("failed to convert config: %w", err)
+	}
+	for k, v := range aiderConfig.(map[string]interface{}) {
+		if k == "api-key" {
+			v = mergeAiderAPIKeys(newConfig["api-key"], v.([]string))
+		}
+		newConfig[k] = v
+	}
+
+	data, err := yaml.Marshal(newConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	header := fmt.Sprintf("%s version=%s tool=%s key=%s managed_at=%s backup=true\n",
+		aiderManagedMarker, Version, req.Runtime.Tool, req.KeyName, time.Now().Format(time.RFC3339))
+
+	// The file holds the API key
+	if err := writeConfigFile(configPath, append([]byte(header), data...), constants.SecretFileMode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+func (i *AiderInstaller) Backup(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	return backupFile(configPath, req.BackupPath, constants.SecretFileMode)
+}
+
+func (i *AiderInstaller) GetConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".aider.conf.yml"), nil
+}
+
+func (i *AiderInstaller) ValidateConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertConfig maps the runtime to aider's settings
+// DeepSeek's own endpoint uses aider's native provider and api-key; other endpoints
+// go through openai-api-base and openai-api-key.
+func (i *AiderInstaller) ConvertConfig(req *InstallRequest) (interface{}, error) {
+	runtime := req.Runtime
+	aiderConfig := make(map[string]interface{})
+
+	if runtime.Model != "" {
+		aiderConfig["model"] = tool.AiderModelName(runtime, runtime.Model)
+	}
+	if weak, ok := runtime.Models[provider.TierFast]; ok && weak != runtime.Model {
+		aiderConfig["weak-model"] = tool.AiderModelName(runtime, weak)
+	}
+
+	if native, ok := tool.AiderNative(runtime.BaseURL); ok {
+		aiderConfig["api-key"] = []string{native.Prefix + "=" + runtime.APIKey}
+	} else {
+		aiderConfig["openai-api-base"] = runtime.BaseURL
+		aiderConfig["openai-api-key"] = runtime.APIKey
+	}
+
+	return aiderConfig, nil
+}
+
+// loadExisting reads the current aider config, if any, and whether aim manages it
+// Like managed_by_aim in other tools' files, the header only counts when it records a backup.
+func (i *AiderInstaller) loadExisting(configPath string) (bool, map[string]interface{}, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var existingConfig map[string]interface{}
+	if err := yaml.Unmarshal(data, &existingConfig); err != nil {
+		return false, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	managedByAIM := strings.HasPrefix(firstLine, aiderManagedMarker) && strings.Contains(firstLine, " backup=true")
+
+	return managedByAIM, existingConfig, nil
+}
+
+// PlanKeyRename rewrites the key recorded in the managed header
+func (i *AiderInstaller) PlanKeyRename(oldKey, newKey string) ([]ManagedConfigChange, error) {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	firstLine, rest, _ := strings.Cut(string(data), "\n")
+	if !strings.HasPrefix(firstLine, aiderManagedMarker) {
+		return nil, nil
+	}
+	fields := strings.Fields(strings.TrimPrefix(firstLine, aiderManagedMarker))
+	renamed := false
+	for n, field := range fields {
+		if field == "key="+oldKey {
+			fields[n] = "key=" + newKey
+			renamed = true
+		}
+	}
+	if !renamed {
+		return nil, nil
+	}
+
+	header := aiderManagedMarker + " " + strings.Join(fields, " ")
+	return []ManagedConfigChange{{
+		Path:     configPath,
+		Location: "managed_by_aim.key",
+		Old:      oldKey,
+		New:      newKey,
+		Data:     []byte(header + "\n" + rest),
+	}}, nil
+}
+
+// mergeAiderAPIKeys replaces the entries for our providers in an existing api-key list
+func mergeAiderAPIKeys(existing interface{}, ours []string) []string {
+	replaced := make(map[string]bool)
+	for _, entry := range ours {
+		name, _, _ := strings.Cut(entry, "=")
+		replaced[name] = true
+	}
+
+	var merged []string
+	if entries, ok := existing.([]interface{}); ok {
+		for _, entry := range entries {
+			s, ok := entry.(string)
+			if !ok {
+				continue
+			}
+			if name, _, _ := strings.Cut(s, "="); !replaced[name] {
+				merged = append(merged, s)
+			}
+		}
+	}
+	return append(merged, ours...)
+}
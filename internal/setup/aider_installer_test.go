@@ -0,0 +1,100 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAiderInstallerManagedMarker(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".aider.conf.yml")
+
+	tests := []struct {
+		name      string
+		existing  string
+		keepsUser bool // Whether the user's dark-mode setting survives
+	}{
+		{"unmanaged", "dark-mode: true\nmodel: old\n", true},
+		{"managed", "# managed_by_aim: version=1 tool=aider key=old managed_at=x backup=true\ndark-mode: true\nmodel: old\n", false},
+		{"marker without backup", "# managed_by_aim: version=1 tool=aider key=old\ndark-mode: true\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			installer := NewAiderInstaller()
+			req := newKeyRequest("aider")
+			req.Runtime.BaseURL = "https://proxy.example.com/v1"
+			req.BackupPath = path + ".bak"
+			if err := installer.Backup(req); err != nil {
+				t.Fatal(err)
+			}
+			if err := installer.Install(req); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := string(data)
+			if !strings.HasPrefix(content, aiderManagedMarker) || !strings.Contains(content, "model: openai/m1") {
+				t.Errorf("installed config = %s", content)
+			}
+			if got := strings.Contains(content, "dark-mode: true"); got != tt.keepsUser {
+				t.Errorf("dark-mode kept = %v, want %v:\n%s", got, tt.keepsUser, content)
+			}
+
+			assertMode(t, path, 0600)
+			assertMode(t, req.BackupPath, 0600)
+		})
+	}
+}
+
+func TestAiderInstallerPlanKeyRename(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".aider.conf.yml")
+	body := "dark-mode: true\nmodel: openai/m1\n"
+
+	installer := NewAiderInstaller()
+	tests := []struct {
+		name   string
+		header string
+		want   string // Rewritten header, empty when nothing changes
+	}{
+		{"managed", "# managed_by_aim: version=1 tool=aider key=work managed_at=x backup=true", "# managed_by_aim: version=1 tool=aider key=job managed_at=x backup=true"},
+		{"other key", "# managed_by_aim: version=1 tool=aider key=workshop backup=true", ""},
+		{"unmanaged", "# my settings key=work", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.header+"\n"+body), 0600); err != nil {
+				t.Fatal(err)
+			}
+			changes, err := installer.PlanKeyRename("work", "job")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if len(changes) != 0 {
+					t.Errorf("changes = %+v, want none", changes)
+				}
+				return
+			}
+			if len(changes) != 1 || changes[0].Path != path {
+				t.Fatalf("changes = %+v", changes)
+			}
+			if got := string(changes[0].Data); got != tt.want+"\n"+body {
+				t.Errorf("rewritten config = %q", got)
+			}
+		})
+	}
+}
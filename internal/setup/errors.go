@@ -18,9 +18,6 @@ var (
 	ErrNoDefaultTool     = errors.New("no default tool configured")
 )
 
-// List of supported tools
-var SupportedTools = []string{"cc", "claude-code", "codex"}
-
 // Supported environment variable types
 var SupportedEnvTypes = []string{"zsh", "bash", "fish", "json"}
 
@@ -0,0 +1,141 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+)
+
+// geminiEnvKeys are the variables aim manages in ~/.gemini/.env
+var geminiEnvKeys = []string{"GEMINI_API_KEY", "GOOGLE_GEMINI_BASE_URL"}
+
+// GeminiInstaller Gemini CLI installer
+// The model and auth type go to ~/.gemini/settings.json; Gemini CLI has no settings
+// for the key or endpoint, so those go to ~/.gemini/.env, which it loads on start.
+type GeminiInstaller struct {
+	BaseInstaller
+}
+
+func NewGeminiInstaller() *GeminiInstaller {
+	return &GeminiInstaller{}
+}
+
+func (i *GeminiInstaller) Install(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := i.installSettings(req, configPath, &JSONParser{}, i.ConvertConfig, constants.ConfigFileMode); err != nil {
+		return err
+	}
+
+	return i.installEnv(req, i.envPath(configPath))
+}
+
+func (i *GeminiInstaller) Backup(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := backupFile(configPath, req.BackupPath, constants.ConfigFileMode); err != nil {
+		return err
+	}
+
+	// .env gets its own timestamped backup next to it, restricted like .env since it holds the key
+	return backupFile(i.envPath(configPath), "", constants.SecretFileMode)
+}
+
+func (i *GeminiInstaller) GetConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".gemini", "settings.json"), nil
+}
+
+func (i *GeminiInstaller) ValidateConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
+
+func (i *GeminiInstaller) ConvertConfig(req *InstallRequest) (interface{}, error) {
+	geminiConfig := map[string]interface{}{
+		"security": map[string]interface{}{
+			"auth": map[string]interface{}{"selectedType": "gemini-api-key"},
+		},
+		"managed_by_aim": managedByAIMField(req),
+	}
+	if req.Runtime.Model != "" {
+		geminiConfig["model"] = map[string]interface{}{"name": req.Runtime.Model}
+	}
+
+	return geminiConfig, nil
+}
+
+// envPath returns the .env file next to settings.json
+func (i *GeminiInstaller) envPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), ".env")
+}
+
+// installEnv sets the key and endpoint in .env, keeping the user's other lines
+func (i *GeminiInstaller) installEnv(req *InstallRequest, envPath string) error {
+	values := make(map[string]string)
+	if req.Runtime.APIKey != "" && req.Runtime.AuthScheme != config.AuthSchemeNone {
+		values["GEMINI_API_KEY"] = req.Runtime.APIKey
+	}
+	if req.Runtime.BaseURL != "" {
+		values["GOOGLE_GEMINI_BASE_URL"] = req.Runtime.BaseURL
+	}
+
+	data, err := os.ReadFile(envPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		name, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		if line == "" || isGeminiEnvKey(strings.TrimSpace(name)) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for _, name := range geminiEnvKeys {
+		if value, ok := values[name]; ok {
+			lines = append(lines, name+"="+value)
+		}
+	}
+
+	if err := writeConfigFile(envPath, []byte(strings.Join(lines, "\n")+"\n"), constants.SecretFileMode); err != nil {
+		return fmt.Errorf("failed to write env file: %w", err)
+	}
+
+	return nil
+}
+
+// isGeminiEnvKey reports whether a variable is managed by aim in .env
+func isGeminiEnvKey(name string) bool {
+	for _, key := range geminiEnvKeys {
+		if name == key {
+			return true
+		}
+	}
+	return false
+}
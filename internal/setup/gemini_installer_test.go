@@ -0,0 +1,94 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+// assertMode fails the test unless path exists with the given permissions
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("%s mode = %o, want %o", filepath.Base(path), got, want)
+	}
+}
+
+func newKeyRequest(toolName string) *InstallRequest {
+	return &InstallRequest{
+		SetupRequest: NewSetupRequest(toolName, "work"),
+		Runtime:      &config.RuntimeConfig{Tool: toolName, Key: "work", APIKey: "sk-secret", BaseURL: "https://api.example.com", Model: "m1"},
+	}
+}
+
+func TestGeminiInstallerRestrictsEnv(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	envPath := filepath.Join(home, ".gemini", ".env")
+	if err := os.MkdirAll(filepath.Dir(envPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(envPath, []byte("OTHER=1\nGEMINI_API_KEY=old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	installer := NewGeminiInstaller()
+	req := newKeyRequest("gemini-cli")
+	if err := installer.Backup(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := installer.Install(req); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "OTHER=1") || !strings.Contains(string(data), "GEMINI_API_KEY=sk-secret") {
+		t.Errorf(".env = %s", data)
+	}
+	assertMode(t, envPath, 0600)
+
+	backups, err := filepath.Glob(envPath + ".bak.*")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v, %v", backups, err)
+	}
+	assertMode(t, backups[0], 0600)
+}
+
+func TestQwenCodeInstallerRestrictsSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	installer := NewQwenCodeInstaller()
+	configPath, err := installer.GetConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(`{"theme": "dark"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newKeyRequest("qwen-code")
+	req.BackupPath = configPath + ".bak"
+	if err := installer.Backup(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := installer.Install(req); err != nil {
+		t.Fatal(err)
+	}
+
+	assertMode(t, configPath, 0600)
+	assertMode(t, req.BackupPath, 0600)
+}
@@ -98,7 +98,7 @@ func (b *BaseInstaller) checkManagedByAIM(configPath string, parser ConfigParser
 }
 
 // installAIMManaged通用实现
-func (b *BaseInstaller) installAIMManaged(req *InstallRequest, configPath string, parser ConfigParser, convertConfig func(*InstallRequest) (interface{}, error)) error {
+func (b *BaseInstaller) installAIMManaged(req *InstallRequest, configPath string, parser ConfigParser, convertConfig func(*InstallRequest) (interface{}, error), mode os.FileMode) error {
 	// Convert configuration
 	config, err := convertConfig(req)
 	if err != nil {
@@ -112,13 +112,96 @@ func (b *BaseInstaller) installAIMManaged(req *InstallRequest, configPath string
 	}
 
 	// Directly overwrite the config file
-	if err := os.WriteFile(configPath, configData, constants.ConfigFileMode); err != nil {
+	if err := writeConfigFile(configPath, configData, mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// installSettings writes a JSON or YAML settings file following the managed_by_aim rules:
+// a managed file is overwritten, an unmanaged one keeps the user's settings with aim's merged in
+func (b *BaseInstaller) installSettings(req *InstallRequest, configPath string, parser ConfigParser, convertConfig func(*InstallRequest) (interface{}, error), mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), constants.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	managedByAIM, existingConfig, err := b.checkManagedByAIM(configPath, parser)
+	if err != nil {
+		return fmt.Errorf("failed to check existing config: %w", err)
+	}
+	if managedByAIM || existingConfig == nil {
+		return b.installAIMManaged(req, configPath, parser, convertConfig, mode)
+	}
+
+	config, err := convertConfig(req)
+	if err != nil {
+		return fmt.Errorf("failed to convert config: %w", err)
+	}
+	if configMap, ok := config.(map[string]interface{}); ok {
+		mergeConfigMaps(existingConfig, configMap)
+	}
+
+	configData, err := parser.Marshal(existingConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := writeConfigFile(configPath, configData, mode); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	return nil
 }
 
+// writeConfigFile writes an installed file with at most the given permissions
+// os.WriteFile keeps the mode of a file it overwrites, so the mode is applied explicitly;
+// an existing file is never made more permissive than it was.
+func writeConfigFile(path string, data []byte, mode os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		mode &= info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
+
+// backupFile copies a config file next to itself; missing files need no backup
+// The copy gets the source's permissions, restricted further to mode.
+func backupFile(configPath, backupPath string, mode os.FileMode) error {
+	info, err := os.Stat(configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if backupPath == "" {
+		backupPath = configPath + ".bak." + time.Now().Format("20060102_1504")
+	}
+	if err := writeConfigFile(backupPath, data, info.Mode().Perm()&mode); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	return nil
+}
+
+// managedByAIMField returns the managed_by_aim record aim adds to the files it writes
+func managedByAIMField(req *InstallRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"version":    Version,
+		"tool":       req.Runtime.Tool,
+		"key":        req.KeyName,
+		"managed_at": time.Now().Format(time.RFC3339),
+		"backup":     true,
+	}
+}
+
 func (i *ClaudeCodeInstaller) Install(req *InstallRequest) error {
 	// Get config path
 	configPath, err := i.GetConfigPath()
@@ -214,9 +297,11 @@ func (i *ClaudeCodeInstaller) ConvertConfig(req *InstallRequest) (interface{}, e
 	runtime := req.Runtime
 
 	// Build Claude Code configuration
+	// The API key is fetched on demand through apiKeyHelper instead of being embedded,
+	// so rotating the key in aim takes effect without reinstalling
 	claudeConfig := map[string]interface{}{
+		"apiKeyHelper": CredentialHelperCommand(runtime.Tool, req.KeyName),
 		"env": map[string]interface{}{
-			"ANTHROPIC_AUTH_TOKEN":                     runtime.APIKey,
 			"ANTHROPIC_BASE_URL":                       "https://open.bigmodel.cn/api/anthropic",
 			"API_TIMEOUT_MS":                           "3000000",
 			"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC": 1,
@@ -248,6 +333,11 @@ func (i *ClaudeCodeInstaller) ConvertConfig(req *InstallRequest) (interface{}, e
 	return claudeConfig, nil
 }
 
+// CredentialHelperCommand returns the command tools run to fetch a key from aim
+func CredentialHelperCommand(toolName, keyName string) string {
+	return fmt.Sprintf("aim credential get --tool %s --key %s", EscapeShellValue(toolName), EscapeShellValue(keyName))
+}
+
 // CodexInstaller Codex installer
 type CodexInstaller struct {
 	BaseInstaller
@@ -387,7 +477,7 @@ func (i *ClaudeCodeInstaller) checkManagedByAIM(configPath string) (bool, map[st
 
 // installAIMManaged installs to existing AIM-managed configuration
 func (i *ClaudeCodeInstaller) installAIMManaged(req *InstallRequest, configPath string) error {
-	return i.BaseInstaller.installAIMManaged(req, configPath, &JSONParser{}, i.ConvertConfig)
+	return i.BaseInstaller.installAIMManaged(req, configPath, &JSONParser{}, i.ConvertConfig, constants.ConfigFileMode)
 }
 
 // installNonAIMManaged installs to non-AIM managed configuration
@@ -456,7 +546,7 @@ func (i *CodexInstaller) checkManagedByAIM(configPath string) (bool, map[string]
 
 // installAIMManaged installs to existing AIM-managed configuration
 func (i *CodexInstaller) installAIMManaged(req *InstallRequest, configPath string) error {
-	return i.BaseInstaller.installAIMManaged(req, configPath, &TOMLParser{}, i.ConvertConfig)
+	return i.BaseInstaller.installAIMManaged(req, configPath, &TOMLParser{}, i.ConvertConfig, constants.ConfigFileMode)
 }
 
 // installNonAIMManaged installs to non-AIM managed configuration
@@ -9,6 +9,12 @@ type ToolInstaller interface {
 	ConvertConfig(req *InstallRequest) (interface{}, error)
 }
 
+// KeyRenamer is implemented by installers that record the key name in their own way
+// instead of a managed_by_aim field; 'aim keys rename' asks them for the rewritten files.
+type KeyRenamer interface {
+	PlanKeyRename(oldKey, newKey string) ([]ManagedConfigChange, error)
+}
+
 // OutputFormatter output formatter base interface
 type OutputFormatter interface {
 	GetName() string
@@ -64,6 +64,17 @@ func (sm *SetupManager) registerDefaultInstallers() {
 	sm.installers["claude-code"] = NewClaudeCodeInstaller()
 	sm.installers["cc"] = NewClaudeCodeInstaller() // cc is an alias for claude-code
 	sm.installers["codex"] = NewCodexInstaller()
+	sm.installers["aider"] = NewAiderInstaller()
+	sm.installers["gemini-cli"] = NewGeminiInstaller()
+	sm.installers["qwen-code"] = NewQwenCodeInstaller()
+	sm.installers["opencode"] = NewOpenCodeInstaller(sm.configManager)
+
+	// Tools that declare an install target in config get a template installer
+	for toolName, toolCfg := range sm.configManager.GetConfig().Tools {
+		if toolCfg != nil && toolCfg.Install != nil {
+			sm.installers[toolName] = NewTemplateInstaller(toolName, toolCfg.Install)
+		}
+	}
 }
 
 // registerDefaultFormatters registers default formatters
@@ -122,7 +133,7 @@ func (sm *SetupManager) ExportEnv(ctx context.Context, req *SetupRequest) (*Setu
 	result.Runtime = runtime
 
 	// Prepare tool-specific environment variables
-	_, toolEnvVars, err := sm.preparerManager.PrepareEnvironment(runtime)
+	_, toolEnvVars, err := sm.prepareEnvironment(runtime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare tool environment: %w", err)
 	}
@@ -179,7 +190,7 @@ func (sm *SetupManager) GenerateCommand(ctx context.Context, req *SetupRequest)
 	result.Runtime = runtime
 
 	// Prepare tool-specific environment variables and arguments
-	toolArgs, toolEnvVars, err := sm.preparerManager.PrepareEnvironment(runtime)
+	toolArgs, toolEnvVars, err := sm.prepareEnvironment(runtime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare tool environment: %w", err)
 	}
@@ -271,7 +282,7 @@ func (sm *SetupManager) InstallConfig(ctx context.Context, req *InstallRequest)
 	}
 
 	// Prepare tool-specific environment variables
-	_, toolEnvVars, err := sm.preparerManager.PrepareEnvironment(runtime)
+	_, toolEnvVars, err := sm.prepareEnvironment(runtime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare tool environment: %w", err)
 	}
@@ -372,6 +383,17 @@ func (sm *SetupManager) FormatCommand(result *SetupResult, formatType string) (s
 	return commandFormatter.FormatCommand(result), nil
 }
 
+// prepareEnvironment runs the tool's preparers
+// Files written by external preparers only live for 'aim run', so they are removed right away.
+func (sm *SetupManager) prepareEnvironment(runtime *config.RuntimeConfig) ([]string, map[string]string, error) {
+	defer func() {
+		if err := sm.preparerManager.Cleanup(); err != nil {
+			sm.logger.Errorf("Warning: %v", err)
+		}
+	}()
+	return sm.preparerManager.PrepareEnvironment(runtime)
+}
+
 // mergeEnvVars merges environment variables
 func (sm *SetupManager) mergeEnvVars(base, toolSpecific map[string]string) map[string]string {
 	merged := make(map[string]string)
@@ -500,6 +522,21 @@ func (sm *SetupManager) RegisterInstaller(toolName string, installer ToolInstall
 	sm.installers[toolName] = installer
 }
 
+// ConfigPaths returns the config file path of every registered installer, keyed by canonical tool name
+func (sm *SetupManager) ConfigPaths() map[string]string {
+	paths := make(map[string]string)
+	for toolName, installer := range sm.installers {
+		canonicalToolName := sm.getCanonicalToolName(toolName)
+		if _, exists := paths[canonicalToolName]; exists {
+			continue
+		}
+		if path, err := installer.GetConfigPath(); err == nil {
+			paths[canonicalToolName] = path
+		}
+	}
+	return paths
+}
+
 // RegisterFormatter registers a formatter
 func (sm *SetupManager) RegisterFormatter(name string, formatter OutputFormatter) {
 	sm.formatters[name] = formatter
@@ -507,16 +544,7 @@ func (sm *SetupManager) RegisterFormatter(name string, formatter OutputFormatter
 
 // getCanonicalToolName gets the canonical name of a tool
 func (sm *SetupManager) getCanonicalToolName(toolName string) string {
-	switch toolName {
-	case "cc":
-		return "claude-code"
-	case "claude-code":
-		return "claude-code"
-	case "codex":
-		return "codex"
-	default:
-		return toolName
-	}
+	return tool.GetCanonicalName(toolName)
 }
 
 // findLatestBackup finds the latest backup file
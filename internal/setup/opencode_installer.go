@@ -0,0 +1,323 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+	"github.com/fakecore/aim/internal/tool"
+)
+
+// OpenCodeInstaller opencode installer
+// Every aim key with an opencode profile becomes an "aim-<key>" provider in opencode.json
+// and the installed key's model becomes the default. Providers the user added are kept.
+// opencode rejects unknown config keys, so the providers aim wrote are recorded in
+// $AIM_HOME/opencode/installed.json instead of a managed_by_aim field; only those are
+// replaced, and dropped once their key is gone.
+// Keys are {env:AIM_<KEY>_...} references, so opencode started outside aim needs each
+// key's variables exported, e.g. with 'aim setup env opencode --key <key>'.
+type OpenCodeInstaller struct {
+	BaseInstaller
+	configManager *config.ConfigManager
+}
+
+// openCodeInstalled records the providers aim added to each opencode.json
+type openCodeInstalled struct {
+	Providers map[string][]string `json:"providers"` // opencode.json path -> provider ids
+}
+
+func NewOpenCodeInstaller(configManager *config.ConfigManager) *OpenCodeInstaller {
+	return &OpenCodeInstaller{configManager: configManager}
+}
+
+func (i *OpenCodeInstaller) Install(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), constants.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	openCodeConfig := map[string]interface{}{"$schema": tool.OpenCodeSchema}
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &openCodeConfig); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	installed, err := loadOpenCodeInstalled()
+	if err != nil {
+		return err
+	}
+
+	converted, err := i.ConvertConfig(req)
+	if err != nil {
+		return fmt.Errorf("failed to convert config: %w", err)
+	}
+	ourConfig := converted.(map[string]interface{})
+	ours := ourConfig["provider"].(map[string]interface{})
+
+	providers, _ := openCodeConfig["provider"].(map[string]interface{})
+	if providers == nil {
+		providers = make(map[string]interface{})
+	}
+	// Providers aim added earlier for keys that are gone or no longer resolve are dropped
+	dropped := make(map[string]bool)
+	for _, id := range installed.Providers[configPath] {
+		if _, ok := ours[id]; !ok {
+			delete(providers, id)
+			dropped[id] = true
+		}
+	}
+	ids := make([]string, 0, len(ours))
+	for id, entry := range ours {
+		providers[id] = entry
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	openCodeConfig["provider"] = providers
+
+	// The default models follow the installed key; one left pointing at a dropped provider is removed
+	for _, field := range []string{"model", "small_model"} {
+		if ref, ok := ourConfig[field]; ok {
+			openCodeConfig[field] = ref
+		} else if ref, _ := openCodeConfig[field].(string); dropped[strings.SplitN(ref, "/", 2)[0]] {
+			delete(openCodeConfig, field)
+		}
+	}
+
+	configData, err := json.MarshalIndent(openCodeConfig, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	// Keys are {env:...} references, but plain headers and private endpoints are still kept from other users
+	if err := writeConfigFile(configPath, configData, constants.SecretFileMode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	installed.Providers[configPath] = ids
+	return saveOpenCodeInstalled(installed)
+}
+
+func (i *OpenCodeInstaller) Backup(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	return backupFile(configPath, req.BackupPath, constants.SecretFileMode)
+}
+
+// GetConfigPath returns opencode's global config, under $XDG_CONFIG_HOME when set
+func (i *OpenCodeInstaller) GetConfigPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, "opencode", "opencode.json"), nil
+}
+
+func (i *OpenCodeInstaller) ValidateConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertConfig builds aim's part of opencode.json: a provider for every key that
+// resolves for opencode, and the installed key's models as the defaults
+func (i *OpenCodeInstaller) ConvertConfig(req *InstallRequest) (interface{}, error) {
+	cfg := i.configManager.GetConfig()
+	resolver := config.NewResolver(cfg)
+
+	keyNames := make([]string, 0, len(cfg.Keys))
+	for keyName := range cfg.Keys {
+		keyNames = append(keyNames, keyName)
+	}
+	sort.Strings(keyNames)
+
+	providers := make(map[string]interface{})
+	envOwners := make(map[string]string)
+	for _, keyName := range keyNames {
+		runtime := req.Runtime
+		if keyName != req.KeyName {
+			var err error
+			// Keys whose provider has no opencode profile are left out
+			if runtime, err = resolver.Resolve(req.Runtime.Tool, keyName, ""); err != nil {
+				continue
+			}
+		}
+		entry, envVars := tool.OpenCodeProvider(runtime)
+		// Key names differing only in punctuation map to the same variables
+		for envKey := range envVars {
+			if other, ok := envOwners[envKey]; ok {
+				return nil, fmt.Errorf("keys '%s' and '%s' both use %s; rename one of them", other, keyName, envKey)
+			}
+			envOwners[envKey] = keyName
+		}
+		providers[tool.OpenCodeProviderID(keyName)] = entry
+	}
+
+	openCodeConfig := map[string]interface{}{"provider": providers}
+	model, smallModel := tool.OpenCodeModelRefs(req.Runtime)
+	if model != "" {
+		openCodeConfig["model"] = model
+	}
+	if smallModel != "" {
+		openCodeConfig["small_model"] = smallModel
+	}
+
+	return openCodeConfig, nil
+}
+
+// PlanKeyRename moves the key's provider to its new id, with the default models, env
+// references and name label following it, and updates the install record
+func (i *OpenCodeInstaller) PlanKeyRename(oldKey, newKey string) ([]ManagedConfigChange, error) {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+	installed, err := loadOpenCodeInstalled()
+	if err != nil {
+		return nil, err
+	}
+	oldID, newID := tool.OpenCodeProviderID(oldKey), tool.OpenCodeProviderID(newKey)
+	ids := installed.Providers[configPath]
+	owned := -1
+	for n, id := range ids {
+		if id == oldID {
+			owned = n
+		}
+	}
+	if owned < 0 {
+		return nil, nil
+	}
+
+	// A deleted opencode.json only leaves the record to update
+	openCodeConfig := make(map[string]interface{})
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &openCodeConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	var changes []ManagedConfigChange
+	if providers, _ := openCodeConfig["provider"].(map[string]interface{}); providers[oldID] != nil {
+		// The entry's name label and {env:AIM_<KEY>_...} references carry the key name too
+		entry, err := json.Marshal(providers[oldID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode provider: %w", err)
+		}
+		oldEnv, newEnv := config.KeyEnvName(oldKey, ""), config.KeyEnvName(newKey, "")
+		renamedEntry := strings.NewReplacer(
+			"(aim: "+oldKey+")", "(aim: "+newKey+")",
+			"{env:"+oldEnv, "{env:"+newEnv,
+		).Replace(string(entry))
+		var renamed interface{}
+		if err := json.Unmarshal([]byte(renamedEntry), &renamed); err != nil {
+			return nil, fmt.Errorf("failed to decode provider: %w", err)
+		}
+		delete(providers, oldID)
+		providers[newID] = renamed
+		changes = append(changes, ManagedConfigChange{Path: configPath, Location: "provider." + oldID, Old: oldID, New: newID})
+		// Shells exporting the old variables need the new names
+		if oldEnv != newEnv && strings.Contains(string(entry), "{env:"+oldEnv) {
+			changes = append(changes, ManagedConfigChange{Path: configPath, Location: "provider." + newID + " {env:...}", Old: oldEnv + "*", New: newEnv + "*"})
+		}
+	}
+	for _, field := range []string{"model", "small_model"} {
+		if ref, _ := openCodeConfig[field].(string); strings.HasPrefix(ref, oldID+"/") {
+			newRef := newID + strings.TrimPrefix(ref, oldID)
+			openCodeConfig[field] = newRef
+			changes = append(changes, ManagedConfigChange{Path: configPath, Location: field, Old: ref, New: newRef})
+		}
+	}
+	configData, err := json.MarshalIndent(openCodeConfig, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	for n := range changes {
+		changes[n].Data = configData
+	}
+
+	ids[owned] = newID
+	sort.Strings(ids)
+	recordData, err := json.MarshalIndent(installed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode opencode install record: %w", err)
+	}
+	return append(changes, ManagedConfigChange{
+		Path:     openCodeInstalledPath(),
+		Location: "providers",
+		Old:      oldID,
+		New:      newID,
+		Data:     recordData,
+	}), nil
+}
+
+// openCodeInstalledPath returns the record of providers aim added to opencode configs
+func openCodeInstalledPath() string {
+	return filepath.Join(config.GetAIMHome(), "opencode", "installed.json")
+}
+
+// loadOpenCodeInstalled reads the installed providers record, empty when missing
+func loadOpenCodeInstalled() (*openCodeInstalled, error) {
+	installed := &openCodeInstalled{Providers: make(map[string][]string)}
+	data, err := os.ReadFile(openCodeInstalledPath())
+	if os.IsNotExist(err) {
+		return installed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read opencode install record: %w", err)
+	}
+	if err := json.Unmarshal(data, installed); err != nil {
+		return nil, fmt.Errorf("failed to parse opencode install record: %w", err)
+	}
+	if installed.Providers == nil {
+		installed.Providers = make(map[string][]string)
+	}
+	return installed, nil
+}
+
+// saveOpenCodeInstalled writes the installed providers record
+func saveOpenCodeInstalled(installed *openCodeInstalled) error {
+	data, err := json.MarshalIndent(installed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode opencode install record: %w", err)
+	}
+	path := openCodeInstalledPath()
+	if err := os.MkdirAll(filepath.Dir(path), constants.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create opencode install record dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, constants.ConfigFileMode); err != nil {
+		return fmt.Errorf("failed to write opencode install record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package setup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+func TestOpenCodeInstallerOwnership(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	aimConfig := `version: "1.0"
+keys:
+  work: {provider: deepseek, key: sk-secret}
+  home: {provider: deepseek, key: sk-home}
+tools:
+  opencode:
+    command: opencode
+    profiles:
+      deepseek: {provider: deepseek}
+`
+	if err := os.WriteFile(configFile, []byte(aimConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AIM_CONFIG_PATH", configFile)
+	t.Setenv("AIM_HOME", filepath.Join(dir, "aim"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+
+	manager := config.GetConfigManager()
+	if err := manager.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	installer := NewOpenCodeInstaller(manager)
+	configPath, err := installer.GetConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A user's own aim-* provider and a provider left by a since-removed key
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{"provider": {"aim-mine": {"name": "mine"}, "aim-gone": {"name": "gone"}, "local": {"name": "local"}},
+		"model": "aim-gone/m0", "small_model": "aim-gone/m0"}`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveOpenCodeInstalled(&openCodeInstalled{Providers: map[string][]string{configPath: {"aim-gone"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newKeyRequest("opencode")
+	req.Runtime.Provider = "deepseek"
+	if err := installer.Install(req); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk-secret") || strings.Contains(string(data), "sk-home") {
+		t.Errorf("opencode.json contains an API key: %s", data)
+	}
+	assertMode(t, configPath, 0600)
+
+	var written struct {
+		Provider   map[string]map[string]interface{} `json:"provider"`
+		Model      string                            `json:"model"`
+		SmallModel *string                           `json:"small_model"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"aim-work", "aim-home", "aim-mine", "local"} {
+		if _, ok := written.Provider[id]; !ok {
+			t.Errorf("provider %s missing: %s", id, data)
+		}
+	}
+	if _, ok := written.Provider["aim-gone"]; ok {
+		t.Errorf("provider of a removed key was kept: %s", data)
+	}
+	if written.Model != "aim-work/m1" || written.SmallModel != nil {
+		t.Errorf("model = %q, small_model = %v", written.Model, written.SmallModel)
+	}
+	// Each key has its own variable, even with the same provider
+	for id, want := range map[string]string{"aim-work": "{env:AIM_WORK_DEEPSEEK_API_KEY}", "aim-home": "{env:AIM_HOME_DEEPSEEK_API_KEY}"} {
+		options, _ := written.Provider[id]["options"].(map[string]interface{})
+		if apiKey, _ := options["apiKey"].(string); apiKey != want {
+			t.Errorf("%s apiKey = %q, want %q", id, apiKey, want)
+		}
+	}
+
+	installed, err := loadOpenCodeInstalled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := installed.Providers[configPath]; !reflect.DeepEqual(ids, []string{"aim-home", "aim-work"}) {
+		t.Errorf("installed providers = %v, want [aim-home aim-work]", ids)
+	}
+}
+
+func TestOpenCodeInstallerPlanKeyRename(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AIM_HOME", filepath.Join(dir, "aim"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+
+	installer := NewOpenCodeInstaller(nil)
+	configPath, err := installer.GetConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{"provider": {
+		"aim-work": {"name": "DeepSeek (aim: work)", "options": {"apiKey": "{env:AIM_WORK_DEEPSEEK_API_KEY}"}},
+		"aim-mine": {"name": "mine"}},
+		"model": "aim-work/m1", "small_model": "aim-mine/m2"}`
+	if err := os.WriteFile(configPath, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveOpenCodeInstalled(&openCodeInstalled{Providers: map[string][]string{configPath: {"aim-other", "aim-work"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := installer.PlanKeyRename("work", "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	var locations []string
+	for _, change := range changes {
+		files[change.Path] = change.Data
+		locations = append(locations, change.Location)
+	}
+	want := []string{"provider.aim-work", "provider.aim-job {env:...}", "model", "providers"}
+	if !reflect.DeepEqual(locations, want) {
+		t.Errorf("locations = %v, want %v", locations, want)
+	}
+
+	var written struct {
+		Provider   map[string]map[string]interface{} `json:"provider"`
+		Model      string                            `json:"model"`
+		SmallModel string                            `json:"small_model"`
+	}
+	if err := json.Unmarshal(files[configPath], &written); err != nil {
+		t.Fatal(err)
+	}
+	job := written.Provider["aim-job"]
+	options, _ := job["options"].(map[string]interface{})
+	if job["name"] != "DeepSeek (aim: job)" || options["apiKey"] != "{env:AIM_JOB_DEEPSEEK_API_KEY}" {
+		t.Errorf("aim-job = %v", job)
+	}
+	if _, ok := written.Provider["aim-work"]; ok || written.Provider["aim-mine"] == nil {
+		t.Errorf("providers = %v", written.Provider)
+	}
+	if written.Model != "aim-job/m1" || written.SmallModel != "aim-mine/m2" {
+		t.Errorf("model = %q, small_model = %q", written.Model, written.SmallModel)
+	}
+
+	var record openCodeInstalled
+	if err := json.Unmarshal(files[openCodeInstalledPath()], &record); err != nil {
+		t.Fatal(err)
+	}
+	if ids := record.Providers[configPath]; !reflect.DeepEqual(ids, []string{"aim-job", "aim-other"}) {
+		t.Errorf("installed providers = %v", ids)
+	}
+
+	// A user's own aim-* provider is not aim's to rename
+	if changes, err := installer.PlanKeyRename("mine", "yours"); err != nil || len(changes) != 0 {
+		t.Errorf("changes = %+v, %v, want none", changes, err)
+	}
+}
@@ -0,0 +1,85 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+)
+
+// QwenCodeInstaller Qwen Code installer
+type QwenCodeInstaller struct {
+	BaseInstaller
+}
+
+func NewQwenCodeInstaller() *QwenCodeInstaller {
+	return &QwenCodeInstaller{}
+}
+
+func (i *QwenCodeInstaller) Install(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	// security.auth.apiKey holds the key, so the file is only readable by its owner
+	return i.installSettings(req, configPath, &JSONParser{}, i.ConvertConfig, constants.SecretFileMode)
+}
+
+func (i *QwenCodeInstaller) Backup(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	return backupFile(configPath, req.BackupPath, constants.SecretFileMode)
+}
+
+func (i *QwenCodeInstaller) GetConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".qwen", "settings.json"), nil
+}
+
+func (i *QwenCodeInstaller) ValidateConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertConfig maps the runtime to Qwen Code's OpenAI auth settings
+func (i *QwenCodeInstaller) ConvertConfig(req *InstallRequest) (interface{}, error) {
+	runtime := req.Runtime
+
+	auth := map[string]interface{}{"selectedType": "openai"}
+	if runtime.BaseURL != "" {
+		auth["baseUrl"] = runtime.BaseURL
+	}
+	if runtime.APIKey != "" && runtime.AuthScheme != config.AuthSchemeNone {
+		auth["apiKey"] = runtime.APIKey
+	}
+
+	qwenConfig := map[string]interface{}{
+		"security":       map[string]interface{}{"auth": auth},
+		"managed_by_aim": managedByAIMField(req),
+	}
+	if runtime.Model != "" {
+		qwenConfig["model"] = map[string]interface{}{"name": runtime.Model}
+	}
+
+	return qwenConfig, nil
+}
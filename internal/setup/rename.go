@@ -0,0 +1,98 @@
+package setup
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// ManagedConfigChange is a pending rewrite of an AIM-managed tool config file
+type ManagedConfigChange struct {
+	Tool     string
+	Path     string
+	Location string // Field inside the tool config, e.g. "managed_by_aim.key"
+	Old      string
+	New      string
+	Data     []byte // Full rewritten file content
+}
+
+// PlanManagedKeyRename renders every AIM-managed tool config installed with oldKey
+// as if it had been installed with newKey. Nothing is written.
+func (sm *SetupManager) PlanManagedKeyRename(oldKey, newKey string) ([]ManagedConfigChange, error) {
+	paths := sm.ConfigPaths()
+
+	toolNames := make([]string, 0, len(paths))
+	for toolName := range paths {
+		toolNames = append(toolNames, toolName)
+	}
+	sort.Strings(toolNames)
+
+	var changes []ManagedConfigChange
+	for _, toolName := range toolNames {
+		path := paths[toolName]
+
+		if renamer, ok := sm.installers[toolName].(KeyRenamer); ok {
+			fileChanges, err := renamer.PlanKeyRename(oldKey, newKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s config: %w", toolName, err)
+			}
+			for i := range fileChanges {
+				fileChanges[i].Tool = toolName
+			}
+			changes = append(changes, fileChanges...)
+			continue
+		}
+
+		parser := parserForPath(path)
+		if parser == nil {
+			continue
+		}
+
+		managed, existing, err := (&BaseInstaller{}).checkManagedByAIM(path, parser)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s config: %w", toolName, err)
+		}
+		if !managed {
+			continue
+		}
+
+		meta, _ := existing["managed_by_aim"].(map[string]interface{})
+		if meta["key"] != oldKey {
+			continue
+		}
+
+		meta["key"] = newKey
+		fileChanges := []ManagedConfigChange{{Tool: toolName, Path: path, Location: "managed_by_aim.key", Old: oldKey, New: newKey}}
+
+		// The credential helper names the key explicitly
+		managedTool, _ := meta["tool"].(string)
+		if helper, ok := existing["apiKeyHelper"].(string); ok && helper == CredentialHelperCommand(managedTool, oldKey) {
+			newHelper := CredentialHelperCommand(managedTool, newKey)
+			existing["apiKeyHelper"] = newHelper
+			fileChanges = append(fileChanges, ManagedConfigChange{Tool: toolName, Path: path, Location: "apiKeyHelper", Old: helper, New: newHelper})
+		}
+
+		data, err := parser.Marshal(existing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s config: %w", toolName, err)
+		}
+		for i := range fileChanges {
+			fileChanges[i].Data = data
+		}
+		changes = append(changes, fileChanges...)
+	}
+
+	return changes, nil
+}
+
+// parserForPath picks a config parser from the file extension
+func parserForPath(path string) ConfigParser {
+	switch filepath.Ext(path) {
+	case ".json":
+		return &JSONParser{}
+	case ".toml":
+		return &TOMLParser{}
+	default:
+		return nil
+	}
+}
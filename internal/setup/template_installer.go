@@ -0,0 +1,150 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+	"github.com/fakecore/aim/internal/tool"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLParser implements YAML parsing
+type YAMLParser struct{}
+
+func (p *YAMLParser) Parse(data []byte, target *map[string]interface{}) error {
+	return yaml.Unmarshal(data, target)
+}
+
+func (p *YAMLParser) Marshal(config interface{}) ([]byte, error) {
+	return yaml.Marshal(config)
+}
+
+// TemplateInstaller installs the config file a tool declares under install in aim's config
+// A file aim manages is rewritten; otherwise declared values are merged and everything else is kept.
+type TemplateInstaller struct {
+	BaseInstaller
+	toolName string
+	install  *config.ToolInstall
+}
+
+// NewTemplateInstaller creates an installer for a tool's declared install target
+func NewTemplateInstaller(toolName string, install *config.ToolInstall) *TemplateInstaller {
+	return &TemplateInstaller{toolName: toolName, install: install}
+}
+
+// parser returns the parser for the declared file format
+func (i *TemplateInstaller) parser() ConfigParser {
+	switch i.install.InstallFormat() {
+	case config.InstallFormatJSON:
+		return &JSONParser{}
+	case config.InstallFormatTOML:
+		return &TOMLParser{}
+	}
+	return &YAMLParser{}
+}
+
+func (i *TemplateInstaller) Install(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	return i.installSettings(req, configPath, i.parser(), i.ConvertConfig, i.fileMode())
+}
+
+// fileMode restricts the installed file to its owner when a value holds the key
+func (i *TemplateInstaller) fileMode() os.FileMode {
+	for key, tmpl := range i.install.Values {
+		if strings.Contains(key, tool.KeyPlaceholder) || strings.Contains(tmpl, tool.KeyPlaceholder) {
+			return constants.SecretFileMode
+		}
+	}
+	return constants.ConfigFileMode
+}
+
+func (i *TemplateInstaller) Backup(req *InstallRequest) error {
+	configPath, err := i.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	return backupFile(configPath, req.BackupPath, i.fileMode())
+}
+
+func (i *TemplateInstaller) GetConfigPath() (string, error) {
+	path := i.install.Path
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	return path, nil
+}
+
+func (i *TemplateInstaller) ValidateConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := i.parser().Parse(data, &parsed); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertConfig expands the declared values into a nested map
+// Dotted keys become nested tables; keys and values may both use templates.
+// Values whose placeholders have no value are left out.
+func (i *TemplateInstaller) ConvertConfig(req *InstallRequest) (interface{}, error) {
+	converted := make(map[string]interface{})
+	for key, tmpl := range i.install.Values {
+		value, ok := tool.ExpandTemplate(tmpl, req.Runtime)
+		if !ok {
+			continue
+		}
+		path, ok := tool.ExpandTemplate(key, req.Runtime)
+		if !ok {
+			return nil, fmt.Errorf("install key '%s' has a placeholder without a value", key)
+		}
+		setDottedValue(converted, strings.Split(path, "."), value)
+	}
+
+	converted["managed_by_aim"] = managedByAIMField(req)
+
+	return converted, nil
+}
+
+// setDottedValue sets a value at a nested path, creating tables as needed
+func setDottedValue(target map[string]interface{}, path []string, value interface{}) {
+	for _, part := range path[:len(path)-1] {
+		next, ok := target[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			target[part] = next
+		}
+		target = next
+	}
+	target[path[len(path)-1]] = value
+}
+
+// mergeConfigMaps merges src into dst, descending into tables present in both
+func mergeConfigMaps(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeConfigMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,103 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+func newTemplateRequest() *InstallRequest {
+	return &InstallRequest{
+		SetupRequest: NewSetupRequest("mycli", "work"),
+		Runtime:      &config.RuntimeConfig{Tool: "mycli", Key: "work", APIKey: "sk-secret", BaseURL: "https://api.example.com"},
+	}
+}
+
+func TestTemplateInstallerKeyFileMode(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		values map[string]string
+		mode   os.FileMode
+	}{
+		{"with key", map[string]string{"api.key": "{key}", "api.base_url": "{base_url}"}, 0600},
+		{"without key", map[string]string{"api.base_url": "{base_url}"}, 0644},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".json")
+			installer := NewTemplateInstaller("mycli", &config.ToolInstall{Path: path, Values: tt.values})
+
+			if err := installer.Install(newTemplateRequest()); err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode().Perm(); got != tt.mode {
+				t.Errorf("mode = %o, want %o", got, tt.mode)
+			}
+		})
+	}
+}
+
+func TestTemplateInstallerRestrictsExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"theme": "dark"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installer := NewTemplateInstaller("mycli", &config.ToolInstall{Path: path, Values: map[string]string{"api.key": "{key}"}})
+
+	req := newTemplateRequest()
+	req.BackupPath = path + ".bak"
+	if err := installer.Backup(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := installer.Install(req); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Errorf("mode = %o, want 600", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"theme": "dark"`) || !strings.Contains(string(data), "managed_by_aim") {
+		t.Errorf("unmanaged file was not merged: %s", data)
+	}
+}
+
+func TestBackupFileKeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("KEY=sk-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := filepath.Join(dir, ".env.bak")
+	if err := backupFile(path, backup, 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Errorf("backup mode = %o, want 600", got)
+	}
+
+	if err := backupFile(filepath.Join(dir, "missing"), "", 0644); err != nil {
+		t.Errorf("backup of a missing file: %v", err)
+	}
+}
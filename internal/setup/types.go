@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/tool"
 )
 
 // SetupRequest setup request
@@ -113,26 +114,12 @@ func (r *SetupRequest) Normalize() {
 
 // GetCanonicalToolName gets the canonical name of the tool
 func (r *SetupRequest) GetCanonicalToolName() string {
-	switch r.ToolName {
-	case "cc":
-		return "claude-code"
-	case "claude-code":
-		return "claude-code"
-	case "codex":
-		return "codex"
-	default:
-		return r.ToolName
-	}
+	return tool.GetCanonicalName(r.ToolName)
 }
 
-// isSupportedTool checks if the tool is supported
+// isSupportedTool checks if the tool is builtin or declared in config
 func isSupportedTool(toolName string) bool {
-	for _, tool := range SupportedTools {
-		if tool == toolName {
-			return true
-		}
-	}
-	return false
+	return tool.IsToolSupported(toolName)
 }
 
 // isSupportedEnvType checks if the environment variable type is supported
@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// Aider variables for OpenAI-compatible endpoints
+const (
+	aiderOpenAIBaseEnv = "OPENAI_API_BASE"
+	aiderOpenAIKeyEnv  = "OPENAI_API_KEY"
+)
+
+// AiderNativeProvider is a provider aider (through litellm) knows by name
+type AiderNativeProvider struct {
+	Prefix string // Model name prefix, e.g. "deepseek" for deepseek/deepseek-chat
+	EnvKey string // Variable aider reads the key from
+}
+
+// aiderNativeHosts maps API hosts to aider's native providers
+// Endpoints on other hosts go through aider's OpenAI-compatible provider.
+var aiderNativeHosts = map[string]AiderNativeProvider{
+	"api.deepseek.com": {Prefix: "deepseek", EnvKey: "DEEPSEEK_API_KEY"},
+}
+
+// AiderNative returns the native aider provider serving a base URL, if any
+func AiderNative(baseURL string) (AiderNativeProvider, bool) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return AiderNativeProvider{}, false
+	}
+	native, ok := aiderNativeHosts[u.Hostname()]
+	return native, ok
+}
+
+// AiderModelName returns aider's name for a model on the runtime's endpoint,
+// e.g. deepseek/deepseek-chat or openai/glm-4.6
+func AiderModelName(runtimeConfig *config.RuntimeConfig, model string) string {
+	if native, ok := AiderNative(runtimeConfig.BaseURL); ok {
+		return native.Prefix + "/" + model
+	}
+	return "openai/" + model
+}
+
+// AiderEnvironmentPreparer maps the resolved profile to aider's model naming and environment
+type AiderEnvironmentPreparer struct {
+	settingsDir string // Temporary model settings, removed by Cleanup
+}
+
+// NewAiderEnvironmentPreparer creates an aider environment preparer
+func NewAiderEnvironmentPreparer() *AiderEnvironmentPreparer {
+	return &AiderEnvironmentPreparer{}
+}
+
+// PrepareEnvironment Prepares the environment for aider
+// The key goes to the native provider's variable or OPENAI_API_KEY with OPENAI_API_BASE,
+// the fast tier becomes --weak-model, and extra headers are passed in a temporary
+// model settings file since aider has no flag for them.
+func (a *AiderEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
+	if runtimeConfig.Tool != string(ToolTypeAider) {
+		return nil, nil, fmt.Errorf("aider preparer does not support tool: %s", runtimeConfig.Tool)
+	}
+
+	args := []string{}
+	envVars := make(map[string]string)
+
+	sendKey := runtimeConfig.APIKey != "" && runtimeConfig.AuthScheme != config.AuthSchemeNone
+	if native, ok := AiderNative(runtimeConfig.BaseURL); ok {
+		if sendKey {
+			envVars[native.EnvKey] = runtimeConfig.APIKey
+		}
+	} else {
+		if runtimeConfig.BaseURL != "" {
+			envVars[aiderOpenAIBaseEnv] = runtimeConfig.BaseURL
+		}
+		if sendKey {
+			envVars[aiderOpenAIKeyEnv] = runtimeConfig.APIKey
+		}
+	}
+
+	var models []string
+	if runtimeConfig.Model != "" {
+		models = append(models, AiderModelName(runtimeConfig, runtimeConfig.Model))
+		args = append(args, "--model", models[0])
+	}
+	if weak, ok := runtimeConfig.Models[provider.TierFast]; ok && weak != runtimeConfig.Model {
+		models = append(models, AiderModelName(runtimeConfig, weak))
+		args = append(args, "--weak-model", models[len(models)-1])
+	}
+
+	if len(runtimeConfig.Headers) > 0 && len(models) > 0 {
+		path, err := a.writeModelSettings(models, runtimeConfig.Headers)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, "--model-settings-file", path)
+	}
+
+	return args, envVars, nil
+}
+
+// aiderModelSettings is one entry of aider's .aider.model.settings.yml
+type aiderModelSettings struct {
+	Name        string                 `yaml:"name"`
+	ExtraParams map[string]interface{} `yaml:"extra_params"`
+}
+
+// writeModelSettings writes a model settings file that adds headers to every request
+func (a *AiderEnvironmentPreparer) writeModelSettings(models []string, headers map[string]string) (string, error) {
+	settings := make([]aiderModelSettings, 0, len(models))
+	for _, model := range models {
+		settings = append(settings, aiderModelSettings{
+			Name:        model,
+			ExtraParams: map[string]interface{}{"extra_headers": headers},
+		})
+	}
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode aider model settings: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "aim-aider-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create aider settings dir: %w", err)
+	}
+	a.settingsDir = dir
+	path := filepath.Join(dir, ".aider.model.settings.yml")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write aider model settings: %w", err)
+	}
+	return path, nil
+}
+
+// Cleanup removes the temporary model settings
+func (a *AiderEnvironmentPreparer) Cleanup() error {
+	if a.settingsDir == "" {
+		return nil
+	}
+	err := os.RemoveAll(a.settingsDir)
+	a.settingsDir = ""
+	return err
+}
+
+// ValidateEnvironment Validates the environment configuration for aider
+func (a *AiderEnvironmentPreparer) ValidateEnvironment(toolName string, provider string) error {
+	if toolName != string(ToolTypeAider) {
+		return fmt.Errorf("aider preparer does not support tool: %s", toolName)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package tool
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+// Gemini CLI variables for the Gemini API
+const (
+	geminiAPIKeyEnv        = "GEMINI_API_KEY"
+	geminiBaseURLEnv       = "GOOGLE_GEMINI_BASE_URL"
+	geminiModelEnv         = "GEMINI_MODEL"
+	geminiCustomHeadersEnv = "GEMINI_CLI_CUSTOM_HEADERS" // "Name:value" pairs separated by commas
+)
+
+// GeminiEnvironmentPreparer maps the resolved profile to Gemini CLI's environment
+type GeminiEnvironmentPreparer struct{}
+
+// NewGeminiEnvironmentPreparer creates a Gemini CLI environment preparer
+func NewGeminiEnvironmentPreparer() *GeminiEnvironmentPreparer {
+	return &GeminiEnvironmentPreparer{}
+}
+
+// PrepareEnvironment Prepares the environment for Gemini CLI
+// Gemini CLI speaks the Gemini API only, so the profile must point at a Gemini-compatible endpoint.
+func (g *GeminiEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
+	if runtimeConfig.Tool != string(ToolTypeGeminiCLI) {
+		return nil, nil, fmt.Errorf("gemini-cli preparer does not support tool: %s", runtimeConfig.Tool)
+	}
+
+	args := []string{}
+	envVars := make(map[string]string)
+
+	if runtimeConfig.APIKey != "" && runtimeConfig.AuthScheme != config.AuthSchemeNone {
+		envVars[geminiAPIKeyEnv] = runtimeConfig.APIKey
+	}
+	if runtimeConfig.BaseURL != "" {
+		envVars[geminiBaseURLEnv] = runtimeConfig.BaseURL
+	}
+	if runtimeConfig.Model != "" {
+		envVars[geminiModelEnv] = runtimeConfig.Model
+	}
+
+	if len(runtimeConfig.Headers) > 0 {
+		pairs := make([]string, 0, len(runtimeConfig.Headers))
+		for _, name := range sortedKeys(runtimeConfig.Headers) {
+			pairs = append(pairs, name+":"+runtimeConfig.Headers[name])
+		}
+		envVars[geminiCustomHeadersEnv] = strings.Join(pairs, ",")
+	}
+
+	return args, envVars, nil
+}
+
+// ValidateEnvironment Validates the environment configuration for Gemini CLI
+func (g *GeminiEnvironmentPreparer) ValidateEnvironment(toolName string, provider string) error {
+	if toolName != string(ToolTypeGeminiCLI) {
+		return fmt.Errorf("gemini-cli preparer does not support tool: %s", toolName)
+	}
+	return nil
+}
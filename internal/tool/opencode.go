@@ -0,0 +1,168 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// opencode config settings
+const (
+	openCodeConfigEnv = "OPENCODE_CONFIG" // Extra config file opencode merges over the global one
+	OpenCodeSchema    = "https://opencode.ai/config.json"
+	openCodeNPM       = "@ai-sdk/openai-compatible"
+)
+
+// OpenCodeProviderPrefix starts the ids of the opencode providers aim writes
+// The prefix only names them; which ones aim owns is recorded by 'aim setup install opencode'.
+const OpenCodeProviderPrefix = "aim-"
+
+// OpenCodeProviderID returns the opencode provider id for an aim key, e.g. "aim-work"
+func OpenCodeProviderID(keyName string) string {
+	return OpenCodeProviderPrefix + keyName
+}
+
+// OpenCodeProvider builds the opencode provider entry for a runtime
+// The key and secret headers are {env:...} references to variables scoped to the key,
+// so providers of several keys can share one opencode.json; the returned variables carry them.
+func OpenCodeProvider(runtimeConfig *config.RuntimeConfig) (map[string]interface{}, map[string]string) {
+	envVars := make(map[string]string)
+	secret := func(envKey, value string) string {
+		envKey = config.KeyEnvName(runtimeConfig.Key, envKey)
+		envVars[envKey] = value
+		return "{env:" + envKey + "}"
+	}
+
+	options := map[string]interface{}{}
+	if runtimeConfig.BaseURL != "" {
+		options["baseURL"] = runtimeConfig.BaseURL
+	}
+	if runtimeConfig.Timeout > 0 {
+		options["timeout"] = runtimeConfig.Timeout.Milliseconds()
+	}
+	if len(runtimeConfig.QueryParams) > 0 {
+		options["queryParams"] = runtimeConfig.QueryParams
+	}
+
+	headers := make(map[string]string)
+	for name, value := range runtimeConfig.Headers {
+		if runtimeConfig.SecretHeaders[name] {
+			value = secret(config.HeaderEnvName(name), value)
+		}
+		headers[name] = value
+	}
+
+	sendKey := runtimeConfig.APIKey != "" && runtimeConfig.AuthScheme != config.AuthSchemeNone
+	if sendKey {
+		envKey := runtimeConfig.EnvKeyName
+		if envKey == "" {
+			envKey = config.DefaultEnvKeyName(runtimeConfig.Provider)
+		}
+		if len(runtimeConfig.EnvHTTPHeaders) > 0 {
+			// Azure and x-api-key providers take the key in a header instead of a bearer token
+			for name := range runtimeConfig.EnvHTTPHeaders {
+				headers[name] = secret(envKey, runtimeConfig.APIKey)
+			}
+		} else {
+			options["apiKey"] = secret(envKey, runtimeConfig.APIKey)
+		}
+	}
+	if len(headers) > 0 {
+		options["headers"] = headers
+	}
+
+	// The profile's model and every tier and alias target, so opencode can switch between them
+	models := make(map[string]interface{})
+	for _, model := range runtimeConfig.Models {
+		models[model] = map[string]interface{}{"name": model}
+	}
+	if runtimeConfig.Model != "" {
+		models[runtimeConfig.Model] = map[string]interface{}{"name": runtimeConfig.Model}
+	}
+
+	entry := map[string]interface{}{
+		"npm":     openCodeNPM,
+		"name":    fmt.Sprintf("%s (aim: %s)", runtimeConfig.DisplayName, runtimeConfig.Key),
+		"options": options,
+		"models":  models,
+	}
+	return entry, envVars
+}
+
+// OpenCodeModelRefs returns opencode's provider/model references for the runtime's model
+// and, when the profile has one, the fast tier used as small_model
+func OpenCodeModelRefs(runtimeConfig *config.RuntimeConfig) (model, smallModel string) {
+	id := OpenCodeProviderID(runtimeConfig.Key)
+	if runtimeConfig.Model != "" {
+		model = id + "/" + runtimeConfig.Model
+	}
+	if fast, ok := runtimeConfig.Models[provider.TierFast]; ok && fast != runtimeConfig.Model {
+		smallModel = id + "/" + fast
+	}
+	return model, smallModel
+}
+
+// OpenCodeEnvironmentPreparer points opencode at a generated config for the resolved profile
+type OpenCodeEnvironmentPreparer struct{}
+
+// NewOpenCodeEnvironmentPreparer creates an opencode environment preparer
+func NewOpenCodeEnvironmentPreparer() *OpenCodeEnvironmentPreparer {
+	return &OpenCodeEnvironmentPreparer{}
+}
+
+// OpenCodeConfigPath returns the managed config aim generates for a key
+// It only references the key through the environment, so it outlives the run and
+// the OPENCODE_CONFIG printed by 'aim setup env' keeps working.
+func OpenCodeConfigPath(keyName string) string {
+	return filepath.Join(config.GetAIMHome(), "opencode", OpenCodeProviderID(keyName)+".json")
+}
+
+// PrepareEnvironment Prepares the environment for opencode
+// It writes the key's provider to a managed opencode.json and selects it through
+// OPENCODE_CONFIG, which opencode merges over the user's own config.
+func (o *OpenCodeEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
+	if runtimeConfig.Tool != string(ToolTypeOpenCode) {
+		return nil, nil, fmt.Errorf("opencode preparer does not support tool: %s", runtimeConfig.Tool)
+	}
+
+	entry, envVars := OpenCodeProvider(runtimeConfig)
+	openCodeConfig := map[string]interface{}{
+		"$schema":  OpenCodeSchema,
+		"provider": map[string]interface{}{OpenCodeProviderID(runtimeConfig.Key): entry},
+	}
+	model, smallModel := OpenCodeModelRefs(runtimeConfig)
+	if model != "" {
+		openCodeConfig["model"] = model
+	}
+	if smallModel != "" {
+		openCodeConfig["small_model"] = smallModel
+	}
+
+	data, err := json.MarshalIndent(openCodeConfig, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode opencode config: %w", err)
+	}
+	path := OpenCodeConfigPath(runtimeConfig.Key)
+	if err := os.MkdirAll(filepath.Dir(path), constants.ConfigDirMode); err != nil {
+		return nil, nil, fmt.Errorf("failed to create opencode config dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, constants.ConfigFileMode); err != nil {
+		return nil, nil, fmt.Errorf("failed to write opencode config: %w", err)
+	}
+
+	envVars[openCodeConfigEnv] = path
+	return []string{}, envVars, nil
+}
+
+// ValidateEnvironment Validates the environment configuration for opencode
+func (o *OpenCodeEnvironmentPreparer) ValidateEnvironment(toolName string, provider string) error {
+	if toolName != string(ToolTypeOpenCode) {
+		return fmt.Errorf("opencode preparer does not support tool: %s", toolName)
+	}
+	return nil
+}
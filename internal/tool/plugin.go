@@ -0,0 +1,261 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/constants"
+)
+
+// PluginSchemaVersion is the preparer plugin protocol version aim speaks
+// Plugins answer with the version they implement; newer versions are rejected.
+const PluginSchemaVersion = 1
+
+// DefaultPluginTimeout bounds a preparer plugin run when the tool config sets no timeout
+const DefaultPluginTimeout = 10 * time.Second
+
+// PluginRequest is the JSON document a preparer plugin reads from stdin
+type PluginRequest struct {
+	SchemaVersion int               `json:"schema_version"`
+	Tool          string            `json:"tool"`
+	Key           string            `json:"key"`
+	Profile       string            `json:"profile"`
+	Provider      string            `json:"provider"`
+	ProviderKind  string            `json:"provider_kind,omitempty"`
+	APIKey        string            `json:"api_key"`
+	BaseURL       string            `json:"base_url"`
+	Model         string            `json:"model"`
+	TimeoutMS     int64             `json:"timeout_ms"`
+	DisplayName   string            `json:"display_name,omitempty"`
+	EnvKey        string            `json:"env_key,omitempty"`
+	WireAPI       string            `json:"wire_api,omitempty"`
+	AuthScheme    string            `json:"auth_scheme,omitempty"`
+	QueryParams   map[string]string `json:"query_params,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Models        map[string]string `json:"models,omitempty"`
+	Env           map[string]string `json:"env"`      // Environment aim already built for the tool
+	WorkDir       string            `json:"work_dir"` // Per-run directory for relative file paths, removed after the tool exits
+}
+
+// PluginFile is a file the plugin asks aim to write before the tool starts
+type PluginFile struct {
+	Path    string `json:"path"`           // Relative to work_dir; absolute paths and .. are rejected
+	Content string `json:"content"`        // File contents
+	Mode    string `json:"mode,omitempty"` // Octal permissions (default: 0600)
+}
+
+// PluginResponse is the JSON document a preparer plugin writes to stdout
+type PluginResponse struct {
+	SchemaVersion int               `json:"schema_version"`
+	Args          []string          `json:"args,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+	Files         []PluginFile      `json:"files,omitempty"`
+	Cleanup       []string          `json:"cleanup,omitempty"` // Paths inside work_dir removed after the tool exits
+}
+
+// NewPluginRequest builds the request for a runtime config
+func NewPluginRequest(runtimeConfig *config.RuntimeConfig, workDir string) *PluginRequest {
+	return &PluginRequest{
+		SchemaVersion: PluginSchemaVersion,
+		Tool:          runtimeConfig.Tool,
+		Key:           runtimeConfig.Key,
+		Profile:       runtimeConfig.Profile,
+		Provider:      runtimeConfig.Provider,
+		ProviderKind:  runtimeConfig.ProviderKind,
+		APIKey:        runtimeConfig.APIKey,
+		BaseURL:       runtimeConfig.BaseURL,
+		Model:         runtimeConfig.Model,
+		TimeoutMS:     runtimeConfig.Timeout.Milliseconds(),
+		DisplayName:   runtimeConfig.DisplayName,
+		EnvKey:        runtimeConfig.EnvKeyName,
+		WireAPI:       runtimeConfig.WireAPI,
+		AuthScheme:    runtimeConfig.AuthScheme,
+		QueryParams:   runtimeConfig.QueryParams,
+		Headers:       runtimeConfig.Headers,
+		Models:        runtimeConfig.Models,
+		Env:           runtimeConfig.EnvVars,
+		WorkDir:       workDir,
+	}
+}
+
+// PluginPreparer runs an external preparer executable
+// It writes the files the plugin returns into the work dir and removes the
+// plugin's cleanup paths and the work dir in Cleanup.
+type PluginPreparer struct {
+	plugin  *config.ToolPreparer
+	workDir string
+	cleanup []string
+}
+
+// NewPluginPreparer creates a preparer for a tool's preparer config
+func NewPluginPreparer(plugin *config.ToolPreparer) *PluginPreparer {
+	return &PluginPreparer{plugin: plugin}
+}
+
+// Timeout returns how long the plugin may run
+func (p *PluginPreparer) Timeout() time.Duration {
+	if p.plugin.Timeout > 0 {
+		return time.Duration(p.plugin.Timeout) * time.Millisecond
+	}
+	return DefaultPluginTimeout
+}
+
+// Call runs the plugin with a request and returns its validated response
+// Plugin stderr is passed through so plugins can report progress and errors.
+func (p *PluginPreparer) Call(req *PluginRequest) (*PluginResponse, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preparer request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout())
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.plugin.Command, p.plugin.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("preparer '%s' timed out after %s", p.plugin.Command, p.Timeout())
+		}
+		return nil, fmt.Errorf("preparer '%s' failed: %w", p.plugin.Command, err)
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("preparer '%s' returned invalid JSON: %w", p.plugin.Command, err)
+	}
+	if err := resp.Validate(); err != nil {
+		return nil, fmt.Errorf("preparer '%s': %w", p.plugin.Command, err)
+	}
+	return &resp, nil
+}
+
+// Validate checks the schema version and file entries of a response
+func (r *PluginResponse) Validate() error {
+	if r.SchemaVersion == 0 {
+		return fmt.Errorf("response has no schema_version (aim speaks version %d)", PluginSchemaVersion)
+	}
+	if r.SchemaVersion > PluginSchemaVersion {
+		return fmt.Errorf("response uses schema_version %d, this aim supports up to %d", r.SchemaVersion, PluginSchemaVersion)
+	}
+	for _, file := range r.Files {
+		if file.Path == "" {
+			return fmt.Errorf("file entry has no path")
+		}
+		if err := checkWorkDirPath(file.Path); err != nil {
+			return fmt.Errorf("file '%s': %w", file.Path, err)
+		}
+		if _, err := fileMode(file.Mode); err != nil {
+			return fmt.Errorf("file '%s': %w", file.Path, err)
+		}
+	}
+	for _, path := range r.Cleanup {
+		if err := checkWorkDirPath(path); err != nil {
+			return fmt.Errorf("cleanup '%s': %w", path, err)
+		}
+	}
+	return nil
+}
+
+// checkWorkDirPath rejects paths that would leave the work dir
+// A buggy plugin must not be able to make aim write or delete files elsewhere.
+func checkWorkDirPath(path string) error {
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path must be relative to work_dir")
+	}
+	if !filepath.IsLocal(path) {
+		return fmt.Errorf("path escapes work_dir")
+	}
+	return nil
+}
+
+// PrepareEnvironment runs the plugin and writes the files it returns
+func (p *PluginPreparer) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
+	workDir, err := os.MkdirTemp("", "aim-"+runtimeConfig.Tool+"-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create preparer work dir: %w", err)
+	}
+	p.workDir = workDir
+
+	resp, err := p.Call(NewPluginRequest(runtimeConfig, workDir))
+	if err != nil {
+		return nil, nil, err
+	}
+	p.cleanup = append(p.cleanup, resp.Cleanup...)
+
+	for _, file := range resp.Files {
+		path := filepath.Join(workDir, file.Path)
+		mode, _ := fileMode(file.Mode)
+		if err := os.MkdirAll(filepath.Dir(path), constants.ConfigDirMode); err != nil {
+			return nil, nil, fmt.Errorf("failed to create directory for '%s': %w", file.Path, err)
+		}
+		if err := os.WriteFile(path, []byte(file.Content), mode); err != nil {
+			return nil, nil, fmt.Errorf("failed to write '%s': %w", file.Path, err)
+		}
+		// WriteFile keeps the mode of a file an earlier entry already wrote
+		if err := os.Chmod(path, mode); err != nil {
+			return nil, nil, fmt.Errorf("failed to set mode of '%s': %w", file.Path, err)
+		}
+	}
+
+	env := resp.Env
+	if env == nil {
+		env = make(map[string]string)
+	}
+	return resp.Args, env, nil
+}
+
+// ValidateEnvironment accepts any provider; the plugin decides what it supports
+func (p *PluginPreparer) ValidateEnvironment(toolName string, provider string) error {
+	return nil
+}
+
+// Cleanup removes the plugin's cleanup paths and the work dir
+// Cleanup paths were checked to stay inside the work dir when the response was validated.
+func (p *PluginPreparer) Cleanup() error {
+	var errs []string
+	for _, path := range p.cleanup {
+		if p.workDir == "" || checkWorkDirPath(path) != nil {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(p.workDir, path)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if p.workDir != "" {
+		if err := os.RemoveAll(p.workDir); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	p.cleanup, p.workDir = nil, ""
+	if len(errs) > 0 {
+		return fmt.Errorf("preparer cleanup failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// fileMode parses an octal mode, defaulting to 0600
+func fileMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return 0600, nil
+	}
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || parsed > 0777 {
+		return 0, fmt.Errorf("invalid mode '%s'", mode)
+	}
+	return os.FileMode(parsed), nil
+}
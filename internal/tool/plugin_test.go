@@ -0,0 +1,139 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+// helperResponses are the stdout documents the helper preparer prints, by behavior name
+var helperResponses = map[string]string{
+	"ok": `{"schema_version": 1, "args": ["--flag"], "env": {"PLUGIN_VAR": "1"},
+		"files": [{"path": "conf/settings.json", "content": "{}", "mode": "0640"}, {"path": "token", "content": "secret"}],
+		"cleanup": ["conf"]}`,
+	"schema0":        `{"args": ["--flag"]}`,
+	"schema_new":     `{"schema_version": 99}`,
+	"invalid_json":   `not json`,
+	"bad_mode":       `{"schema_version": 1, "files": [{"path": "a", "content": "", "mode": "rw"}]}`,
+	"file_absolute":  `{"schema_version": 1, "files": [{"path": "/tmp/aim-plugin-test", "content": ""}]}`,
+	"file_escape":    `{"schema_version": 1, "files": [{"path": "conf/../../escape", "content": ""}]}`,
+	"cleanup_escape": `{"schema_version": 1, "cleanup": ["../"]}`,
+}
+
+// TestHelperProcess is re-executed by the tests below as a stand-in preparer plugin
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	behavior := os.Args[len(os.Args)-1]
+
+	var req PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil || req.SchemaVersion != PluginSchemaVersion || req.WorkDir == "" {
+		fmt.Fprintf(os.Stderr, "bad request: %v", err)
+		os.Exit(2)
+	}
+
+	if behavior == "sleep" {
+		time.Sleep(10 * time.Second)
+	}
+	fmt.Print(helperResponses[behavior])
+	os.Exit(0)
+}
+
+// newHelperPreparer returns a preparer that runs TestHelperProcess with the given behavior
+func newHelperPreparer(t *testing.T, behavior string, timeoutMS int) *PluginPreparer {
+	t.Helper()
+	t.Setenv("GO_WANT_HELPER_PROCESS", "1")
+	return NewPluginPreparer(&config.ToolPreparer{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess", "--", behavior},
+		Timeout: timeoutMS,
+	})
+}
+
+func helperRuntime() *config.RuntimeConfig {
+	return &config.RuntimeConfig{Tool: "mycli", Key: "work", APIKey: "sk-test", Model: "m1"}
+}
+
+func TestPluginPreparerWritesFiles(t *testing.T) {
+	preparer := newHelperPreparer(t, "ok", 0)
+
+	args, env, err := preparer.PrepareEnvironment(helperRuntime())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 1 || args[0] != "--flag" || env["PLUGIN_VAR"] != "1" {
+		t.Errorf("args = %v, env = %v", args, env)
+	}
+
+	workDir := preparer.workDir
+	modes := map[string]os.FileMode{"conf/settings.json": 0640, "token": 0600}
+	for path, want := range modes {
+		info, err := os.Stat(filepath.Join(workDir, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %o, want %o", path, got, want)
+		}
+	}
+
+	if err := preparer.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(workDir); !os.IsNotExist(err) {
+		t.Errorf("work dir %s was not removed", workDir)
+	}
+}
+
+func TestPluginPreparerErrors(t *testing.T) {
+	tests := []struct {
+		behavior  string
+		timeoutMS int
+		want      string
+	}{
+		{"sleep", 200, "timed out"},
+		{"schema0", 0, "no schema_version"},
+		{"schema_new", 0, "schema_version 99"},
+		{"invalid_json", 0, "invalid JSON"},
+		{"bad_mode", 0, "invalid mode"},
+		{"file_absolute", 0, "relative to work_dir"},
+		{"file_escape", 0, "escapes work_dir"},
+		{"cleanup_escape", 0, "escapes work_dir"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			preparer := newHelperPreparer(t, tt.behavior, tt.timeoutMS)
+			defer preparer.Cleanup()
+
+			_, _, err := preparer.PrepareEnvironment(helperRuntime())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluginCleanupStaysInWorkDir(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "keep")
+	if err := os.WriteFile(outside, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	workDir := t.TempDir()
+
+	// Cleanup re-checks paths even if a response skipped validation
+	preparer := &PluginPreparer{workDir: workDir, cleanup: []string{outside, "../" + filepath.Base(filepath.Dir(outside))}}
+	if err := preparer.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the work dir was removed: %v", err)
+	}
+}
@@ -1,9 +1,14 @@
 package tool
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/provider"
 )
 
 // DefaultEnvironmentPreparer Default environment preparer (for claude-code and cc)
@@ -14,13 +19,56 @@ func NewDefaultEnvironmentPreparer() *DefaultEnvironmentPreparer {
 	return &DefaultEnvironmentPreparer{}
 }
 
+// claudeTierEnvVars maps model tiers to the Claude Code variables that pick the model for each family
+var claudeTierEnvVars = map[string]string{
+	provider.TierFast:     "ANTHROPIC_DEFAULT_HAIKU_MODEL",
+	provider.TierBalanced: "ANTHROPIC_DEFAULT_SONNET_MODEL",
+	provider.TierStrong:   "ANTHROPIC_DEFAULT_OPUS_MODEL",
+}
+
+// Claude Code variables for the auth scheme and extra headers
+const (
+	claudeAuthTokenEnv     = "ANTHROPIC_AUTH_TOKEN"     // Sent as Authorization: Bearer
+	claudeAPIKeyEnv        = "ANTHROPIC_API_KEY"        // Sent as x-api-key
+	claudeCustomHeadersEnv = "ANTHROPIC_CUSTOM_HEADERS" // "Name: value" lines
+)
+
 // PrepareEnvironment Prepares the environment for default tools
 // Note: Environment variables are now handled by the resolver based on tool configuration
-// This preparer only handles tool-specific command line arguments
+// This preparer only adds the model tier variables that the profile env doesn't set itself,
+// moves the key for the x-api-key auth scheme and passes extra headers
 func (d *DefaultEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
 	// Default tools don't need special command line arguments
 	args := []string{}
-	envVars := make(map[string]string) // Empty - rely on tool configuration env_vars
+	envVars := make(map[string]string)
+
+	// Claude Code sends ANTHROPIC_API_KEY as x-api-key, so move the mapped token there
+	if runtimeConfig.AuthScheme == config.AuthSchemeXAPIKey {
+		if token, ok := runtimeConfig.EnvVars[claudeAuthTokenEnv]; ok {
+			delete(runtimeConfig.EnvVars, claudeAuthTokenEnv)
+			envVars[claudeAPIKeyEnv] = token
+		}
+	}
+
+	if len(runtimeConfig.Headers) > 0 {
+		if _, exists := runtimeConfig.EnvVars[claudeCustomHeadersEnv]; !exists {
+			lines := make([]string, 0, len(runtimeConfig.Headers))
+			for _, name := range sortedKeys(runtimeConfig.Headers) {
+				lines = append(lines, name+": "+runtimeConfig.Headers[name])
+			}
+			envVars[claudeCustomHeadersEnv] = strings.Join(lines, "\n")
+		}
+	}
+
+	for tier, envKey := range claudeTierEnvVars {
+		model, ok := runtimeConfig.Models[tier]
+		if !ok {
+			continue
+		}
+		if _, exists := runtimeConfig.EnvVars[envKey]; !exists {
+			envVars[envKey] = model
+		}
+	}
 
 	return args, envVars, nil
 }
@@ -55,24 +103,13 @@ func (c *CodexEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.Runt
 
 	// Codex is configured through command line arguments, environment variables are handled by resolver's config file env_mapping
 	args := []string{}
-	envVars := make(map[string]string) // Empty - rely on config file env_mapping
+	envVars := make(map[string]string) // Only secret header values, the rest relies on config file env_mapping
 
 	// If provider is specified, add configuration parameters
+	// Display name, env key, wire API, query parameters and headers come from the resolved provider wiring
 	if runtimeConfig.Provider != "" {
-		// Set provider display name
-		var providerName string
-		switch runtimeConfig.Provider {
-		case "glm", "glm-coding":
-			providerName = "GLM"
-		case "deepseek", "deepseek-coding":
-			providerName = "DeepSeek"
-		case "kimi", "kimi-coding":
-			providerName = "Kimi"
-		case "qwen", "qwen-coding":
-			providerName = "Qwen"
-		case "openai":
-			providerName = "OpenAI"
-		default:
+		providerName := runtimeConfig.DisplayName
+		if providerName == "" {
 			providerName = runtimeConfig.Provider
 		}
 
@@ -88,16 +125,50 @@ func (c *CodexEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.Runt
 			)
 		}
 
-		// Add env_key configuration - find provider-specific environment variable name from EnvVars
-		// resolver.buildEnvVars will set the corresponding environment variable based on toolProvider.EnvKeyName
-		envKeyName := c.getEnvKeyNameFromEnvVars(runtimeConfig.EnvVars)
-		if envKeyName != "" {
+		// Add env_key configuration - resolver.buildEnvVars sets this variable to the key
+		// Other auth schemes send the key through env_http_headers instead of a bearer token
+		if runtimeConfig.EnvKeyName != "" && runtimeConfig.AuthScheme != config.AuthSchemeXAPIKey {
 			args = append(args,
-				"-c", fmt.Sprintf("model_providers.%s.env_key=%s", runtimeConfig.Provider, envKeyName),
+				"-c", fmt.Sprintf("model_providers.%s.env_key=%s", runtimeConfig.Provider, runtimeConfig.EnvKeyName),
 			)
 		}
 
-		// Actual setting of environment variables is handled by resolver.buildEnvVars
+		// Add wire protocol, query parameters and headers
+		if runtimeConfig.WireAPI != "" {
+			args = append(args,
+				"-c", fmt.Sprintf("model_providers.%s.wire_api=%s", runtimeConfig.Provider, runtimeConfig.WireAPI),
+			)
+		}
+		if len(runtimeConfig.QueryParams) > 0 {
+			args = append(args,
+				"-c", fmt.Sprintf("model_providers.%s.query_params=%s", runtimeConfig.Provider, tomlInlineTable(runtimeConfig.QueryParams)),
+			)
+		}
+		// Headers holding secrets go through the environment so they stay off the command line
+		httpHeaders := make(map[string]string)
+		envHTTPHeaders := make(map[string]string)
+		for name, envKey := range runtimeConfig.EnvHTTPHeaders {
+			envHTTPHeaders[name] = envKey
+		}
+		for name, value := range runtimeConfig.Headers {
+			if runtimeConfig.SecretHeaders[name] {
+				envKey := config.HeaderEnvName(name)
+				envVars[envKey] = value
+				envHTTPHeaders[name] = envKey
+			} else {
+				httpHeaders[name] = value
+			}
+		}
+		if len(httpHeaders) > 0 {
+			args = append(args,
+				"-c", fmt.Sprintf("model_providers.%s.http_headers=%s", runtimeConfig.Provider, tomlInlineTable(httpHeaders)),
+			)
+		}
+		if len(envHTTPHeaders) > 0 {
+			args = append(args,
+				"-c", fmt.Sprintf("model_providers.%s.env_http_headers=%s", runtimeConfig.Provider, tomlInlineTable(envHTTPHeaders)),
+			)
+		}
 	}
 
 	// If model is specified, add model configuration
@@ -105,28 +176,41 @@ func (c *CodexEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.Runt
 		args = append(args, "-c", fmt.Sprintf("model=%s", runtimeConfig.Model))
 	}
 
+	// Codex has no model families; expose each tier as a profile, selectable with --profile <tier>
+	if runtimeConfig.Provider != "" {
+		for _, tier := range provider.ModelTiers {
+			model, ok := runtimeConfig.Models[tier]
+			if !ok {
+				continue
+			}
+			args = append(args,
+				"-c", fmt.Sprintf("profiles.%s.model=%s", tier, model),
+				"-c", fmt.Sprintf("profiles.%s.model_provider=%s", tier, runtimeConfig.Provider),
+			)
+		}
+	}
+
 	return args, envVars, nil
 }
 
-// getEnvKeyNameFromEnvVars Finds provider-specific API key environment variable name from environment variable mapping
-// For example: If environment variables contain GLM_API_KEY, return "GLM_API_KEY"
-func (c *CodexEnvironmentPreparer) getEnvKeyNameFromEnvVars(envVars map[string]string) string {
-	// Common provider-specific API key environment variable names
-	providerEnvKeys := []string{
-		"GLM_API_KEY",
-		"DEEPSEEK_API_KEY",
-		"KIMI_API_KEY",
-		"QWEN_API_KEY",
-		"OPENAI_API_KEY",
+// tomlInlineTable renders a string map as a TOML inline table for codex -c overrides
+func tomlInlineTable(values map[string]string) string {
+	keys := sortedKeys(values)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", strconv.Quote(k), strconv.Quote(values[k]))
 	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
 
-	for _, keyName := range providerEnvKeys {
-		if _, exists := envVars[keyName]; exists {
-			return keyName
-		}
+// sortedKeys returns the keys of a string map in stable order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
 	}
-
-	return ""
+	sort.Strings(keys)
+	return keys
 }
 
 // ValidateEnvironment Validates the environment configuration for Codex tool
@@ -140,20 +224,34 @@ func (c *CodexEnvironmentPreparer) ValidateEnvironment(toolName string, provider
 	return nil
 }
 
+// Cleaner is implemented by preparers that leave files behind until the tool exits
+type Cleaner interface {
+	Cleanup() error
+}
+
 // EnvironmentPreparerManager Environment preparer manager
 type EnvironmentPreparerManager struct {
 	preparers map[string]EnvironmentPreparer // Use standard name as key
+	cleaners  []Cleaner                      // Preparers that ran and need cleanup
 }
 
 // NewEnvironmentPreparerManager Creates an environment preparer manager
 func NewEnvironmentPreparerManager() *EnvironmentPreparerManager {
 	defaultPreparer := NewDefaultEnvironmentPreparer()
 	codexPreparer := NewCodexEnvironmentPreparer()
+	aiderPreparer := NewAiderEnvironmentPreparer()
+	geminiPreparer := NewGeminiEnvironmentPreparer()
+	qwenCodePreparer := NewQwenCodeEnvironmentPreparer()
+	openCodePreparer := NewOpenCodeEnvironmentPreparer()
 
 	return &EnvironmentPreparerManager{
 		preparers: map[string]EnvironmentPreparer{
 			string(ToolTypeClaudeCode): defaultPreparer, // Use standard name
 			string(ToolTypeCodex):      codexPreparer,
+			string(ToolTypeAider):      aiderPreparer,
+			string(ToolTypeGeminiCLI):  geminiPreparer,
+			string(ToolTypeQwenCode):   qwenCodePreparer,
+			string(ToolTypeOpenCode):   openCodePreparer,
 		},
 	}
 }
@@ -169,16 +267,44 @@ func (m *EnvironmentPreparerManager) GetPreparer(canonicalName string) (Environm
 }
 
 // PrepareEnvironment Prepares tool environment
+// Tools declared only in config have no preparer and rely on their env mappings.
+// An external preparer declared in config runs next; its env overrides and its args
+// follow the builtin preparer's, and the declared argument templates come last.
 func (m *EnvironmentPreparerManager) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
 	// Get standard name
 	canonicalName := GetCanonicalName(runtimeConfig.Tool)
 
-	preparer, err := m.GetPreparer(canonicalName)
-	if err != nil {
-		return nil, nil, err
+	def, declared := ToolsRegistry[ToolType(canonicalName)]
+	args := []string{}
+	envVars := make(map[string]string)
+	if preparer, exists := m.preparers[canonicalName]; exists {
+		if cleaner, ok := preparer.(Cleaner); ok {
+			m.cleaners = append(m.cleaners, cleaner)
+		}
+		var err error
+		args, envVars, err = preparer.PrepareEnvironment(runtimeConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if !declared {
+		return nil, nil, fmt.Errorf("no environment preparer found for tool: %s", canonicalName)
+	}
+
+	if def.Preparer != nil {
+		plugin := NewPluginPreparer(def.Preparer)
+		m.cleaners = append(m.cleaners, plugin)
+		pluginArgs, pluginEnv, err := plugin.PrepareEnvironment(runtimeConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, pluginArgs...)
+		for key, value := range pluginEnv {
+			envVars[key] = value
+		}
 	}
 
-	return preparer.PrepareEnvironment(runtimeConfig)
+	args = append(args, ExpandArgs(def.Args, runtimeConfig)...)
+	return args, envVars, nil
 }
 
 // ValidateEnvironment Validates tool environment configuration
@@ -188,8 +314,23 @@ func (m *EnvironmentPreparerManager) ValidateEnvironment(toolName string, provid
 
 	preparer, err := m.GetPreparer(canonicalName)
 	if err != nil {
+		if IsToolSupported(canonicalName) {
+			return nil
+		}
 		return err
 	}
 
 	return preparer.ValidateEnvironment(canonicalName, provider)
 }
+
+// Cleanup removes what the preparers that ran left behind; call it after the tool exits
+func (m *EnvironmentPreparerManager) Cleanup() error {
+	var errs []error
+	for _, cleaner := range m.cleaners {
+		if err := cleaner.Cleanup(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	m.cleaners = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+func TestDefaultPreparerTierEnvVars(t *testing.T) {
+	runtime := &config.RuntimeConfig{
+		Tool:   "claude-code",
+		Models: map[string]string{"fast": "m-fast", "strong": "m-strong", "think": "m-think"},
+		// The profile env pins opus itself, so aim leaves it alone
+		EnvVars: map[string]string{"ANTHROPIC_DEFAULT_OPUS_MODEL": "m-pinned"},
+	}
+
+	args, envVars, err := NewDefaultEnvironmentPreparer().PrepareEnvironment(runtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 0 {
+		t.Errorf("args = %v, want none", args)
+	}
+	want := map[string]string{"ANTHROPIC_DEFAULT_HAIKU_MODEL": "m-fast"}
+	if !reflect.DeepEqual(envVars, want) {
+		t.Errorf("env = %v, want %v", envVars, want)
+	}
+}
+
+func TestCodexPreparerTierProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		models   map[string]string
+		want     []string
+	}{
+		{
+			name:     "tiers in order",
+			provider: "glm",
+			models:   map[string]string{"strong": "m-strong", "fast": "m-fast", "think": "m-think"},
+			want: []string{
+				"-c", "model_provider=glm",
+				"-c", "model_providers.glm.name=glm",
+				"-c", "model=m1",
+				"-c", "profiles.fast.model=m-fast",
+				"-c", "profiles.fast.model_provider=glm",
+				"-c", "profiles.strong.model=m-strong",
+				"-c", "profiles.strong.model_provider=glm",
+			},
+		},
+		{
+			name:     "no tiers",
+			provider: "glm",
+			want: []string{
+				"-c", "model_provider=glm",
+				"-c", "model_providers.glm.name=glm",
+				"-c", "model=m1",
+			},
+		},
+		{
+			name:   "no provider",
+			models: map[string]string{"fast": "m-fast"},
+			want:   []string{"-c", "model=m1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &config.RuntimeConfig{Tool: "codex", Provider: tt.provider, Model: "m1", Models: tt.models}
+			args, _, err := NewCodexEnvironmentPreparer().PrepareEnvironment(runtime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(args, tt.want) {
+				t.Errorf("args = %v, want %v", args, tt.want)
+			}
+		})
+	}
+}
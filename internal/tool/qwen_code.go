@@ -0,0 +1,53 @@
+package tool
+
+import (
+	"fmt"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+// Qwen Code variables for OpenAI-compatible endpoints
+const (
+	qwenOpenAIBaseEnv  = "OPENAI_BASE_URL"
+	qwenOpenAIKeyEnv   = "OPENAI_API_KEY"
+	qwenOpenAIModelEnv = "OPENAI_MODEL"
+)
+
+// QwenCodeEnvironmentPreparer maps the resolved profile to Qwen Code's OpenAI environment
+type QwenCodeEnvironmentPreparer struct{}
+
+// NewQwenCodeEnvironmentPreparer creates a Qwen Code environment preparer
+func NewQwenCodeEnvironmentPreparer() *QwenCodeEnvironmentPreparer {
+	return &QwenCodeEnvironmentPreparer{}
+}
+
+// PrepareEnvironment Prepares the environment for Qwen Code
+// Qwen Code has no setting for extra headers, so profile headers are not forwarded.
+func (q *QwenCodeEnvironmentPreparer) PrepareEnvironment(runtimeConfig *config.RuntimeConfig) ([]string, map[string]string, error) {
+	if runtimeConfig.Tool != string(ToolTypeQwenCode) {
+		return nil, nil, fmt.Errorf("qwen-code preparer does not support tool: %s", runtimeConfig.Tool)
+	}
+
+	args := []string{}
+	envVars := make(map[string]string)
+
+	if runtimeConfig.BaseURL != "" {
+		envVars[qwenOpenAIBaseEnv] = runtimeConfig.BaseURL
+	}
+	if runtimeConfig.APIKey != "" && runtimeConfig.AuthScheme != config.AuthSchemeNone {
+		envVars[qwenOpenAIKeyEnv] = runtimeConfig.APIKey
+	}
+	if runtimeConfig.Model != "" {
+		envVars[qwenOpenAIModelEnv] = runtimeConfig.Model
+	}
+
+	return args, envVars, nil
+}
+
+// ValidateEnvironment Validates the environment configuration for Qwen Code
+func (q *QwenCodeEnvironmentPreparer) ValidateEnvironment(toolName string, provider string) error {
+	if toolName != string(ToolTypeQwenCode) {
+		return fmt.Errorf("qwen-code preparer does not support tool: %s", toolName)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package tool
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+	"github.com/fakecore/aim/internal/provider"
+)
+
+// templatePlaceholder matches {name} and {models.<alias>} placeholders in argument and install templates
+var templatePlaceholder = regexp.MustCompile(`\{([a-z_]+|models\.[^{}\s]+)\}`)
+
+// KeyPlaceholder is the template placeholder for the API key
+// Only install templates and environment values expand it; command lines are visible to other users.
+const KeyPlaceholder = "{key}"
+
+// templateValue returns the runtime value of a placeholder name
+func templateValue(name string, runtimeConfig *config.RuntimeConfig) (string, bool) {
+	if alias, ok := strings.CutPrefix(name, "models."); ok {
+		return runtimeConfig.Models[provider.CanonicalModelAlias(alias)], true
+	}
+
+	switch name {
+	case "key":
+		return runtimeConfig.APIKey, true
+	case "base_url":
+		return runtimeConfig.BaseURL, true
+	case "model":
+		return runtimeConfig.Model, true
+	case "provider":
+		return runtimeConfig.Provider, true
+	case "profile":
+		return runtimeConfig.Profile, true
+	case "timeout":
+		if runtimeConfig.Timeout > 0 {
+			return fmt.Sprintf("%d", runtimeConfig.Timeout.Milliseconds()), true
+		}
+		return "", true
+	case "env_key":
+		return runtimeConfig.EnvKeyName, true
+	case "display_name":
+		if runtimeConfig.DisplayName != "" {
+			return runtimeConfig.DisplayName, true
+		}
+		return runtimeConfig.Provider, true
+	case "wire_api":
+		return runtimeConfig.WireAPI, true
+	}
+	return "", false
+}
+
+// ExpandTemplate substitutes runtime values into a template
+// Unknown placeholders are kept as written. It returns false when a known placeholder has no value.
+func ExpandTemplate(tmpl string, runtimeConfig *config.RuntimeConfig) (string, bool) {
+	return expandTemplate(tmpl, runtimeConfig, true)
+}
+
+// expandTemplate substitutes runtime values, leaving {key} as written unless withKey is set
+func expandTemplate(tmpl string, runtimeConfig *config.RuntimeConfig, withKey bool) (string, bool) {
+	complete := true
+	expanded := templatePlaceholder.ReplaceAllStringFunc(tmpl, func(ref string) string {
+		if ref == KeyPlaceholder && !withKey {
+			return ref
+		}
+		value, known := templateValue(ref[1:len(ref)-1], runtimeConfig)
+		if !known {
+			return ref
+		}
+		if value == "" {
+			complete = false
+		}
+		return value
+	})
+	return expanded, complete
+}
+
+// ExpandArgs turns argument templates into command line arguments
+// Each template is split on whitespace first, so "--model {model}" becomes two arguments,
+// and is left out entirely when one of its placeholders has no value.
+// {key} is never expanded, so the key does not show up in the process list.
+func ExpandArgs(templates []string, runtimeConfig *config.RuntimeConfig) []string {
+	var args []string
+	for _, tmpl := range templates {
+		fields := strings.Fields(tmpl)
+		expanded := make([]string, 0, len(fields))
+		complete := true
+		for _, field := range fields {
+			value, ok := expandTemplate(field, runtimeConfig, false)
+			complete = complete && ok
+			expanded = append(expanded, value)
+		}
+		if complete {
+			args = append(args, expanded...)
+		}
+	}
+	return args
+}
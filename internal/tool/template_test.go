@@ -0,0 +1,22 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fakecore/aim/internal/config"
+)
+
+func TestExpandArgsKeepsKeyOffCommandLine(t *testing.T) {
+	runtime := &config.RuntimeConfig{APIKey: "sk-secret", Model: "m1", BaseURL: "https://api.example.com"}
+
+	args := ExpandArgs([]string{"--model {model}", "--api-key {key}", "--url={base_url}"}, runtime)
+	want := []string{"--model", "m1", "--api-key", "{key}", "--url=https://api.example.com"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("ExpandArgs = %v, want %v", args, want)
+	}
+
+	if value, ok := ExpandTemplate("Bearer {key}", runtime); !ok || value != "Bearer sk-secret" {
+		t.Errorf("ExpandTemplate = %q, %v; install templates still expand {key}", value, ok)
+	}
+}
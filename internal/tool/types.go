@@ -1,6 +1,11 @@
 package tool
 
-import "github.com/fakecore/aim/internal/config"
+import (
+	"sort"
+	"strings"
+
+	"github.com/fakecore/aim/internal/config"
+)
 
 // ToolType tool type enumeration
 type ToolType string
@@ -9,6 +14,18 @@ const (
 	ToolTypeClaudeCode ToolType = "claude-code"
 	ToolTypeCC         ToolType = "cc"
 	ToolTypeCodex      ToolType = "codex"
+	ToolTypeAider      ToolType = "aider"
+	ToolTypeGeminiCLI  ToolType = "gemini-cli"
+	ToolTypeQwenCode   ToolType = "qwen-code"
+	ToolTypeOpenCode   ToolType = "opencode"
+)
+
+// Protocol identifies the API wire protocol a tool speaks
+type Protocol string
+
+const (
+	ProtocolAnthropic Protocol = "anthropic" // Anthropic Messages API (/v1/messages)
+	ProtocolOpenAI    Protocol = "openai"    // OpenAI Chat Completions API (/chat/completions)
 )
 
 // ToolConfig tool configuration structure
@@ -17,25 +34,61 @@ type ToolConfig struct {
 	Aliases     []string
 	Canonical   string // canonical name
 	Description string
+	Protocol    Protocol             // API protocol the tool uses against provider endpoints
+	Args        []string             // Argument templates declared in config, see ExpandArgs
+	Preparer    *config.ToolPreparer // External preparer declared in config, see PluginPreparer
 }
 
 // ToolsRegistry tool registry, centrally manages all tool information
-var ToolsRegistry = map[ToolType]ToolConfig{
-	ToolTypeClaudeCode: {
-		Type:      ToolTypeClaudeCode,
-		Aliases:   []string{"cc"},
-		Canonical: string(ToolTypeClaudeCode),
-	},
-	ToolTypeCC: {
-		Type:      ToolTypeCC,
-		Aliases:   []string{},
-		Canonical: string(ToolTypeClaudeCode), // cc is an alias for claude-code, pointing to claude-code
-	},
-	ToolTypeCodex: {
-		Type:      ToolTypeCodex,
-		Aliases:   []string{},
-		Canonical: string(ToolTypeCodex),
-	},
+// It starts with the builtin tools; RegisterConfigTools adds the tools declared in config.
+var ToolsRegistry = builtinToolsRegistry()
+
+// builtinToolsRegistry returns the definitions of the tools aim ships with
+func builtinToolsRegistry() map[ToolType]ToolConfig {
+	return map[ToolType]ToolConfig{
+		ToolTypeClaudeCode: {
+			Type:      ToolTypeClaudeCode,
+			Aliases:   []string{"cc"},
+			Canonical: string(ToolTypeClaudeCode),
+			Protocol:  ProtocolAnthropic,
+		},
+		ToolTypeCC: {
+			Type:      ToolTypeCC,
+			Aliases:   []string{},
+			Canonical: string(ToolTypeClaudeCode), // cc is an alias for claude-code, pointing to claude-code
+			Protocol:  ProtocolAnthropic,
+		},
+		ToolTypeCodex: {
+			Type:      ToolTypeCodex,
+			Aliases:   []string{},
+			Canonical: string(ToolTypeCodex),
+			Protocol:  ProtocolOpenAI,
+		},
+		ToolTypeAider: {
+			Type:      ToolTypeAider,
+			Aliases:   []string{},
+			Canonical: string(ToolTypeAider),
+			Protocol:  ProtocolOpenAI,
+		},
+		ToolTypeGeminiCLI: {
+			Type:      ToolTypeGeminiCLI,
+			Aliases:   []string{"gemini"},
+			Canonical: string(ToolTypeGeminiCLI),
+			// Gemini API only; there is no connectivity probe for it
+		},
+		ToolTypeQwenCode: {
+			Type:      ToolTypeQwenCode,
+			Aliases:   []string{"qwen"},
+			Canonical: string(ToolTypeQwenCode),
+			Protocol:  ProtocolOpenAI,
+		},
+		ToolTypeOpenCode: {
+			Type:      ToolTypeOpenCode,
+			Aliases:   []string{},
+			Canonical: string(ToolTypeOpenCode),
+			Protocol:  ProtocolOpenAI,
+		},
+	}
 }
 
 // SupportedTools list of supported tools (generated from registry)
@@ -44,6 +97,61 @@ var SupportedTools = getSupportedTools()
 // ToolAliases tool alias mapping (generated from registry)
 var ToolAliases = getToolAliases()
 
+// RegisterConfigTools adds the tools declared in config to the registry
+// Builtin tools keep their definitions; aliases and args declared for them are merged in.
+func RegisterConfigTools(cfg *config.Config) {
+	registry := builtinToolsRegistry()
+	for name, declared := range cfg.Tools {
+		if declared == nil {
+			continue
+		}
+		def, builtin := registry[ToolType(name)]
+		if !builtin {
+			def = ToolConfig{
+				Type:      ToolType(name),
+				Canonical: name,
+				Protocol:  declaredProtocol(cfg, declared),
+			}
+		}
+		def.Aliases = append(append([]string{}, def.Aliases...), declared.Aliases...)
+		def.Args = declared.Args
+		def.Preparer = declared.Preparer
+		registry[ToolType(name)] = def
+	}
+
+	ToolsRegistry = registry
+	SupportedTools = getSupportedTools()
+	ToolAliases = getToolAliases()
+}
+
+// declaredProtocol returns a config tool's protocol, falling back to the tool its profiles come from
+func declaredProtocol(cfg *config.Config, declared *config.ToolConfig) Protocol {
+	if declared.Protocol != "" {
+		return Protocol(declared.Protocol)
+	}
+	if def, ok := builtinToolsRegistry()[ToolType(declared.ProfilesFrom)]; ok {
+		return def.Protocol
+	}
+	return ""
+}
+
+// SupportedToolNames lists the canonical tool names with their aliases, e.g. "claude-code (cc)"
+func SupportedToolNames() string {
+	names := make([]string, 0, len(ToolsRegistry))
+	for toolType, def := range ToolsRegistry {
+		if string(toolType) != def.Canonical {
+			continue
+		}
+		name := def.Canonical
+		if len(def.Aliases) > 0 {
+			name += " (" + strings.Join(def.Aliases, ", ") + ")"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
 // getSupportedTools generates list of supported tools from registry
 func getSupportedTools() []string {
 	tools := make([]string, 0, len(ToolsRegistry))
@@ -127,3 +235,12 @@ func GetToolConfig(toolType ToolType) (ToolConfig, bool) {
 	config, exists := ToolsRegistry[toolType]
 	return config, exists
 }
+
+// GetProtocol gets the API protocol of a tool (handles aliases)
+func GetProtocol(toolName string) (Protocol, bool) {
+	toolType, ok := GetToolType(toolName)
+	if !ok || ToolsRegistry[toolType].Protocol == "" {
+		return "", false
+	}
+	return ToolsRegistry[toolType].Protocol, true
+}